@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+const commandUsage = `usage: sole-server [command]
+
+commands:
+  create-admin <email> [role]    grant role, superadmin by default, to registered user`
+
+// run subcommand instead of serving http if any
+func runCommand(args []string) error {
+	switch {
+	case len(args) >= 2 && len(args) <= 3 && args[0] == "create-admin":
+		role := "superadmin"
+		if len(args) == 3 {
+			role = args[2]
+		}
+		return createAdmin(args[1], role)
+	default:
+		return fmt.Errorf(commandUsage)
+	}
+}
+
+// bootstrap the first admin, who can grant roles to others with api afterwards
+func createAdmin(email, role string) error {
+	user, err := store.GetUserByEmail(email)
+	if err != nil {
+		return fmt.Errorf("get user %v error: %v", email, err)
+	}
+
+	if err := store.UpdateUserRole(user.ID, role); err != nil {
+		return fmt.Errorf("grant role %v to user %v error: %v", role, email, err)
+	}
+
+	permissions := must(store.GetRolePermissions()).(models.RolePermissions)
+	fmt.Printf("user %v (id %v) is granted role %v with permissions %v\n", email, user.ID, role, permissions[role])
+	return nil
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"gopkg.in/go-playground/validator.v8"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/solefaucet/sole-server/models"
 	"github.com/spf13/viper"
 )
 
@@ -16,6 +19,31 @@ type graylog struct {
 	Facility string `mapstructure:"facility" validate:"required"`
 }
 
+type ethereumConfig struct {
+	RPCURL   string
+	Address  string
+	GasPrice int64 // in gwei, 0 means gas price suggested by node
+}
+
+// currency that balances are kept in, each one is paid out by its own wallet
+type currencyConfig struct {
+	Code           string  `validate:"required,eq=btc|eq=doge|eq=ltc|eq=dash|eq=eth|eq=alipay"`
+	ExchangeRate   float64 `validate:"gt=0"`  // units of currency per unit of base currency
+	RewardInterval int64   `validate:"min=0"` // 0 means user's reward interval
+	TxExplorer     string  `validate:"required"`
+	RPCHost        string
+	RPCUser        string
+	RPCPass        string
+	Ethereum       ethereumConfig // eth only
+	// addresses are validated offline for network given, and optionally by wallet node as well
+	Testnet                 bool
+	ValidateAddressWithNode bool
+	// fee is estimated for confirmation within blocks given, payout is postponed
+	// while estimated network fee per withdrawal exceeds max fee, 0 means no ceiling
+	FeeConfTarget       int64   `validate:"min=0"` // 0 means fee configured in wallet, required by nodes before bitcoin core 0.15
+	MaxFeePerWithdrawal float64 `validate:"min=0"`
+}
+
 type configuration struct {
 	App struct {
 		Name string `validate:"required"`
@@ -46,11 +74,26 @@ type configuration struct {
 		NumCachedIncomes int `validate:"required,gt=1"`
 	} `validate:"required"`
 	Template struct {
-		EmailVerificationTemplate string `validate:"required"`
+		EmailVerificationTemplate   string `validate:"required"`
+		AddressVerificationTemplate string `validate:"required"`
+		WithdrawalRejectionTemplate string `validate:"required"`
+		WalletAlertTemplate         string `validate:"required"`
 	} `validate:"required"`
 	Coin struct {
 		TxExplorer string `validate:"required"`
 		Type       string `validate:"required,eq=btc|eq=doge|eq=ltc|eq=dash|eq=eth|eq=alipay"`
+		RPCHost    string
+		RPCUser    string
+		RPCPass    string
+	} `validate:"required"`
+	Currencies []currencyConfig `validate:"required,min=1,dive"` // base currency, i.e. coin type, first
+	Payout     struct {
+		MaxOutputs    int           `validate:"min=0"` // 0 means no limit
+		Confirmations int64         `validate:"required,min=1"`
+		StuckAfter    time.Duration `validate:"required"`
+		MinRunwayDays float64       `validate:"min=0"` // 0 means no alert
+		AlertEmails   string        // comma separated
+		AlertInterval time.Duration `validate:"required"`
 	} `validate:"required"`
 	Geetest struct {
 		CaptchaID  string `validate:"required"`
@@ -106,6 +149,13 @@ func initConfig() {
 	// set default
 	viper.SetDefault("cronjob_spec_create_withdrawal", "@daily")
 	viper.SetDefault("cronjob_spec_process_withdrawal", "@every 30m")
+	viper.SetDefault("coin_rpc_host", "localhost:8332")
+	viper.SetDefault("eth_rpc_url", "http://localhost:8545")
+	viper.SetDefault("payout_max_outputs", 100)
+	viper.SetDefault("payout_confirmations", 6)
+	viper.SetDefault("payout_stuck_after", "6h")
+	viper.SetDefault("payout_min_runway_days", 3)
+	viper.SetDefault("payout_alert_interval", "6h")
 
 	// See Viper doc, config is get in the following order
 	// override, flag, env, config file, key/value store, default
@@ -133,9 +183,63 @@ func initConfig() {
 	config.Cache.NumCachedIncomes = viper.GetInt("num_cached_incomes")
 
 	config.Template.EmailVerificationTemplate = viper.GetString("email_verification_template")
+	config.Template.AddressVerificationTemplate = viper.GetString("address_verification_template")
+	config.Template.WithdrawalRejectionTemplate = viper.GetString("withdrawal_rejection_template")
+	config.Template.WalletAlertTemplate = viper.GetString("wallet_alert_template")
 
 	config.Coin.TxExplorer = viper.GetString("tx_explorer")
 	config.Coin.Type = viper.GetString("coin_type")
+	config.Coin.RPCHost = viper.GetString("coin_rpc_host")
+	config.Coin.RPCUser = viper.GetString("coin_rpc_user")
+	config.Coin.RPCPass = viper.GetString("coin_rpc_pass")
+
+	// base currency is coin type, others are configured with their code as prefix, e.g. SOLE_DOGE_RPC_HOST
+	config.Currencies = []currencyConfig{{
+		Code:           config.Coin.Type,
+		ExchangeRate:   1,
+		RewardInterval: int64(viper.GetInt("coin_reward_interval")),
+		TxExplorer:     config.Coin.TxExplorer,
+		RPCHost:        config.Coin.RPCHost,
+		RPCUser:        config.Coin.RPCUser,
+		RPCPass:        config.Coin.RPCPass,
+		Ethereum: ethereumConfig{
+			RPCURL:   viper.GetString("eth_rpc_url"),
+			Address:  viper.GetString("eth_address"),
+			GasPrice: int64(viper.GetInt("eth_gas_price")),
+		},
+
+		Testnet:                 viper.GetBool("coin_testnet"),
+		ValidateAddressWithNode: viper.GetBool("coin_validate_address_with_node"),
+
+		FeeConfTarget:       int64(viper.GetInt("payout_fee_conf_target")),
+		MaxFeePerWithdrawal: viper.GetFloat64("payout_max_fee"),
+	}}
+	for _, v := range strings.Split(viper.GetString("currencies"), ",") {
+		if code := strings.TrimSpace(v); code != "" {
+			config.Currencies = append(config.Currencies, currencyConfig{
+				Code:           code,
+				ExchangeRate:   viper.GetFloat64(code + "_exchange_rate"),
+				RewardInterval: int64(viper.GetInt(code + "_reward_interval")),
+				TxExplorer:     viper.GetString(code + "_tx_explorer"),
+				RPCHost:        viper.GetString(code + "_rpc_host"),
+				RPCUser:        viper.GetString(code + "_rpc_user"),
+				RPCPass:        viper.GetString(code + "_rpc_pass"),
+
+				Testnet:                 viper.GetBool(code + "_testnet"),
+				ValidateAddressWithNode: viper.GetBool(code + "_validate_address_with_node"),
+
+				FeeConfTarget:       int64(viper.GetInt(code + "_payout_fee_conf_target")),
+				MaxFeePerWithdrawal: viper.GetFloat64(code + "_payout_max_fee"),
+			})
+		}
+	}
+
+	config.Payout.MaxOutputs = viper.GetInt("payout_max_outputs")
+	config.Payout.Confirmations = int64(viper.GetInt("payout_confirmations"))
+	config.Payout.StuckAfter = must(time.ParseDuration(viper.GetString("payout_stuck_after"))).(time.Duration)
+	config.Payout.MinRunwayDays = viper.GetFloat64("payout_min_runway_days")
+	config.Payout.AlertEmails = viper.GetString("payout_alert_emails")
+	config.Payout.AlertInterval = must(time.ParseDuration(viper.GetString("payout_alert_interval"))).(time.Duration)
 
 	config.Geetest.CaptchaID = viper.GetString("geetest_captcha_id")
 	config.Geetest.PrivateKey = viper.GetString("geetest_private_key")
@@ -166,7 +270,47 @@ func initConfig() {
 func validateConfiguration(c configuration) error {
 	validate := validator.New(&validator.Config{TagName: "validate"})
 	must(nil, validate.RegisterValidation("dsn", dsnValidator))
-	return validate.Struct(c)
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+
+	return validateCurrencies(c.Currencies)
+}
+
+// additional currencies are paid out by bitcoind compatible wallets
+func validateCurrencies(currencies []currencyConfig) error {
+	seen := map[string]bool{}
+	for i, c := range currencies {
+		if seen[c.Code] {
+			return fmt.Errorf("currency %v is configured more than once", c.Code)
+		}
+		seen[c.Code] = true
+
+		if i > 0 && (c.Code == models.CoinTypeEthereum || c.Code == models.CoinTypeAlipay) {
+			return fmt.Errorf("currency %v can only be coin type", c.Code)
+		}
+	}
+
+	return nil
+}
+
+// currencies enabled in deployment, base currency first
+func (c configuration) currencies() models.Currencies {
+	currencies := make(models.Currencies, len(c.Currencies))
+	for i, v := range c.Currencies {
+		currencies[i] = models.Currency{Code: v.Code, ExchangeRate: v.ExchangeRate, RewardInterval: v.RewardInterval}
+	}
+	return currencies
+}
+
+// config of currency with code given
+func (c configuration) currency(code string) (currencyConfig, bool) {
+	for _, v := range c.Currencies {
+		if v.Code == code {
+			return v, true
+		}
+	}
+	return currencyConfig{}, false
 }
 
 func dsnValidator(v *validator.Validate, topStruct reflect.Value, currentStructOrField reflect.Value, field reflect.Value, fieldType reflect.Type, fieldKind reflect.Kind, param string) bool {
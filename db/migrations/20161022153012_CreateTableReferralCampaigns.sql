@@ -0,0 +1,22 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `referral_campaigns` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `user_id` INT(11) NOT NULL COMMENT 'referer who owns the campaign',
+  `code` VARCHAR(31) NOT NULL COMMENT 'unique referral code used in campaign links',
+  `name` VARCHAR(63) NOT NULL DEFAULT '' COMMENT 'name of banner, site or campaign',
+  `clicks` INT(11) NOT NULL DEFAULT 0 COMMENT 'number of clicks on campaign link',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `referral_campaigns`
+ADD UNIQUE INDEX (`code`),
+ADD INDEX (`user_id`),
+ADD INDEX (`created_at`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `referral_campaigns`;
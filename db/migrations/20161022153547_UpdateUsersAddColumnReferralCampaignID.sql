@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `users` ADD COLUMN `referral_campaign_id` INT(11) NOT NULL DEFAULT 0 COMMENT 'referral campaign the user signed up with, default no campaign';
+
+ALTER TABLE `users` ADD INDEX (`referral_campaign_id`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users` DROP COLUMN `referral_campaign_id`;
@@ -0,0 +1,29 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `levels` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `level` INT(11) NOT NULL COMMENT 'unique level number',
+  `min_xp` INT(11) NOT NULL COMMENT 'minimum xp to reach this level',
+  `reward_interval` SMALLINT(6) NOT NULL DEFAULT 0 COMMENT 'reward interval in seconds of this level, 0 means users.reward_interval',
+  `reward_multiplier` DECIMAL(6, 4) NOT NULL DEFAULT 1 COMMENT 'multiplier applied to reward',
+  `referer_reward_rate` DECIMAL(4, 4) NOT NULL DEFAULT 0 COMMENT 'referer reward rate of this level, 0 means configs.referer_reward_rate',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `levels`
+ADD UNIQUE INDEX (`level`),
+ADD UNIQUE INDEX (`min_xp`);
+
+INSERT INTO `levels` (`level`, `min_xp`, `reward_interval`, `reward_multiplier`, `referer_reward_rate`) VALUES
+(1, 0, 0, 1, 0),
+(2, 100, 840, 1.05, 0),
+(3, 500, 780, 1.1, 0.12),
+(4, 2000, 720, 1.2, 0.15),
+(5, 10000, 600, 1.3, 0.2);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `levels`;
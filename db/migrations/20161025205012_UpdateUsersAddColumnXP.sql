@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `users` ADD COLUMN `xp` INT(11) NOT NULL DEFAULT 0 COMMENT 'experience earned from rewards and offerwalls';
+
+ALTER TABLE `users` ADD INDEX (`xp`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users` DROP COLUMN `xp`;
@@ -0,0 +1,25 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `streak_bonuses` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `days` INT(11) NOT NULL COMMENT 'minimum consecutive days with at least one claim',
+  `multiplier` DECIMAL(6, 4) NOT NULL COMMENT 'multiplier applied to reward',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `streak_bonuses`
+ADD UNIQUE INDEX (`days`);
+
+INSERT INTO `streak_bonuses` (`days`, `multiplier`) VALUES
+(2, 1.05),
+(3, 1.1),
+(7, 1.2),
+(14, 1.3),
+(30, 1.5);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `streak_bonuses`;
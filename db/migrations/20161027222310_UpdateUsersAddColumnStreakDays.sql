@@ -0,0 +1,8 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `users` ADD COLUMN `streak_days` INT(11) NOT NULL DEFAULT 0 COMMENT 'consecutive days with at least one claim till rewarded_at';
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users` DROP COLUMN `streak_days`;
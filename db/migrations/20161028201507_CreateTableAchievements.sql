@@ -0,0 +1,29 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `achievements` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(31) NOT NULL COMMENT 'unique identifier of achievement',
+  `title` VARCHAR(63) NOT NULL,
+  `metric` VARCHAR(31) NOT NULL COMMENT 'rewards, offerwalls, verified_referees or withdrawals',
+  `threshold` INT(11) NOT NULL COMMENT 'achievement is unlocked when metric reaches threshold',
+  `bonus` DECIMAL(16, 8) NOT NULL DEFAULT 0 COMMENT 'one-off bonus credited on unlocking',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `achievements`
+ADD UNIQUE INDEX (`name`);
+
+INSERT INTO `achievements` (`name`, `title`, `metric`, `threshold`, `bonus`) VALUES
+('first_reward', 'First claim', 'rewards', 1, 0),
+('rewards_100', '100 claims', 'rewards', 100, 0.00001),
+('rewards_1000', '1000 claims', 'rewards', 1000, 0.0001),
+('first_offerwall', 'First offer completed', 'offerwalls', 1, 0.00001),
+('verified_referees_10', '10 verified referees', 'verified_referees', 10, 0.0001),
+('first_withdrawal', 'First withdrawal', 'withdrawals', 1, 0);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `achievements`;
@@ -0,0 +1,23 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `fair_seeds` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `user_id` INT(11) NOT NULL,
+  `server_seed` CHAR(64) NOT NULL COMMENT 'kept secret until seed is revealed',
+  `server_seed_hash` CHAR(64) NOT NULL COMMENT 'sha256 of server_seed, committed to user',
+  `client_seed` VARCHAR(64) NOT NULL,
+  `nonce` INT(11) NOT NULL DEFAULT 0 COMMENT 'nonce of next claim',
+  `status` VARCHAR(15) NOT NULL DEFAULT 'active' COMMENT 'active or revealed',
+  `revealed_at` DATETIME NULL DEFAULT NULL,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `fair_seeds`
+ADD INDEX (`user_id`, `status`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `fair_seeds`;
@@ -0,0 +1,11 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `reward_rates`
+ADD COLUMN `scope` VARCHAR(31) NOT NULL DEFAULT '' COMMENT 'empty for global, ISO country code (e.g. US) or country group name (e.g. tier1)' AFTER `type`,
+ADD INDEX (`scope`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `reward_rates`
+DROP COLUMN `scope`;
@@ -0,0 +1,19 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `country_groups` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(31) NOT NULL COMMENT 'group name used as reward_rates.scope, should not collide with country codes',
+  `country` CHAR(2) NOT NULL COMMENT 'ISO country code',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `country_groups`
+ADD UNIQUE INDEX (`country`),
+ADD INDEX (`name`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `country_groups`;
@@ -0,0 +1,36 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `reward_rules` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(63) NOT NULL,
+  `description` VARCHAR(255) NOT NULL DEFAULT '',
+  `priority` INT(11) NOT NULL DEFAULT 0 COMMENT 'rules are applied in ascending order of priority',
+  `multiplier` DECIMAL(6, 4) NOT NULL DEFAULT 1 COMMENT 'multiplier applied to reward',
+  `starts_at` DATETIME NULL DEFAULT NULL COMMENT 'UTC, NULL means no start date',
+  `ends_at` DATETIME NULL DEFAULT NULL COMMENT 'UTC, exclusive, NULL means no end date',
+  `weekdays` VARCHAR(15) NOT NULL DEFAULT '' COMMENT 'comma separated weekdays in UTC starting from sunday 0, empty means every day',
+  `start_hour` TINYINT(4) NOT NULL DEFAULT 0 COMMENT 'UTC hour of day, inclusive',
+  `end_hour` TINYINT(4) NOT NULL DEFAULT 0 COMMENT 'UTC hour of day, exclusive, equal to start_hour means all day',
+  `max_account_days` INT(11) NOT NULL DEFAULT 0 COMMENT 'only users registered within days, 0 means no limit',
+  `min_level` INT(11) NOT NULL DEFAULT 0 COMMENT '0 means no limit',
+  `max_level` INT(11) NOT NULL DEFAULT 0 COMMENT '0 means no limit',
+  `apply_to_referer` TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'multiply referer reward as well',
+  `final` TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'stop applying following rules once matched',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `reward_rules`
+ADD INDEX (`priority`),
+ADD INDEX (`ends_at`);
+
+-- migrate configs.double_on_weekday of latest config
+INSERT INTO `reward_rules` (`name`, `description`, `multiplier`, `weekdays`)
+SELECT 'Double rewards', 'Rewards are doubled today', 2, CAST(`double_on_weekday` AS CHAR)
+FROM `configs` WHERE `id` = (SELECT MAX(`id`) FROM `configs`) AND `double_on_weekday` >= 0;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `reward_rules`;
@@ -0,0 +1,20 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `admin_adjustments` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `user_id` INT(11) NOT NULL,
+  `income_id` INT(11) NOT NULL COMMENT 'admin type income created by adjustment',
+  `amount` DECIMAL(19, 8) NOT NULL COMMENT 'negative amount deducts user balance',
+  `reason` VARCHAR(255) NOT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `admin_adjustments`
+ADD UNIQUE INDEX (`income_id`),
+ADD INDEX (`user_id`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `admin_adjustments`;
@@ -0,0 +1,14 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `configs`
+ADD COLUMN `review_amount` DECIMAL(19, 8) NOT NULL DEFAULT 0 COMMENT 'withdrawals above amount are reviewed, 0 means no limit' AFTER `min_withdrawal_amount`,
+ADD COLUMN `review_account_days` INT(11) NOT NULL DEFAULT 0 COMMENT 'withdrawals of users registered within days are reviewed, 0 means no limit' AFTER `review_amount`,
+ADD COLUMN `review_address_days` INT(11) NOT NULL DEFAULT 0 COMMENT 'withdrawals to address changed within days are reviewed, 0 means no limit' AFTER `review_account_days`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `configs`
+DROP COLUMN `review_amount`,
+DROP COLUMN `review_account_days`,
+DROP COLUMN `review_address_days`;
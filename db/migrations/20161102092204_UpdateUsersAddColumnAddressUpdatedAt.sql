@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `users`
+ADD COLUMN `address_updated_at` DATETIME NULL DEFAULT NULL COMMENT 'NULL means address is never changed' AFTER `address`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users`
+DROP COLUMN `address_updated_at`;
@@ -0,0 +1,14 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `withdrawals`
+MODIFY COLUMN `status` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '0: pending, 1: processing 2: processed 3: review 4: rejected',
+ADD COLUMN `review_reason` VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'review rules matched when withdrawal is created' AFTER `transaction_id`,
+ADD COLUMN `reject_reason` VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'reason sent to user when withdrawal is rejected' AFTER `review_reason`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `withdrawals`
+MODIFY COLUMN `status` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '0: pending, 1: processing 2: processed',
+DROP COLUMN `review_reason`,
+DROP COLUMN `reject_reason`;
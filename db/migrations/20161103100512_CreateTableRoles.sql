@@ -0,0 +1,44 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `roles` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(31) NOT NULL,
+  `description` VARCHAR(255) NOT NULL DEFAULT '',
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `roles`
+ADD UNIQUE INDEX (`name`);
+
+CREATE TABLE `role_permissions` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `role_id` INT(11) NOT NULL,
+  `permission` VARCHAR(63) NOT NULL COMMENT '* grants every permission',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `role_permissions`
+ADD UNIQUE INDEX (`role_id`, `permission`);
+
+INSERT INTO `roles` (`id`, `name`, `description`) VALUES
+(1, 'support', 'look up users, ban and unban'),
+(2, 'finance', 'review withdrawals, adjust balances, view stats'),
+(3, 'superadmin', 'everything, including configs and roles');
+
+INSERT INTO `role_permissions` (`role_id`, `permission`) VALUES
+(1, 'users.read'),
+(1, 'users.write'),
+(1, 'withdrawals.read'),
+(2, 'users.read'),
+(2, 'balances.write'),
+(2, 'withdrawals.read'),
+(2, 'withdrawals.review'),
+(2, 'stats.read'),
+(3, '*');
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `role_permissions`;
+DROP TABLE `roles`;
@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `users`
+ADD COLUMN `role` VARCHAR(31) NOT NULL DEFAULT '' COMMENT 'name of role, empty means no admin permission' AFTER `status`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users`
+DROP COLUMN `role`;
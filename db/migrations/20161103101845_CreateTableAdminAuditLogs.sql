@@ -0,0 +1,21 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `admin_audit_logs` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `user_id` INT(11) NOT NULL COMMENT 'admin who sent the request',
+  `action` VARCHAR(63) NOT NULL COMMENT 'method and route of request, e.g. POST /v1/admin/users/:id/ban',
+  `target` VARCHAR(255) NOT NULL COMMENT 'request uri, e.g. /v1/admin/users/1/ban',
+  `status_code` SMALLINT(6) NOT NULL,
+  `ip` VARCHAR(45) NOT NULL DEFAULT '',
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `admin_audit_logs`
+ADD INDEX (`user_id`),
+ADD INDEX (`created_at`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `admin_audit_logs`;
@@ -0,0 +1,14 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `users`
+ADD COLUMN `auto_withdrawal` TINYINT(1) NOT NULL DEFAULT 1 COMMENT 'whether balance is swept by daily withdrawal cronjob' AFTER `balance`,
+ADD COLUMN `totp_secret` VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'base32 encoded TOTP secret' AFTER `auto_withdrawal`,
+ADD COLUMN `totp_enabled` TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'whether withdrawal requires TOTP code' AFTER `totp_secret`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users`
+DROP COLUMN `auto_withdrawal`,
+DROP COLUMN `totp_secret`,
+DROP COLUMN `totp_enabled`;
@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `configs`
+ADD COLUMN `withdrawal_interval` INT(11) NOT NULL DEFAULT 86400 COMMENT 'seconds between withdrawals requested by user, 0 means no limit' AFTER `min_withdrawal_amount`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `configs`
+DROP COLUMN `withdrawal_interval`;
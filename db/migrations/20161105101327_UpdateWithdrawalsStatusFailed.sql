@@ -0,0 +1,12 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `withdrawals`
+MODIFY COLUMN `status` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '0: pending, 1: processing 2: processed 3: review 4: rejected 5: failed',
+MODIFY COLUMN `reject_reason` VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'reason sent to user when withdrawal is rejected or failed to pay out';
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `withdrawals`
+MODIFY COLUMN `status` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '0: pending, 1: processing 2: processed 3: review 4: rejected',
+MODIFY COLUMN `reject_reason` VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'reason sent to user when withdrawal is rejected';
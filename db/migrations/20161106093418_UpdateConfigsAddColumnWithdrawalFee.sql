@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `configs`
+ADD COLUMN `withdrawal_fee` DECIMAL(19, 8) NOT NULL DEFAULT 0 COMMENT 'fee charged to user per withdrawal, deducted from amount sent' AFTER `withdrawal_interval`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `configs`
+DROP COLUMN `withdrawal_fee`;
@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `withdrawals`
+ADD COLUMN `fee` DECIMAL(19, 8) NOT NULL DEFAULT 0 COMMENT 'fee charged to user, amount - fee is sent' AFTER `amount`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `withdrawals`
+DROP COLUMN `fee`;
@@ -0,0 +1,18 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `withdrawals`
+ADD COLUMN `confirmations` INT(11) NOT NULL DEFAULT 0 COMMENT 'confirmations of transaction, negative if transaction conflicts' AFTER `transaction_id`,
+ADD COLUMN `block_time` DATETIME NULL DEFAULT NULL COMMENT 'time of block the transaction is mined in' AFTER `confirmations`,
+ADD COLUMN `stuck` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '1: transaction is not confirmed in time or conflicts' AFTER `block_time`,
+ADD COLUMN `processed_at` DATETIME NULL DEFAULT NULL COMMENT 'time transaction is sent' AFTER `reject_reason`,
+ADD INDEX (`transaction_id`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `withdrawals`
+DROP INDEX `transaction_id`,
+DROP COLUMN `processed_at`,
+DROP COLUMN `stuck`,
+DROP COLUMN `block_time`,
+DROP COLUMN `confirmations`;
@@ -0,0 +1,19 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `payout_batches` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `batch_id` VARCHAR(63) NOT NULL COMMENT 'derived from withdrawal ids, attached to transaction comment',
+  `status` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '0: sending, 1: sent, 2: failed',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `payout_batches`
+ADD UNIQUE INDEX (`batch_id`),
+ADD INDEX (`status`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `payout_batches`;
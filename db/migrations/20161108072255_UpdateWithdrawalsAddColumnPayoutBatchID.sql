@@ -0,0 +1,12 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `withdrawals`
+ADD COLUMN `payout_batch_id` VARCHAR(63) NOT NULL DEFAULT '' COMMENT 'payout batch last sending the withdrawal' AFTER `transaction_id`,
+ADD INDEX (`payout_batch_id`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `withdrawals`
+DROP INDEX `payout_batch_id`,
+DROP COLUMN `payout_batch_id`;
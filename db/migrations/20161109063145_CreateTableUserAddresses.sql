@@ -0,0 +1,27 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `user_addresses` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `user_id` INT(11) NOT NULL,
+  `address` VARCHAR(63) NOT NULL COMMENT 'unique across all users',
+  `label` VARCHAR(31) NOT NULL DEFAULT '',
+  `verify_token` VARCHAR(63) NOT NULL DEFAULT '' COMMENT 'sent by email, cleared once verified',
+  `verified` TINYINT(1) NOT NULL DEFAULT 0,
+  `is_default` TINYINT(1) NOT NULL DEFAULT 0 COMMENT 'mirrored to users.address, withdrawals are paid to it',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `user_addresses`
+ADD UNIQUE INDEX (`address`),
+ADD INDEX (`user_id`);
+
+-- addresses given at signup are trusted as verified default
+INSERT INTO `user_addresses` (`user_id`, `address`, `verified`, `is_default`)
+SELECT `id`, `address`, 1, 1 FROM `users`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `user_addresses`;
@@ -0,0 +1,31 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `user_balances` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `user_id` INT(11) NOT NULL,
+  `currency` VARCHAR(15) NOT NULL DEFAULT '' COMMENT 'coin type, empty for rows not labeled as base currency on startup yet',
+  `balance` DECIMAL(19, 8) NOT NULL DEFAULT 0,
+  `total_income` DECIMAL(32, 8) NOT NULL DEFAULT 0,
+  `total_income_from_referees` DECIMAL(32, 8) NOT NULL DEFAULT 0,
+  `referer_total_income` DECIMAL(32, 8) NOT NULL DEFAULT 0,
+  `rewarded_at` DATETIME NOT NULL DEFAULT '1970-01-01 00:00:01' COMMENT 'last claim of faucet in the currency',
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `user_balances`
+ADD UNIQUE INDEX (`user_id`, `currency`),
+ADD INDEX (`currency`, `balance`);
+
+-- balances of single currency deployment, users.balance and totals are kept for rollback but no longer updated.
+-- deploy order: stop the old server, migrate, then start the new server, which labels copied rows
+-- with base currency on startup. incomes credited by an old server still running after this copy
+-- go to users.balance only and are lost to user_balances
+INSERT INTO `user_balances` (`user_id`, `balance`, `total_income`, `total_income_from_referees`, `referer_total_income`, `rewarded_at`)
+SELECT `id`, `balance`, `total_income`, `total_income_from_referees`, `referer_total_income`, `rewarded_at` FROM `users`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `user_balances`;
@@ -0,0 +1,12 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `incomes`
+ADD COLUMN `currency` VARCHAR(15) NOT NULL DEFAULT '' COMMENT 'coin type, empty for rows not labeled as base currency on startup yet' AFTER `type`,
+ADD INDEX (`currency`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `incomes`
+DROP INDEX `currency`,
+DROP COLUMN `currency`;
@@ -0,0 +1,12 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `withdrawals`
+ADD COLUMN `currency` VARCHAR(15) NOT NULL DEFAULT '' COMMENT 'coin type, empty for rows not labeled as base currency on startup yet' AFTER `user_id`,
+ADD INDEX (`currency`, `status`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `withdrawals`
+DROP INDEX `currency`,
+DROP COLUMN `currency`;
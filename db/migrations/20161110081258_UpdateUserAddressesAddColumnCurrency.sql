@@ -0,0 +1,12 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `user_addresses`
+ADD COLUMN `currency` VARCHAR(15) NOT NULL DEFAULT '' COMMENT 'coin type, empty for rows not labeled as base currency on startup yet' AFTER `user_id`,
+ADD INDEX (`currency`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `user_addresses`
+DROP INDEX `currency`,
+DROP COLUMN `currency`;
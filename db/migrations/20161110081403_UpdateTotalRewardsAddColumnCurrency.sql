@@ -0,0 +1,14 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `total_rewards`
+ADD COLUMN `currency` VARCHAR(15) NOT NULL DEFAULT '' COMMENT 'coin type, empty for rows not labeled as base currency on startup yet' AFTER `id`,
+DROP INDEX `created_at`,
+ADD UNIQUE INDEX (`currency`, `created_at`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `total_rewards`
+DROP INDEX `currency`,
+DROP COLUMN `currency`,
+ADD UNIQUE INDEX (`created_at`);
@@ -0,0 +1,20 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE `fair_rolls` (
+  `id` INT(11) NOT NULL AUTO_INCREMENT,
+  `fair_seed_id` INT(11) NOT NULL,
+  `nonce` INT(11) NOT NULL,
+  `currency` VARCHAR(15) NOT NULL,
+  `reward_rates` TEXT NOT NULL COMMENT 'json of reward rates used by the roll',
+  `reward` DECIMAL(16,8) NOT NULL COMMENT 'reward before multipliers are applied',
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `fair_rolls`
+ADD UNIQUE INDEX (`fair_seed_id`, `nonce`);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `fair_rolls`;
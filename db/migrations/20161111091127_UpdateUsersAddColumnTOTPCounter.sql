@@ -0,0 +1,10 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `users`
+ADD COLUMN `totp_counter` BIGINT(20) NOT NULL DEFAULT 0 COMMENT 'counter of the latest TOTP code used, codes of it or before are rejected' AFTER `totp_enabled`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users`
+DROP COLUMN `totp_counter`;
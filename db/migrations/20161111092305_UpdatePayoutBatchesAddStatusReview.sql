@@ -0,0 +1,18 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `payout_batches`
+MODIFY COLUMN `status` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '0: sending, 1: sent, 2: failed, 3: review';
+
+-- batches failed before with withdrawals still processing may have paid some of them,
+-- they are resolved with admin endpoints of payout batches
+UPDATE `payout_batches` SET `status` = 3 WHERE `status` = 2 AND `batch_id` IN (
+  SELECT `payout_batch_id` FROM `withdrawals` WHERE `status` = 1
+);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+UPDATE `payout_batches` SET `status` = 2 WHERE `status` = 3;
+
+ALTER TABLE `payout_batches`
+MODIFY COLUMN `status` TINYINT(4) NOT NULL DEFAULT 0 COMMENT '0: sending, 1: sent, 2: failed';
@@ -0,0 +1,12 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `admin_audit_logs`
+MODIFY COLUMN `target` VARCHAR(255) NOT NULL COMMENT 'route params of request, e.g. id=1',
+ADD COLUMN `body` TEXT NOT NULL COMMENT 'truncated json body of request, e.g. {"amount":1}' AFTER `target`;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `admin_audit_logs`
+MODIFY COLUMN `target` VARCHAR(255) NOT NULL COMMENT 'request uri, e.g. /v1/admin/users/1/ban',
+DROP COLUMN `body`;
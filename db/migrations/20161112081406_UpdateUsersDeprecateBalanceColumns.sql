@@ -0,0 +1,17 @@
+
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+-- balances and totals moved to user_balances, columns are kept for rollback of CreateTableUserBalances only
+ALTER TABLE `users`
+MODIFY COLUMN `balance` DECIMAL(19, 8) NOT NULL DEFAULT 0 COMMENT 'deprecated, moved to user_balances',
+MODIFY COLUMN `total_income` DECIMAL(32, 8) NOT NULL DEFAULT 0 COMMENT 'deprecated, moved to user_balances',
+MODIFY COLUMN `referer_total_income` DECIMAL(32, 8) NOT NULL DEFAULT 0 COMMENT 'deprecated, moved to user_balances',
+MODIFY COLUMN `total_income_from_referees` DECIMAL(32, 8) NOT NULL DEFAULT 0 COMMENT 'deprecated, moved to user_balances';
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `users`
+MODIFY COLUMN `balance` DECIMAL(19, 8) NOT NULL DEFAULT 0 COMMENT 'user account balance count',
+MODIFY COLUMN `total_income` DECIMAL(32, 8) NOT NULL DEFAULT 0 COMMENT 'user total income',
+MODIFY COLUMN `referer_total_income` DECIMAL(32, 8) NOT NULL DEFAULT 0 COMMENT 'referer total income',
+MODIFY COLUMN `total_income_from_referees` DECIMAL(32, 8) NOT NULL DEFAULT 0 COMMENT 'total income get from referees';
@@ -4,12 +4,29 @@ import "errors"
 
 // errors
 var (
-	ErrUnknown             = errors.New("unknown")
-	ErrNotFound            = errors.New("not found")
-	ErrInsufficientBalance = errors.New("insufficient balance")
-	ErrDuplicatedEmail     = errors.New("duplicated email")
-	ErrDuplicatedAddress   = errors.New("duplicated address")
-	ErrDuplicatedAuthToken = errors.New("duplicated auth token")
-	ErrInvalidAddress      = errors.New("invalid address")
-	ErrInvalidCaptcha      = errors.New("invalid captcha")
+	ErrUnknown                 = errors.New("unknown")
+	ErrNotFound                = errors.New("not found")
+	ErrInsufficientBalance     = errors.New("insufficient balance")
+	ErrDuplicatedEmail         = errors.New("duplicated email")
+	ErrDuplicatedAddress       = errors.New("duplicated address")
+	ErrDuplicatedAuthToken     = errors.New("duplicated auth token")
+	ErrDuplicatedReferralCode  = errors.New("duplicated referral code")
+	ErrInvalidAddress          = errors.New("invalid address")
+	ErrInvalidCaptcha          = errors.New("invalid captcha")
+	ErrInvalidRewardRates      = errors.New("invalid reward rates")
+	ErrUserBanned              = errors.New("user banned")
+	ErrUserNotBanned           = errors.New("user not banned")
+	ErrWithdrawalNotInReview   = errors.New("withdrawal not in review")
+	ErrWithdrawalNotProcessing = errors.New("withdrawal not processing")
+	ErrInvalidRole             = errors.New("invalid role")
+	ErrInvalidTOTPCode         = errors.New("invalid totp code")
+	ErrFairSeedChanged         = errors.New("fair seed changed")
+	ErrPayoutBatchExists       = errors.New("payout batch exists")
+	ErrPayoutBatchNotInReview  = errors.New("payout batch not in review")
+	ErrFindBatchNotSupported   = errors.New("find batch not supported")
+	ErrAddressNotVerified      = errors.New("address not verified")
+	ErrDefaultAddress          = errors.New("default address")
+	ErrTooManyAddresses        = errors.New("too many addresses")
+	ErrTooManyWithdrawals      = errors.New("too many withdrawals")
+	ErrInvalidCurrency         = errors.New("invalid currency")
 )
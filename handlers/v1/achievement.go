@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/models"
+)
+
+type achievementProgress struct {
+	models.Achievement
+	Progress   int64      `json:"progress"`
+	UnlockedAt *time.Time `json:"unlocked_at"`
+}
+
+// AchievementList returns all achievements with user's progress and unlock time as response
+func AchievementList(
+	getAchievements dependencyGetAchievements,
+	getUserAchievements dependencyGetUserAchievements,
+	getAchievementStats dependencyGetAchievementStats,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		unlocked, err := getUserAchievements(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		stats, err := getAchievementStats(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		unlockedAt := map[int64]time.Time{}
+		for _, v := range unlocked {
+			unlockedAt[v.AchievementID] = v.CreatedAt
+		}
+
+		achievements := getAchievements()
+		result := make([]achievementProgress, len(achievements))
+		for i, a := range achievements {
+			result[i] = achievementProgress{Achievement: a, Progress: stats[a.Metric]}
+			if t, ok := unlockedAt[a.ID]; ok {
+				result[i].UnlockedAt = &t
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
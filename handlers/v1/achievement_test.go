@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAchievementList(t *testing.T) {
+	getAchievements := func() models.Achievements {
+		return models.Achievements{{ID: 1, Metric: models.AchievementMetricRewards, Threshold: 1}}
+	}
+
+	testdata := []struct {
+		when                string
+		getUserAchievements dependencyGetUserAchievements
+		getAchievementStats dependencyGetAchievementStats
+		code                int
+	}{
+		{
+			"errored getUserAchievements dependency",
+			func(int64) ([]models.UserAchievement, error) { return nil, fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"errored getAchievementStats dependency",
+			func(int64) ([]models.UserAchievement, error) { return nil, nil },
+			func(int64) (models.AchievementStats, error) { return nil, fmt.Errorf("") },
+			500,
+		},
+		{
+			"correct dependencies injected",
+			func(int64) ([]models.UserAchievement, error) {
+				return []models.UserAchievement{{AchievementID: 1}}, nil
+			},
+			func(int64) (models.AchievementStats, error) { return models.AchievementStats{}, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given achievement list controller", t, func() {
+			handler := AchievementList(getAchievements, v.getUserAchievements, v.getAchievementStats)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/users/achievements"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
@@ -21,6 +21,7 @@ type adgateMediaPayload struct {
 // AdgateMediaCallback handles callback from adgateMedia
 func AdgateMediaCallback(
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getNumberOfAdgateMediaOffers dependencyGetNumberOfAdgateMediaOffers,
 	getSystemConfig dependencyGetSystemConfig,
 	createAdgateMediaIncome dependencyCreateAdgateMediaIncome,
@@ -58,12 +59,14 @@ func AdgateMediaCallback(
 			return
 		}
 
-		// create income adgateMedia
-		amount := payload.Amount / 1e8
+		// create income adgateMedia in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount / 1e8)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypeAdgateMedia,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -74,11 +77,12 @@ func AdgateMediaCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, amount, "adgate media", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, amount, currency.Code, "adgate media", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// AdminConfigList returns config versions, latest first, as response
+func AdminConfigList(
+	getConfigs dependencyGetConfigs,
+	getNumberOfConfigs dependencyGetNumberOfConfigs,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		configs, err := getConfigs(limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfConfigs()
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(configs, count))
+	}
+}
+
+type configPayload struct {
+	TotalRewardThreshold float64 `json:"total_reward_threshold" binding:"min=0"`
+	RefererRewardRate    float64 `json:"referer_reward_rate" binding:"min=0,max=0.9999"`
+	MinWithdrawalAmount  float64 `json:"min_withdrawal_amount" binding:"min=0"`
+	WithdrawalInterval   int64   `json:"withdrawal_interval" binding:"min=0"`
+	WithdrawalFee        float64 `json:"withdrawal_fee" binding:"min=0"`
+	ReviewAmount         float64 `json:"review_amount" binding:"min=0"`
+	ReviewAccountDays    int64   `json:"review_account_days" binding:"min=0"`
+	ReviewAddressDays    int64   `json:"review_address_days" binding:"min=0"`
+}
+
+// AdminCreateConfig creates a new config version and refreshes cache
+func AdminCreateConfig(
+	createConfig dependencyCreateConfig,
+	updateCache dependencyUpdateCache,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := configPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		config := models.Config{
+			TotalRewardThreshold: payload.TotalRewardThreshold,
+			RefererRewardRate:    payload.RefererRewardRate,
+			MinWithdrawalAmount:  payload.MinWithdrawalAmount,
+			WithdrawalInterval:   payload.WithdrawalInterval,
+			WithdrawalFee:        payload.WithdrawalFee,
+			ReviewAmount:         payload.ReviewAmount,
+			ReviewAccountDays:    payload.ReviewAccountDays,
+			ReviewAddressDays:    payload.ReviewAddressDays,
+		}
+		id, err := createConfig(config)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		config.ID = id
+		updateCache()
+
+		logrus.WithFields(logrus.Fields{
+			"event":  models.EventAdminChange,
+			"ip":     c.ClientIP(),
+			"config": config,
+		}).Info("admin created config")
+
+		c.JSON(http.StatusCreated, config)
+	}
+}
+
+// AdminRewardRateList returns reward rates of every scope and type as response
+func AdminRewardRateList(getAllRewardRates dependencyGetAllRewardRates) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rates, err := getAllRewardRates()
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, rates)
+	}
+}
+
+type rewardRatePayload struct {
+	Min      float64 `json:"min" binding:"min=0"`
+	Max      float64 `json:"max" binding:"gtfield=Min"`
+	Weight   int64   `json:"weight" binding:"min=0"`
+	Type     string  `json:"type" binding:"required,eq=reward-today-less|eq=reward-today-more"`
+	Scope    string  `json:"scope" binding:"max=31"`
+	Currency string  `json:"currency" binding:"-"`
+}
+
+func rewardRateWithPayload(id int64, currency string, p rewardRatePayload) models.RewardRate {
+	return models.RewardRate{
+		ID:       id,
+		Min:      p.Min,
+		Max:      p.Max,
+		Weight:   p.Weight,
+		Type:     p.Type,
+		Scope:    p.Scope,
+		Currency: currency,
+	}
+}
+
+// AdminCreateRewardRate creates a new reward rate bucket of currency, base currency if not specified, and refreshes cache
+func AdminCreateRewardRate(
+	getCurrency dependencyGetCurrency,
+	createRewardRate dependencyCreateRewardRate,
+	updateCache dependencyUpdateCache,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := rewardRatePayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		currency, ok := getCurrency(payload.Currency)
+		if !ok {
+			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidCurrency)
+			return
+		}
+
+		rate := rewardRateWithPayload(0, currency.Code, payload)
+		if err := createRewardRate(rate); err != nil {
+			abortWithRewardRateError(c, err)
+			return
+		}
+		updateCache()
+
+		logrus.WithFields(logrus.Fields{
+			"event":       models.EventAdminChange,
+			"ip":          c.ClientIP(),
+			"reward_rate": rate,
+		}).Info("admin created reward rate")
+
+		c.JSON(http.StatusCreated, rate)
+	}
+}
+
+// AdminUpdateRewardRate updates reward rate bucket and refreshes cache
+func AdminUpdateRewardRate(
+	getCurrency dependencyGetCurrency,
+	updateRewardRate dependencyUpdateRewardRate,
+	updateCache dependencyUpdateCache,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		payload := rewardRatePayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		currency, ok := getCurrency(payload.Currency)
+		if !ok {
+			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidCurrency)
+			return
+		}
+
+		rate := rewardRateWithPayload(id, currency.Code, payload)
+		if err := updateRewardRate(rate); err != nil {
+			abortWithRewardRateError(c, err)
+			return
+		}
+		updateCache()
+
+		logrus.WithFields(logrus.Fields{
+			"event":       models.EventAdminChange,
+			"ip":          c.ClientIP(),
+			"reward_rate": rate,
+		}).Info("admin updated reward rate")
+
+		c.JSON(http.StatusOK, rate)
+	}
+}
+
+// AdminDeleteRewardRate deletes reward rate bucket and refreshes cache
+func AdminDeleteRewardRate(
+	deleteRewardRate dependencyDeleteRewardRate,
+	updateCache dependencyUpdateCache,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		if err := deleteRewardRate(id); err != nil {
+			abortWithRewardRateError(c, err)
+			return
+		}
+		updateCache()
+
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventAdminChange,
+			"ip":             c.ClientIP(),
+			"reward_rate_id": id,
+		}).Info("admin deleted reward rate")
+
+		c.Status(http.StatusOK)
+	}
+}
+
+func abortWithRewardRateError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrNotFound:
+		c.AbortWithError(http.StatusNotFound, err)
+	case errors.ErrInvalidRewardRates:
+		c.AbortWithError(http.StatusBadRequest, err)
+	default:
+		c.AbortWithError(http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// columns of alipay batch transfer file, serial_no is withdrawal id
+var alipayExportHeader = []string{"serial_no", "account", "name", "amount", "remark"}
+
+// status of rows in alipay batch transfer result file
+const (
+	alipayResultSuccess = "SUCCESS"
+	alipayResultFail    = "FAIL"
+)
+
+// AdminExportAlipayWithdrawals marks pending withdrawals as processing and exports exactly them
+// as alipay batch transfer csv, operators upload the file to alipay to pay them out
+func AdminExportAlipayWithdrawals(
+	updatePendingWithdrawalStatusToProcessing dependencyUpdatePendingWithdrawalStatusToProcessing,
+	appname string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// exported withdrawals must not be exported again
+		withdrawals, err := updatePendingWithdrawalStatusToProcessing()
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		buf := bytes.NewBufferString("")
+		w := csv.NewWriter(buf)
+		w.Write(alipayExportHeader)
+		ids := make([]int64, len(withdrawals))
+		for i, v := range withdrawals {
+			ids[i] = v.ID
+			w.Write([]string{
+				strconv.FormatInt(v.ID, 10),
+				strings.TrimSpace(v.Address),
+				"",
+				strconv.FormatFloat(v.AmountToSend(), 'f', 2, 64), // amounts of alipay are floored to cents on creation
+				fmt.Sprintf("%s withdrawal %d", appname, v.ID),
+			})
+		}
+		w.Flush()
+
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventAdminChange,
+			"ip":             c.ClientIP(),
+			"withdrawal_ids": ids,
+		}).Info("admin exported alipay withdrawals")
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=alipay-%s.csv", time.Now().UTC().Format("20060102150405")))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	}
+}
+
+type alipayResultError struct {
+	SerialNo string `json:"serial_no"`
+	Error    string `json:"error"`
+}
+
+// AdminImportAlipayResults reconciles withdrawals with alipay batch transfer result csv uploaded as file,
+// rows with columns serial_no, status (SUCCESS or FAIL), transfer_id and reason are required,
+// succeeded withdrawals are marked as processed, failed ones are refunded to user
+func AdminImportAlipayResults(
+	updateWithdrawalStatusToProcessed dependencyUpdateWithdrawalStatusToProcessed,
+	failWithdrawal dependencyFailWithdrawal,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file, _, err := c.Request.FormFile("file")
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		defer file.Close()
+
+		rows, err := parseAlipayResults(file)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		processed, failed, rowErrors := []int64{}, []int64{}, []alipayResultError{}
+		for _, row := range rows {
+			id, err := strconv.ParseInt(row["serial_no"], 10, 64)
+			if err != nil {
+				rowErrors = append(rowErrors, alipayResultError{row["serial_no"], "invalid serial_no"})
+				continue
+			}
+
+			switch row["status"] {
+			case alipayResultSuccess:
+				if row["transfer_id"] == "" {
+					err = fmt.Errorf("empty transfer_id")
+					break
+				}
+				if err = updateWithdrawalStatusToProcessed([]int64{id}, row["transfer_id"]); err == nil {
+					processed = append(processed, id)
+				}
+			case alipayResultFail:
+				reason := row["reason"]
+				if reason == "" {
+					reason = "alipay transfer failed"
+				}
+				if _, err = failWithdrawal(id, reason); err == nil {
+					failed = append(failed, id)
+				}
+			default:
+				err = fmt.Errorf("invalid status %q", row["status"])
+			}
+
+			if err != nil {
+				rowErrors = append(rowErrors, alipayResultError{row["serial_no"], err.Error()})
+			}
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":     models.EventAdminChange,
+			"ip":        c.ClientIP(),
+			"processed": processed,
+			"failed":    failed,
+			"errors":    rowErrors,
+		}).Info("admin imported alipay results")
+
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"processed": processed,
+			"failed":    failed,
+			"errors":    rowErrors,
+		})
+	}
+}
+
+// parse csv with header into rows keyed by column name
+func parseAlipayResults(r io.Reader) ([]map[string]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty result file")
+	}
+
+	header := records[0]
+	for _, column := range []string{"serial_no", "status", "transfer_id", "reason"} {
+		if !containsString(header, column) {
+			return nil, fmt.Errorf("missing column %v", column)
+		}
+	}
+
+	rows := make([]map[string]string, len(records)-1)
+	for i, record := range records[1:] {
+		rows[i] = make(map[string]string, len(header))
+		for j, column := range header {
+			rows[i][column] = strings.TrimSpace(record[j])
+		}
+	}
+	return rows, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
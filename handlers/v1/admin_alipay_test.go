@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAdminExportAlipayWithdrawals(t *testing.T) {
+	withdrawals := []models.Withdrawal{{ID: 1, Address: "a@example.com", Amount: 1.5}, {ID: 2, Address: " 13800000000 ", Amount: 2}}
+
+	testdata := []struct {
+		when                                      string
+		updatePendingWithdrawalStatusToProcessing dependencyUpdatePendingWithdrawalStatusToProcessing
+		code                                      int
+	}{
+		{
+			"errored updatePendingWithdrawalStatusToProcessing dependency",
+			func() ([]models.Withdrawal, error) { return nil, fmt.Errorf("") },
+			500,
+		},
+		{
+			"no pending withdrawals",
+			func() ([]models.Withdrawal, error) { return nil, nil },
+			200,
+		},
+		{
+			"correct dependencies",
+			func() ([]models.Withdrawal, error) { return withdrawals, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin export alipay withdrawals controller", t, func() {
+			handler := AdminExportAlipayWithdrawals(v.updatePendingWithdrawalStatusToProcessing, "sole")
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.POST("/admin/alipay/exports", handler)
+				req, _ := http.NewRequest("POST", "/admin/alipay/exports", nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given admin export alipay withdrawals controller", t, func() {
+		handler := AdminExportAlipayWithdrawals(
+			func() ([]models.Withdrawal, error) { return withdrawals, nil },
+			"sole",
+		)
+
+		Convey("When export withdrawals", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.POST("/admin/alipay/exports", handler)
+			req, _ := http.NewRequest("POST", "/admin/alipay/exports", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response should be csv of withdrawals", func() {
+				So(resp.Body.String(), ShouldEqual, "serial_no,account,name,amount,remark\n"+
+					"1,a@example.com,,1.50,sole withdrawal 1\n"+
+					"2,13800000000,,2.00,sole withdrawal 2\n")
+			})
+		})
+	})
+}
+
+func newAlipayResultsRequest(content string) *http.Request {
+	body := bytes.NewBufferString("")
+	w := multipart.NewWriter(body)
+	if content != "" {
+		part, _ := w.CreateFormFile("file", "result.csv")
+		part.Write([]byte(content))
+	}
+	w.Close()
+
+	req, _ := http.NewRequest("POST", "/admin/alipay/results", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestAdminImportAlipayResults(t *testing.T) {
+	testdata := []struct {
+		when    string
+		content string
+		code    int
+	}{
+		{"missing file", "", 400},
+		{"missing column", "serial_no,status\n1,SUCCESS\n", 400},
+		{"valid file", "serial_no,status,transfer_id,reason\n1,SUCCESS,t1,\n", 200},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin import alipay results controller", t, func() {
+			handler := AdminImportAlipayResults(
+				func([]int64, string) error { return nil },
+				func(int64, string) (models.Withdrawal, error) { return models.Withdrawal{}, nil },
+			)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.POST("/admin/alipay/results", handler)
+				r.ServeHTTP(resp, newAlipayResultsRequest(v.content))
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given admin import alipay results controller", t, func() {
+		processed := map[int64]string{}
+		failed := map[int64]string{}
+		handler := AdminImportAlipayResults(
+			func(ids []int64, transactionID string) error {
+				if ids[0] == 3 {
+					return fmt.Errorf("expected 1 but 0 rows affected")
+				}
+				processed[ids[0]] = transactionID
+				return nil
+			},
+			func(id int64, reason string) (models.Withdrawal, error) {
+				if id == 5 {
+					return models.Withdrawal{}, errors.ErrWithdrawalNotProcessing
+				}
+				failed[id] = reason
+				return models.Withdrawal{}, nil
+			},
+		)
+
+		Convey("When import results", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.POST("/admin/alipay/results", handler)
+			r.ServeHTTP(resp, newAlipayResultsRequest("serial_no,account,amount,status,transfer_id,reason\n"+
+				"1,a,1.00,SUCCESS,t1,\n"+
+				"2,b,1.00,FAIL,,account not found\n"+
+				"3,c,1.00,SUCCESS,t3,\n"+
+				"4,d,1.00,FAIL,,\n"+
+				"5,e,1.00,FAIL,,\n"+
+				"6,f,1.00,SUCCESS,,\n"+
+				"x,g,1.00,SUCCESS,t7,\n"+
+				"8,h,1.00,UNKNOWN,,\n"))
+
+			Convey("Withdrawals should be reconciled row by row", func() {
+				So(resp.Code, ShouldEqual, 200)
+				So(reflect.DeepEqual(processed, map[int64]string{1: "t1"}), ShouldBeTrue)
+				So(reflect.DeepEqual(failed, map[int64]string{2: "account not found", 4: "alipay transfer failed"}), ShouldBeTrue)
+				for _, serialNo := range []string{`"3"`, `"5"`, `"6"`, `"x"`, `"8"`} {
+					So(resp.Body.String(), ShouldContainSubstring, `"serial_no":`+serialNo)
+				}
+			})
+		})
+	})
+}
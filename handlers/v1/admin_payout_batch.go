@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// AdminPayoutBatchList returns payout batches with status given, in review by default, as response
+func AdminPayoutBatchList(
+	getPayoutBatchesByStatus dependencyGetPayoutBatchesByStatus,
+	getNumberOfPayoutBatchesByStatus dependencyGetNumberOfPayoutBatchesByStatus,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		status, err := strconv.ParseInt(c.DefaultQuery("status", strconv.Itoa(models.PayoutBatchStatusReview)), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		batches, err := getPayoutBatchesByStatus(status, limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfPayoutBatchesByStatus(status)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(batches, count))
+	}
+}
+
+// AdminPayoutBatchWithdrawalList returns withdrawals of payout batch still processing, i.e. to be resolved, as response
+func AdminPayoutBatchWithdrawalList(getProcessingWithdrawalsByPayoutBatch dependencyGetProcessingWithdrawalsByPayoutBatch) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		withdrawals, err := getProcessingWithdrawalsByPayoutBatch(c.Param("batch_id"))
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, withdrawals)
+	}
+}
+
+// withdrawal found in wallet is paid with transaction id, one not found is unpaid to be paid out again
+// or refunded to user with reason
+type resolvePayoutBatchWithdrawalPayload struct {
+	Result        string `json:"result" binding:"required,eq=paid|eq=unpaid|eq=refunded"`
+	TransactionID string `json:"tx_id" binding:"max=255"`
+	Reason        string `json:"reason" binding:"max=255"`
+}
+
+// withdrawal status resolved to for each result
+var payoutBatchWithdrawalResultStatus = map[string]int64{
+	"paid":     models.WithdrawalStatusProcessed,
+	"unpaid":   models.WithdrawalStatusPending,
+	"refunded": models.WithdrawalStatusFailed,
+}
+
+// AdminResolvePayoutBatchWithdrawal resolves withdrawal of payout batch in review after it is checked in wallet
+func AdminResolvePayoutBatchWithdrawal(resolvePayoutBatchWithdrawal dependencyResolvePayoutBatchWithdrawal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		payload := resolvePayoutBatchWithdrawalPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+		if (payload.Result == "paid" && payload.TransactionID == "") || (payload.Result == "refunded" && payload.Reason == "") {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		batchID := c.Param("batch_id")
+		status := payoutBatchWithdrawalResultStatus[payload.Result]
+		withdrawal, err := resolvePayoutBatchWithdrawal(batchID, id, status, payload.TransactionID, payload.Reason)
+		if err != nil {
+			switch err {
+			case errors.ErrNotFound:
+				c.AbortWithError(http.StatusNotFound, err)
+			case errors.ErrPayoutBatchNotInReview, errors.ErrWithdrawalNotProcessing:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventAdminChange,
+			"ip":             c.ClientIP(),
+			"batch_id":       batchID,
+			"withdrawal_id":  id,
+			"result":         payload.Result,
+			"transaction_id": payload.TransactionID,
+			"reason":         payload.Reason,
+		}).Info("admin resolved withdrawal of payout batch")
+
+		c.JSON(http.StatusOK, withdrawal)
+	}
+}
@@ -0,0 +1,226 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAdminPayoutBatchList(t *testing.T) {
+	testdata := []struct {
+		when                             string
+		query                            string
+		getPayoutBatchesByStatus         dependencyGetPayoutBatchesByStatus
+		getNumberOfPayoutBatchesByStatus dependencyGetNumberOfPayoutBatchesByStatus
+		code                             int
+	}{
+		{
+			"invalid status",
+			"?status=a",
+			nil,
+			nil,
+			400,
+		},
+		{
+			"errored getPayoutBatchesByStatus dependency",
+			"",
+			func(int64, int64, int64) ([]models.PayoutBatch, error) { return nil, fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"correct dependencies injected",
+			"",
+			func(status int64, limit, offset int64) ([]models.PayoutBatch, error) {
+				return []models.PayoutBatch{{BatchID: "b1", Status: status}}, nil
+			},
+			func(int64) (int64, error) { return 1, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin payout batch list controller", t, func() {
+			handler := AdminPayoutBatchList(v.getPayoutBatchesByStatus, v.getNumberOfPayoutBatchesByStatus)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/payout_batches"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminPayoutBatchWithdrawalList(t *testing.T) {
+	testdata := []struct {
+		when                                  string
+		getProcessingWithdrawalsByPayoutBatch dependencyGetProcessingWithdrawalsByPayoutBatch
+		code                                  int
+	}{
+		{
+			"errored getProcessingWithdrawalsByPayoutBatch dependency",
+			func(string) ([]models.Withdrawal, error) { return nil, fmt.Errorf("") },
+			500,
+		},
+		{
+			"correct dependencies injected",
+			func(string) ([]models.Withdrawal, error) { return []models.Withdrawal{{ID: 1}}, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin payout batch withdrawal list controller", t, func() {
+			handler := AdminPayoutBatchWithdrawalList(v.getProcessingWithdrawalsByPayoutBatch)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/payout_batches/:batch_id/withdrawals"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", "/admin/payout_batches/b1/withdrawals", nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminResolvePayoutBatchWithdrawal(t *testing.T) {
+	resolved := func(string, int64, int64, string, string) (models.Withdrawal, error) { return models.Withdrawal{}, nil }
+
+	testdata := []struct {
+		when                         string
+		path                         string
+		requestData                  string
+		resolvePayoutBatchWithdrawal dependencyResolvePayoutBatchWithdrawal
+		code                         int
+	}{
+		{
+			"invalid id",
+			"/admin/payout_batches/b1/withdrawals/a/resolve",
+			`{"result":"unpaid"}`,
+			nil,
+			400,
+		},
+		{
+			"invalid result",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"lost"}`,
+			nil,
+			400,
+		},
+		{
+			"paid result without transaction id",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"paid"}`,
+			nil,
+			400,
+		},
+		{
+			"refunded result without reason",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"refunded"}`,
+			nil,
+			400,
+		},
+		{
+			"withdrawal not found",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"unpaid"}`,
+			func(string, int64, int64, string, string) (models.Withdrawal, error) {
+				return models.Withdrawal{}, errors.ErrNotFound
+			},
+			404,
+		},
+		{
+			"batch not in review",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"unpaid"}`,
+			func(string, int64, int64, string, string) (models.Withdrawal, error) {
+				return models.Withdrawal{}, errors.ErrPayoutBatchNotInReview
+			},
+			409,
+		},
+		{
+			"errored resolvePayoutBatchWithdrawal dependency",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"unpaid"}`,
+			func(string, int64, int64, string, string) (models.Withdrawal, error) {
+				return models.Withdrawal{}, fmt.Errorf("")
+			},
+			500,
+		},
+		{
+			"paid result",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"paid","tx_id":"tx"}`,
+			resolved,
+			200,
+		},
+		{
+			"refunded result",
+			"/admin/payout_batches/b1/withdrawals/1/resolve",
+			`{"result":"refunded","reason":"not paid"}`,
+			resolved,
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin resolve payout batch withdrawal controller", t, func() {
+			handler := AdminResolvePayoutBatchWithdrawal(v.resolvePayoutBatchWithdrawal)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/payout_batches/:batch_id/withdrawals/:id/resolve"
+				_, resp, r := gin.CreateTestContext()
+				r.POST(route, handler)
+				req, _ := http.NewRequest("POST", v.path, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given admin resolve payout batch withdrawal controller", t, func() {
+		var batchID, transactionID string
+		var status int64
+		handler := AdminResolvePayoutBatchWithdrawal(func(b string, id int64, s int64, tx, reason string) (models.Withdrawal, error) {
+			batchID, status, transactionID = b, s, tx
+			return models.Withdrawal{}, nil
+		})
+
+		Convey("When resolve withdrawal as paid", func() {
+			route := "/admin/payout_batches/:batch_id/withdrawals/:id/resolve"
+			_, resp, r := gin.CreateTestContext()
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", "/admin/payout_batches/b1/withdrawals/1/resolve", bytes.NewBufferString(`{"result":"paid","tx_id":"tx"}`))
+			r.ServeHTTP(resp, req)
+
+			Convey("Withdrawal of batch should be resolved as processed with transaction id", func() {
+				So(resp.Code, ShouldEqual, 200)
+				So(batchID, ShouldEqual, "b1")
+				So(status, ShouldEqual, models.WithdrawalStatusProcessed)
+				So(transactionID, ShouldEqual, "tx")
+			})
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// AdminRoleList returns roles with permissions granted as response
+func AdminRoleList(
+	getRoles dependencyGetRoles,
+	getRolePermissions dependencyGetRolePermissions,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles, err := getRoles()
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		permissions := getRolePermissions()
+		result := make([]map[string]interface{}, len(roles))
+		for i, role := range roles {
+			result[i] = map[string]interface{}{
+				"id":          role.ID,
+				"name":        role.Name,
+				"description": role.Description,
+				"permissions": permissions[role.Name],
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+type userRolePayload struct {
+	Role string `json:"role" binding:"max=31"`
+}
+
+// AdminUpdateUserRole grants role to user, empty role revokes admin permissions
+func AdminUpdateUserRole(updateUserRole dependencyUpdateUserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		payload := userRolePayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		if err := updateUserRole(id, payload.Role); err != nil {
+			switch err {
+			case errors.ErrNotFound:
+				c.AbortWithError(http.StatusNotFound, err)
+			case errors.ErrInvalidRole:
+				c.AbortWithError(http.StatusBadRequest, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":   models.EventAdminChange,
+			"ip":      c.ClientIP(),
+			"user_id": id,
+			"role":    payload.Role,
+		}).Info("admin updated user role")
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// AdminAuditLogList returns admin requests, latest first, as response
+func AdminAuditLogList(
+	getAdminAuditLogs dependencyGetAdminAuditLogs,
+	getNumberOfAdminAuditLogs dependencyGetNumberOfAdminAuditLogs,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		logs, err := getAdminAuditLogs(limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfAdminAuditLogs()
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(logs, count))
+	}
+}
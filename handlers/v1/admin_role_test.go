@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAdminRoleList(t *testing.T) {
+	Convey("Given admin role list controller with errored getRoles dependency", t, func() {
+		handler := AdminRoleList(func() ([]models.Role, error) { return nil, fmt.Errorf("") }, nil)
+
+		Convey("When get role list", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.GET("/admin/roles", handler)
+			req, _ := http.NewRequest("GET", "/admin/roles", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 500", func() {
+				So(resp.Code, ShouldEqual, 500)
+			})
+		})
+	})
+
+	Convey("Given admin role list controller with correct dependencies injected", t, func() {
+		getRoles := func() ([]models.Role, error) { return []models.Role{{Name: "support"}}, nil }
+		getRolePermissions := func() models.RolePermissions {
+			return models.RolePermissions{"support": {models.PermissionUsersRead}}
+		}
+		handler := AdminRoleList(getRoles, getRolePermissions)
+
+		Convey("When get role list", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.GET("/admin/roles", handler)
+			req, _ := http.NewRequest("GET", "/admin/roles", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Permissions should be in response", func() {
+				So(resp.Code, ShouldEqual, 200)
+				So(resp.Body.String(), ShouldContainSubstring, `"permissions":["users.read"]`)
+			})
+		})
+	})
+}
+
+func TestAdminUpdateUserRole(t *testing.T) {
+	testdata := []struct {
+		when           string
+		requestData    string
+		updateUserRole dependencyUpdateUserRole
+		code           int
+	}{
+		{
+			"invalid json data",
+			"huhu",
+			nil,
+			400,
+		},
+		{
+			"non-existing role",
+			`{"role":"god"}`,
+			func(int64, string) error { return errors.ErrInvalidRole },
+			400,
+		},
+		{
+			"non-existing user",
+			`{"role":"support"}`,
+			func(int64, string) error { return errors.ErrNotFound },
+			404,
+		},
+		{
+			"valid payload",
+			`{"role":"support"}`,
+			func(int64, string) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin update user role controller", t, func() {
+			handler := AdminUpdateUserRole(v.updateUserRole)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.PUT("/admin/users/:id/role", handler)
+				req, _ := http.NewRequest("PUT", "/admin/users/1/role", bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminAuditLogList(t *testing.T) {
+	Convey("Given admin audit log list controller with correct dependencies injected", t, func() {
+		getAdminAuditLogs := func(int64, int64) ([]models.AdminAuditLog, error) { return nil, nil }
+		getNumberOfAdminAuditLogs := func() (int64, error) { return 0, nil }
+		handler := AdminAuditLogList(getAdminAuditLogs, getNumberOfAdminAuditLogs)
+
+		Convey("When get audit log list", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.GET("/admin/audit_logs", handler)
+			req, _ := http.NewRequest("GET", "/admin/audit_logs", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 200", func() {
+				So(resp.Code, ShouldEqual, 200)
+			})
+		})
+	})
+}
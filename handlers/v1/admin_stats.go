@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminStats returns key metrics of faucet in currency of query, base currency by default, as response,
+// wallet_balance is null if hot wallet is unavailable
+func AdminStats(
+	getCurrency dependencyGetCurrency,
+	getLatestTotalReward dependencyGetLatestTotalReward,
+	getUserStats dependencyGetUserStats,
+	getWithdrawalStats dependencyGetWithdrawalStats,
+	getLiabilities dependencyGetLiabilities,
+	getWalletBalance dependencyGetWalletBalance,
+	getUsersOnline dependencyGetUsersOnline,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now()
+		today := now.UTC().Truncate(24 * time.Hour)
+
+		currency, err := parseCurrency(c, getCurrency)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		userStats, err := getUserStats(today)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		withdrawalStats, err := getWithdrawalStats(currency.Code)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		liabilities, err := getLiabilities(currency.Code)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		todayReward := 0.0
+		if totalReward := getLatestTotalReward(currency.Code); totalReward.IsSameDay(now) {
+			todayReward = totalReward.Total
+		}
+
+		// dashboard should still work without wallet
+		var walletBalance *float64
+		if balance, err := getWalletBalance(currency.Code); err != nil {
+			c.Error(err)
+		} else {
+			walletBalance = &balance
+		}
+
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"currency":       currency.Code,
+			"today_reward":   todayReward,
+			"users":          userStats,
+			"withdrawals":    withdrawalStats,
+			"liabilities":    liabilities,
+			"wallet_balance": walletBalance,
+			"users_online":   getUsersOnline(),
+		})
+	}
+}
+
+// AdminWalletRunway returns hot wallet balance of currency of query against liabilities and how long it lasts as response
+func AdminWalletRunway(
+	getCurrency dependencyGetCurrency,
+	getWalletRunway dependencyGetWalletRunway,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currency, err := parseCurrency(c, getCurrency)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		runway, err := getWalletRunway(currency.Code)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, runway)
+	}
+}
+
+// AdminRewardStats returns daily faucet payout of currency of query within date range as response
+func AdminRewardStats(
+	getCurrency dependencyGetCurrency,
+	getTotalRewards dependencyGetTotalRewards,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currency, err := parseCurrency(c, getCurrency)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		since, until, err := parseDateRange(c, time.Now())
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		totalRewards, err := getTotalRewards(currency.Code, since, until)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, totalRewards)
+	}
+}
+
+// AdminOfferwallStats returns offerwall revenue per provider within date range as response
+func AdminOfferwallStats(getOfferwallRevenues dependencyGetOfferwallRevenues) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		since, until, err := parseDateRange(c, time.Now())
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		revenues, err := getOfferwallRevenues(since, until)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, revenues)
+	}
+}
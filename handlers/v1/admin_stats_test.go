@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAdminStats(t *testing.T) {
+	getLatestTotalReward := func(string) models.TotalReward { return models.TotalReward{Total: 5, CreatedAt: time.Now()} }
+	getUsersOnline := func() int { return 3 }
+
+	getLiabilities := func(string) (float64, error) { return 2, nil }
+
+	testdata := []struct {
+		when               string
+		query              string
+		getUserStats       dependencyGetUserStats
+		getWithdrawalStats dependencyGetWithdrawalStats
+		getWalletBalance   dependencyGetWalletBalance
+		code               int
+		body               string
+	}{
+		{
+			"invalid currency",
+			"?currency=xxx",
+			nil,
+			nil,
+			nil,
+			400,
+			"",
+		},
+		{
+			"errored getUserStats dependency",
+			"",
+			func(time.Time) (models.UserStats, error) { return models.UserStats{}, fmt.Errorf("") },
+			nil,
+			nil,
+			500,
+			"",
+		},
+		{
+			"errored getWithdrawalStats dependency",
+			"",
+			func(time.Time) (models.UserStats, error) { return models.UserStats{}, nil },
+			func(string) ([]models.WithdrawalStats, error) { return nil, fmt.Errorf("") },
+			nil,
+			500,
+			"",
+		},
+		{
+			"errored getWalletBalance dependency",
+			"",
+			func(time.Time) (models.UserStats, error) { return models.UserStats{}, nil },
+			func(string) ([]models.WithdrawalStats, error) { return nil, nil },
+			func(string) (float64, error) { return 0, fmt.Errorf("") },
+			200,
+			`"wallet_balance":null`,
+		},
+		{
+			"correct dependencies injected",
+			"?currency=doge",
+			func(time.Time) (models.UserStats, error) { return models.UserStats{}, nil },
+			func(string) ([]models.WithdrawalStats, error) { return nil, nil },
+			func(string) (float64, error) { return 10, nil },
+			200,
+			`"wallet_balance":10`,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin stats controller", t, func() {
+			handler := AdminStats(mockGetCurrency(), getLatestTotalReward, v.getUserStats, v.getWithdrawalStats, getLiabilities, v.getWalletBalance, getUsersOnline)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/stats"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+					So(resp.Body.String(), ShouldContainSubstring, v.body)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminWalletRunway(t *testing.T) {
+	days := 2.5
+	testdata := []struct {
+		when            string
+		getWalletRunway dependencyGetWalletRunway
+		code            int
+		body            string
+	}{
+		{
+			"errored getWalletRunway dependency",
+			func(string) (models.WalletRunway, error) { return models.WalletRunway{}, fmt.Errorf("") },
+			500,
+			"",
+		},
+		{
+			"correct dependencies injected",
+			func(string) (models.WalletRunway, error) {
+				return models.WalletRunway{Balance: 10, RunwayDays: &days}, nil
+			},
+			200,
+			`"runway_days":2.5`,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin wallet runway controller", t, func() {
+			handler := AdminWalletRunway(mockGetCurrency(), v.getWalletRunway)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/stats/wallet"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+					So(resp.Body.String(), ShouldContainSubstring, v.body)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminRewardStats(t *testing.T) {
+	testdata := []struct {
+		when            string
+		query           string
+		getTotalRewards dependencyGetTotalRewards
+		code            int
+	}{
+		{
+			"invalid date range",
+			"?since=2016-10-31&until=2016-10-01",
+			nil,
+			400,
+		},
+		{
+			"errored getTotalRewards dependency",
+			"",
+			func(string, time.Time, time.Time) ([]models.TotalReward, error) { return nil, fmt.Errorf("") },
+			500,
+		},
+		{
+			"correct dependencies injected",
+			"?since=2016-10-01&until=2016-10-31",
+			func(string, time.Time, time.Time) ([]models.TotalReward, error) { return nil, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin reward stats controller", t, func() {
+			handler := AdminRewardStats(mockGetCurrency(), v.getTotalRewards)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/stats/rewards"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminOfferwallStats(t *testing.T) {
+	Convey("Given admin offerwall stats controller with correct dependencies injected", t, func() {
+		getOfferwallRevenues := func(time.Time, time.Time) ([]models.OfferwallRevenue, error) {
+			return []models.OfferwallRevenue{{Provider: "superrewards", Amount: 1}}, nil
+		}
+		handler := AdminOfferwallStats(getOfferwallRevenues)
+
+		Convey("When get offerwall stats", func() {
+			route := "/admin/stats/offerwalls"
+			_, resp, r := gin.CreateTestContext()
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Revenue per provider should be in response", func() {
+				So(resp.Code, ShouldEqual, 200)
+				So(resp.Body.String(), ShouldContainSubstring, `"provider":"superrewards"`)
+			})
+		})
+	})
+}
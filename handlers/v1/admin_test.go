@@ -0,0 +1,249 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAdminConfigList(t *testing.T) {
+	testdata := []struct {
+		when               string
+		query              string
+		getConfigs         dependencyGetConfigs
+		getNumberOfConfigs dependencyGetNumberOfConfigs
+		code               int
+	}{
+		{
+			"invalid limit",
+			"?limit=3i",
+			nil,
+			nil,
+			400,
+		},
+		{
+			"errored getConfigs dependency",
+			"",
+			func(int64, int64) ([]models.Config, error) { return nil, fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"correct dependencies injected",
+			"",
+			func(int64, int64) ([]models.Config, error) { return nil, nil },
+			func() (int64, error) { return 0, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin config list controller", t, func() {
+			handler := AdminConfigList(v.getConfigs, v.getNumberOfConfigs)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/configs"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminCreateConfig(t *testing.T) {
+	testdata := []struct {
+		when         string
+		requestData  string
+		createConfig dependencyCreateConfig
+		code         int
+	}{
+		{
+			"invalid referer reward rate",
+			`{"total_reward_threshold":10,"referer_reward_rate":1.5}`,
+			nil,
+			400,
+		},
+		{
+			"negative review account days",
+			`{"total_reward_threshold":10,"referer_reward_rate":0.1,"review_account_days":-1}`,
+			nil,
+			400,
+		},
+		{
+			"errored createConfig dependency",
+			`{"total_reward_threshold":10,"referer_reward_rate":0.1}`,
+			func(models.Config) (int64, error) { return 0, fmt.Errorf("") },
+			500,
+		},
+		{
+			"valid payload",
+			`{"total_reward_threshold":10,"referer_reward_rate":0.1}`,
+			func(models.Config) (int64, error) { return 3, nil },
+			201,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin create config controller", t, func() {
+			handler := AdminCreateConfig(v.createConfig, func() {})
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/configs"
+				_, resp, r := gin.CreateTestContext()
+				r.POST(route, handler)
+				req, _ := http.NewRequest("POST", route, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given admin create config controller", t, func() {
+		handler := AdminCreateConfig(func(models.Config) (int64, error) { return 3, nil }, func() {})
+
+		Convey("When request with valid payload", func() {
+			route := "/admin/configs"
+			_, resp, r := gin.CreateTestContext()
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route, bytes.NewBufferString(`{"total_reward_threshold":10,"referer_reward_rate":0.1}`))
+			r.ServeHTTP(resp, req)
+
+			Convey("Response should carry id of created config", func() {
+				So(resp.Body.String(), ShouldContainSubstring, `"id":3`)
+			})
+		})
+	})
+}
+
+func TestAdminUpdateRewardRate(t *testing.T) {
+	testdata := []struct {
+		when             string
+		path             string
+		requestData      string
+		updateRewardRate dependencyUpdateRewardRate
+		code             int
+	}{
+		{
+			"invalid id",
+			"/admin/reward_rates/x",
+			`{"min":1,"max":2,"weight":1,"type":"reward-today-less"}`,
+			nil,
+			400,
+		},
+		{
+			"min greater than max",
+			"/admin/reward_rates/1",
+			`{"min":2,"max":1,"weight":1,"type":"reward-today-less"}`,
+			nil,
+			400,
+		},
+		{
+			"invalid type",
+			"/admin/reward_rates/1",
+			`{"min":1,"max":2,"weight":1,"type":"huhu"}`,
+			nil,
+			400,
+		},
+		{
+			"invalid currency",
+			"/admin/reward_rates/1",
+			`{"min":1,"max":2,"weight":1,"type":"reward-today-less","currency":"xxx"}`,
+			nil,
+			400,
+		},
+		{
+			"non-existing reward rate",
+			"/admin/reward_rates/1",
+			`{"min":1,"max":2,"weight":1,"type":"reward-today-less"}`,
+			func(models.RewardRate) error { return errors.ErrNotFound },
+			404,
+		},
+		{
+			"weights summing to 0",
+			"/admin/reward_rates/1",
+			`{"min":1,"max":2,"weight":0,"type":"reward-today-less"}`,
+			func(models.RewardRate) error { return errors.ErrInvalidRewardRates },
+			400,
+		},
+		{
+			"valid payload",
+			"/admin/reward_rates/1",
+			`{"min":1,"max":2,"weight":1,"type":"reward-today-less","scope":"US","currency":"doge"}`,
+			func(models.RewardRate) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin update reward rate controller", t, func() {
+			handler := AdminUpdateRewardRate(mockGetCurrency(), v.updateRewardRate, func() {})
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.PUT("/admin/reward_rates/:id", handler)
+				req, _ := http.NewRequest("PUT", v.path, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminDeleteRewardRate(t *testing.T) {
+	testdata := []struct {
+		when             string
+		deleteRewardRate dependencyDeleteRewardRate
+		code             int
+	}{
+		{
+			"last global reward rate",
+			func(int64) error { return errors.ErrInvalidRewardRates },
+			400,
+		},
+		{
+			"errored deleteRewardRate dependency",
+			func(int64) error { return fmt.Errorf("") },
+			500,
+		},
+		{
+			"correct dependencies injected",
+			func(int64) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin delete reward rate controller", t, func() {
+			handler := AdminDeleteRewardRate(v.deleteRewardRate, func() {})
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.DELETE("/admin/reward_rates/:id", handler)
+				req, _ := http.NewRequest("DELETE", "/admin/reward_rates/1", nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// AdminUserList returns users searched by id, referer_id, email or address prefix as response
+func AdminUserList(
+	getUsers dependencyGetUsers,
+	getNumberOfUsers dependencyGetNumberOfUsers,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		q, err := parseUserQuery(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		users, err := getUsers(q, limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfUsers(q)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(users, count))
+	}
+}
+
+func parseUserQuery(c *gin.Context) (models.UserQuery, error) {
+	q := models.UserQuery{
+		Email:   c.Query("email"),
+		Address: c.Query("address"),
+	}
+
+	var err error
+	if q.ID, err = strconv.ParseInt(c.DefaultQuery("id", "0"), 10, 64); err != nil {
+		return q, err
+	}
+	if q.RefererID, err = strconv.ParseInt(c.DefaultQuery("referer_id", "0"), 10, 64); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// AdminUserDetail returns user with status logs and balance adjustments as response
+func AdminUserDetail(
+	getUserByID dependencyGetUserByID,
+	getUserStatusLogs dependencyGetUserStatusLogs,
+	getAdminAdjustments dependencyGetAdminAdjustments,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		user, err := getUserByID(id)
+		switch {
+		case err == errors.ErrNotFound:
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		case err != nil:
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		logs, err := getUserStatusLogs(id)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		adjustments, err := getAdminAdjustments(id)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"user":        user,
+			"referer_id":  user.RefererID,
+			"status_logs": logs,
+			"adjustments": adjustments,
+		})
+	}
+}
+
+// AdminUserIncomeList returns user's incomes of all types as response
+func AdminUserIncomeList(
+	getIncomes dependencyGetIncomes,
+	getNumberOfIncomes dependencyGetNumberOfIncomes,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		incomes, err := getIncomes(id, limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfIncomes(id)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(incomes, count))
+	}
+}
+
+// AdminUserWithdrawalList returns user's withdrawals as response
+func AdminUserWithdrawalList(
+	getWithdrawals dependencyGetWithdrawals,
+	getNumberOfWithdrawals dependencyGetNumberOfWithdrawals,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		withdrawals, err := getWithdrawals(id, limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfWithdrawals(id)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(withdrawals, count))
+	}
+}
+
+type userStatusPayload struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// AdminBanUser bans user with reason
+func AdminBanUser(banUser dependencyBanUser) gin.HandlerFunc {
+	return adminChangeUserStatus(banUser, "admin banned user")
+}
+
+// AdminUnbanUser unbans user with reason
+func AdminUnbanUser(unbanUser dependencyUnbanUser) gin.HandlerFunc {
+	return adminChangeUserStatus(unbanUser, "admin unbanned user")
+}
+
+func adminChangeUserStatus(changeStatus func(id int64, reason string) error, message string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		payload := userStatusPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		if err := changeStatus(id, payload.Reason); err != nil {
+			switch err {
+			case errors.ErrNotFound:
+				c.AbortWithError(http.StatusNotFound, err)
+			case errors.ErrUserBanned, errors.ErrUserNotBanned:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":   models.EventAdminChange,
+			"ip":      c.ClientIP(),
+			"user_id": id,
+			"reason":  payload.Reason,
+		}).Info(message)
+
+		c.Status(http.StatusOK)
+	}
+}
+
+type adminAdjustmentPayload struct {
+	Amount   float64 `json:"amount" binding:"required"`
+	Currency string  `json:"currency" binding:"-"`
+	Reason   string  `json:"reason" binding:"required,max=255"`
+}
+
+// AdminCreateAdjustment credits or deducts user balance of currency, base currency if not specified, with reason,
+// deduction leading to negative balance is rejected
+func AdminCreateAdjustment(
+	getCurrency dependencyGetCurrency,
+	createAdminAdjustment dependencyCreateAdminAdjustment,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		payload := adminAdjustmentPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		currency, ok := getCurrency(payload.Currency)
+		if !ok {
+			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidCurrency)
+			return
+		}
+
+		adjustment := models.AdminAdjustment{
+			UserID:   id,
+			Currency: currency.Code,
+			Amount:   payload.Amount,
+			Reason:   payload.Reason,
+		}
+		if err := createAdminAdjustment(adjustment); err != nil {
+			switch err {
+			case errors.ErrNotFound:
+				c.AbortWithError(http.StatusNotFound, err)
+			case errors.ErrInsufficientBalance:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":      models.EventAdminChange,
+			"ip":         c.ClientIP(),
+			"adjustment": adjustment,
+		}).Info("admin adjusted user balance")
+
+		c.JSON(http.StatusCreated, adjustment)
+	}
+}
@@ -0,0 +1,299 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAdminUserList(t *testing.T) {
+	testdata := []struct {
+		when             string
+		query            string
+		getUsers         dependencyGetUsers
+		getNumberOfUsers dependencyGetNumberOfUsers
+		code             int
+	}{
+		{
+			"invalid limit",
+			"?limit=3i",
+			nil,
+			nil,
+			400,
+		},
+		{
+			"invalid referer id",
+			"?referer_id=a",
+			nil,
+			nil,
+			400,
+		},
+		{
+			"errored getUsers dependency",
+			"?email=a",
+			func(models.UserQuery, int64, int64) ([]models.User, error) { return nil, fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"correct dependencies injected",
+			"?id=1&address=1a",
+			func(models.UserQuery, int64, int64) ([]models.User, error) { return nil, nil },
+			func(models.UserQuery) (int64, error) { return 0, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin user list controller", t, func() {
+			handler := AdminUserList(v.getUsers, v.getNumberOfUsers)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/users"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminUserDetail(t *testing.T) {
+	testdata := []struct {
+		when                string
+		id                  string
+		getUserByID         dependencyGetUserByID
+		getUserStatusLogs   dependencyGetUserStatusLogs
+		getAdminAdjustments dependencyGetAdminAdjustments
+		code                int
+	}{
+		{
+			"invalid id",
+			"a",
+			nil,
+			nil,
+			nil,
+			400,
+		},
+		{
+			"non-existing user",
+			"1",
+			mockGetUserByID(models.User{}, errors.ErrNotFound),
+			nil,
+			nil,
+			404,
+		},
+		{
+			"errored getUserStatusLogs dependency",
+			"1",
+			mockGetUserByID(models.User{}, nil),
+			func(int64) ([]models.UserStatusLog, error) { return nil, fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"correct dependencies injected",
+			"1",
+			mockGetUserByID(models.User{}, nil),
+			func(int64) ([]models.UserStatusLog, error) { return nil, nil },
+			func(int64) ([]models.AdminAdjustment, error) { return nil, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin user detail controller", t, func() {
+			handler := AdminUserDetail(v.getUserByID, v.getUserStatusLogs, v.getAdminAdjustments)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.GET("/admin/users/:id", handler)
+				req, _ := http.NewRequest("GET", "/admin/users/"+v.id, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminUserIncomeList(t *testing.T) {
+	Convey("Given admin user income list controller with correct dependencies injected", t, func() {
+		getIncomes := func(int64, int64, int64) ([]models.Income, error) { return nil, nil }
+		getNumberOfIncomes := func(int64) (int64, error) { return 0, nil }
+		handler := AdminUserIncomeList(getIncomes, getNumberOfIncomes)
+
+		Convey("When get user income list", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.GET("/admin/users/:id/incomes", handler)
+			req, _ := http.NewRequest("GET", "/admin/users/1/incomes", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 200", func() {
+				So(resp.Code, ShouldEqual, 200)
+			})
+		})
+	})
+}
+
+func TestAdminUserWithdrawalList(t *testing.T) {
+	Convey("Given admin user withdrawal list controller with errored getWithdrawals dependency", t, func() {
+		getWithdrawals := func(int64, int64, int64) ([]models.Withdrawal, error) { return nil, fmt.Errorf("") }
+		handler := AdminUserWithdrawalList(getWithdrawals, nil)
+
+		Convey("When get user withdrawal list", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.GET("/admin/users/:id/withdrawals", handler)
+			req, _ := http.NewRequest("GET", "/admin/users/1/withdrawals", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 500", func() {
+				So(resp.Code, ShouldEqual, 500)
+			})
+		})
+	})
+}
+
+func TestAdminBanUser(t *testing.T) {
+	testdata := []struct {
+		when        string
+		requestData string
+		banUser     dependencyBanUser
+		code        int
+	}{
+		{
+			"empty reason",
+			`{}`,
+			nil,
+			400,
+		},
+		{
+			"non-existing user",
+			`{"reason":"spam"}`,
+			func(int64, string) error { return errors.ErrNotFound },
+			404,
+		},
+		{
+			"banned user",
+			`{"reason":"spam"}`,
+			func(int64, string) error { return errors.ErrUserBanned },
+			409,
+		},
+		{
+			"valid payload",
+			`{"reason":"spam"}`,
+			func(int64, string) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin ban user controller", t, func() {
+			handler := AdminBanUser(v.banUser)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.POST("/admin/users/:id/ban", handler)
+				req, _ := http.NewRequest("POST", "/admin/users/1/ban", bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminUnbanUser(t *testing.T) {
+	Convey("Given admin unban user controller with user not banned", t, func() {
+		handler := AdminUnbanUser(func(int64, string) error { return errors.ErrUserNotBanned })
+
+		Convey("When unban user", func() {
+			_, resp, r := gin.CreateTestContext()
+			r.POST("/admin/users/:id/unban", handler)
+			req, _ := http.NewRequest("POST", "/admin/users/1/unban", bytes.NewBufferString(`{"reason":"appealed"}`))
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 409", func() {
+				So(resp.Code, ShouldEqual, 409)
+			})
+		})
+	})
+}
+
+func TestAdminCreateAdjustment(t *testing.T) {
+	testdata := []struct {
+		when                  string
+		requestData           string
+		createAdminAdjustment dependencyCreateAdminAdjustment
+		code                  int
+	}{
+		{
+			"zero amount",
+			`{"amount":0,"reason":"compensation"}`,
+			nil,
+			400,
+		},
+		{
+			"empty reason",
+			`{"amount":1}`,
+			nil,
+			400,
+		},
+		{
+			"invalid currency",
+			`{"amount":1,"currency":"xxx","reason":"compensation"}`,
+			nil,
+			400,
+		},
+		{
+			"insufficient balance",
+			`{"amount":-1,"reason":"fraud"}`,
+			func(models.AdminAdjustment) error { return errors.ErrInsufficientBalance },
+			409,
+		},
+		{
+			"errored createAdminAdjustment dependency",
+			`{"amount":1,"reason":"compensation"}`,
+			func(models.AdminAdjustment) error { return fmt.Errorf("") },
+			500,
+		},
+		{
+			"valid payload",
+			`{"amount":1,"currency":"doge","reason":"compensation"}`,
+			func(models.AdminAdjustment) error { return nil },
+			201,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin create adjustment controller", t, func() {
+			handler := AdminCreateAdjustment(mockGetCurrency(), v.createAdminAdjustment)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.POST("/admin/users/:id/adjustments", handler)
+				req, _ := http.NewRequest("POST", "/admin/users/1/adjustments", bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
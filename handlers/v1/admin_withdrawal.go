@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// review reason is visible to admin only
+type adminWithdrawal struct {
+	models.Withdrawal
+	ReviewReason string `json:"review_reason"`
+}
+
+// AdminWithdrawalList returns withdrawals with status given, under review by default, as response
+func AdminWithdrawalList(
+	getWithdrawalsByStatus dependencyGetWithdrawalsByStatus,
+	getNumberOfWithdrawalsByStatus dependencyGetNumberOfWithdrawalsByStatus,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		status, err := strconv.ParseInt(c.DefaultQuery("status", strconv.Itoa(models.WithdrawalStatusReview)), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		withdrawals, err := getWithdrawalsByStatus(status, limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfWithdrawalsByStatus(status)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		result := make([]adminWithdrawal, len(withdrawals))
+		for i := range withdrawals {
+			result[i] = adminWithdrawal{withdrawals[i], withdrawals[i].ReviewReason}
+		}
+
+		c.JSON(http.StatusOK, paginationResult(result, count))
+	}
+}
+
+// AdminApproveWithdrawal releases withdrawal under review to payout job
+func AdminApproveWithdrawal(approveWithdrawal dependencyApproveWithdrawal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		if err := approveWithdrawal(id); err != nil {
+			abortWithWithdrawalReviewError(c, err)
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":         models.EventAdminChange,
+			"ip":            c.ClientIP(),
+			"withdrawal_id": id,
+		}).Info("admin approved withdrawal")
+
+		c.Status(http.StatusOK)
+	}
+}
+
+type rejectWithdrawalPayload struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// AdminRejectWithdrawal rejects withdrawal under review, refunds user and emails the reason
+func AdminRejectWithdrawal(
+	rejectWithdrawal dependencyRejectWithdrawal,
+	getUserByID dependencyGetUserByID,
+	sendEmail dependencySendEmail,
+	tmpl *template.Template,
+	appname string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		payload := rejectWithdrawalPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		withdrawal, err := rejectWithdrawal(id, payload.Reason)
+		if err != nil {
+			abortWithWithdrawalReviewError(c, err)
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":         models.EventAdminChange,
+			"ip":            c.ClientIP(),
+			"withdrawal_id": id,
+			"reason":        payload.Reason,
+		}).Info("admin rejected withdrawal")
+
+		// withdrawal is rejected anyway, failure of notification is only recorded
+		if err := notifyWithdrawalRejected(withdrawal, getUserByID, sendEmail, tmpl, appname); err != nil {
+			c.Error(err)
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+func notifyWithdrawalRejected(
+	withdrawal models.Withdrawal,
+	getUserByID dependencyGetUserByID,
+	sendEmail dependencySendEmail,
+	tmpl *template.Template,
+	appname string,
+) error {
+	user, err := getUserByID(withdrawal.UserID)
+	if err != nil {
+		return err
+	}
+
+	w := bytes.NewBufferString("")
+	if err := tmpl.Execute(w, map[string]interface{}{
+		"appname": appname,
+		"amount":  withdrawal.Amount,
+		"address": withdrawal.Address,
+		"reason":  withdrawal.RejectReason,
+	}); err != nil {
+		return err
+	}
+
+	return sendEmail([]string{user.Email}, fmt.Sprintf("%s --- Your withdrawal is rejected", appname), w.String())
+}
+
+func abortWithWithdrawalReviewError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrNotFound:
+		c.AbortWithError(http.StatusNotFound, err)
+	case errors.ErrWithdrawalNotInReview:
+		c.AbortWithError(http.StatusConflict, err)
+	default:
+		c.AbortWithError(http.StatusInternalServerError, err)
+	}
+}
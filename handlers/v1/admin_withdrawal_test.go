@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestAdminWithdrawalList(t *testing.T) {
+	testdata := []struct {
+		when                           string
+		query                          string
+		getWithdrawalsByStatus         dependencyGetWithdrawalsByStatus
+		getNumberOfWithdrawalsByStatus dependencyGetNumberOfWithdrawalsByStatus
+		code                           int
+	}{
+		{
+			"invalid status",
+			"?status=a",
+			nil,
+			nil,
+			400,
+		},
+		{
+			"errored getWithdrawalsByStatus dependency",
+			"",
+			func(int64, int64, int64) ([]models.Withdrawal, error) { return nil, fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"correct dependencies injected",
+			"",
+			func(status int64, limit, offset int64) ([]models.Withdrawal, error) {
+				return []models.Withdrawal{{Status: status, ReviewReason: "amount above 1"}}, nil
+			},
+			func(int64) (int64, error) { return 1, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin withdrawal list controller", t, func() {
+			handler := AdminWithdrawalList(v.getWithdrawalsByStatus, v.getNumberOfWithdrawalsByStatus)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/admin/withdrawals"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given admin withdrawal list controller with withdrawal under review", t, func() {
+		getWithdrawalsByStatus := func(status int64, limit, offset int64) ([]models.Withdrawal, error) {
+			return []models.Withdrawal{{Status: status, ReviewReason: "amount above 1"}}, nil
+		}
+		getNumberOfWithdrawalsByStatus := func(int64) (int64, error) { return 1, nil }
+		handler := AdminWithdrawalList(getWithdrawalsByStatus, getNumberOfWithdrawalsByStatus)
+
+		Convey("When get withdrawal list", func() {
+			route := "/admin/withdrawals"
+			_, resp, r := gin.CreateTestContext()
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Review reason should be in response", func() {
+				So(resp.Body.String(), ShouldContainSubstring, `"review_reason":"amount above 1"`)
+				So(resp.Body.String(), ShouldContainSubstring, `"status":3`)
+			})
+		})
+	})
+}
+
+func TestAdminApproveWithdrawal(t *testing.T) {
+	testdata := []struct {
+		when              string
+		approveWithdrawal dependencyApproveWithdrawal
+		code              int
+	}{
+		{"non-existing withdrawal", func(int64) error { return errors.ErrNotFound }, 404},
+		{"withdrawal not in review", func(int64) error { return errors.ErrWithdrawalNotInReview }, 409},
+		{"errored approveWithdrawal dependency", func(int64) error { return fmt.Errorf("") }, 500},
+		{"withdrawal in review", func(int64) error { return nil }, 200},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin approve withdrawal controller", t, func() {
+			handler := AdminApproveWithdrawal(v.approveWithdrawal)
+
+			Convey(fmt.Sprintf("When approve %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.POST("/admin/withdrawals/:id/approve", handler)
+				req, _ := http.NewRequest("POST", "/admin/withdrawals/1/approve", nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestAdminRejectWithdrawal(t *testing.T) {
+	tmpl := template.Must(template.New("template").Parse(`reason: {{.reason}}`))
+	rejected := func(int64, string) (models.Withdrawal, error) {
+		return models.Withdrawal{UserID: 1, RejectReason: "fraud"}, nil
+	}
+
+	testdata := []struct {
+		when             string
+		requestData      string
+		rejectWithdrawal dependencyRejectWithdrawal
+		getUserByID      dependencyGetUserByID
+		sendEmail        dependencySendEmail
+		code             int
+	}{
+		{
+			"empty reason",
+			`{}`,
+			nil,
+			nil,
+			nil,
+			400,
+		},
+		{
+			"withdrawal not in review",
+			`{"reason":"fraud"}`,
+			func(int64, string) (models.Withdrawal, error) {
+				return models.Withdrawal{}, errors.ErrWithdrawalNotInReview
+			},
+			nil,
+			nil,
+			409,
+		},
+		{
+			"errored sendEmail dependency",
+			`{"reason":"fraud"}`,
+			rejected,
+			mockGetUserByID(models.User{Email: "e"}, nil),
+			func([]string, string, string) error { return fmt.Errorf("") },
+			200,
+		},
+		{
+			"withdrawal in review",
+			`{"reason":"fraud"}`,
+			rejected,
+			mockGetUserByID(models.User{Email: "e"}, nil),
+			func([]string, string, string) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given admin reject withdrawal controller", t, func() {
+			handler := AdminRejectWithdrawal(v.rejectWithdrawal, v.getUserByID, v.sendEmail, tmpl, "app")
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.POST("/admin/withdrawals/:id/reject", handler)
+				req, _ := http.NewRequest("POST", "/admin/withdrawals/1/reject", bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
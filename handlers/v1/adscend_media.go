@@ -21,6 +21,7 @@ type adscendMediaPayload struct {
 // AdscendMediaCallback handles callback from adscendMedia
 func AdscendMediaCallback(
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getAdscendMediaOffer dependencyGetAdscendMediaOffer,
 	chargebackIncome dependencyChargebackIncome,
 	getSystemConfig dependencyGetSystemConfig,
@@ -71,12 +72,14 @@ func AdscendMediaCallback(
 			return
 		}
 
-		// create income adscendMedia
-		amount := payload.Amount / 1e8
+		// create income adscendMedia in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount / 1e8)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypeAdscendMedia,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -87,11 +90,12 @@ func AdscendMediaCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, amount, "adscend media", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, amount, currency.Code, "adscend media", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
@@ -25,6 +25,7 @@ type clixwallPayload struct {
 func ClixwallCallback(
 	secretPassword string,
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getNumberOfClixwallOffers dependencyGetNumberOfClixwallOffers,
 	getSystemConfig dependencyGetSystemConfig,
 	createClixwallIncome dependencyCreateClixwallIncome,
@@ -66,12 +67,14 @@ func ClixwallCallback(
 			return
 		}
 
-		// create income clixwall
-		amount := payload.Amount
+		// create income clixwall in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypeClixwall,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -82,11 +85,12 @@ func ClixwallCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, payload.Amount, "clixwall", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, payload.Amount, currency.Code, "clixwall", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
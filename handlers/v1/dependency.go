@@ -11,12 +11,36 @@ import (
 // dependencies
 type (
 	// user
-	dependencyGetUserByID         func(int64) (models.User, error)
-	dependencyGetUserByEmail      func(string) (models.User, error)
-	dependencyCreateUser          func(models.User) error
-	dependencyUpdateUserStatus    func(int64, string) error
-	dependencyGetReferees         func(userID int64, limit, offset int64) ([]models.User, error)
-	dependencyGetNumberOfReferees func(userID int64) (int64, error)
+	dependencyGetUserByID              func(int64) (models.User, error)
+	dependencyGetUserByEmail           func(string) (models.User, error)
+	dependencyCreateUser               func(models.User) error
+	dependencyUpdateUserStatus         func(int64, string) error
+	dependencyGetReferees              func(userID int64, limit, offset int64) ([]models.User, error)
+	dependencyGetNumberOfReferees      func(userID int64) (int64, error)
+	dependencyUpdateUserAutoWithdrawal func(id int64, autoWithdrawal bool) error
+	dependencyUpdateUserTOTP           func(id int64, secret string, enabled bool) error
+	dependencyUseTOTPCounter           func(id int64, counter int64) error
+	dependencyUpdateUserCurrency       func(id int64, currency string) error
+
+	// currency
+	dependencyGetCurrency     func(code string) (models.Currency, bool)
+	dependencyGetUserBalance  func(userID int64, currency string) (models.UserBalance, error)
+	dependencyGetUserBalances func(userID int64) ([]models.UserBalance, error)
+
+	// user address
+	dependencyGetUserAddresses      func(userID int64) ([]models.UserAddress, error)
+	dependencyGetDefaultUserAddress func(userID int64, currency string) (models.UserAddress, error)
+	dependencyCreateUserAddress     func(models.UserAddress) (int64, error)
+	dependencyVerifyUserAddress     func(userID, id int64, token string) error
+	dependencySetDefaultUserAddress func(userID, id int64) error
+	dependencyDeleteUserAddress     func(userID, id int64) error
+
+	// referral campaign
+	dependencyCreateReferralCampaign          func(models.ReferralCampaign) (int64, error)
+	dependencyGetReferralCampaignByCode       func(code string) (models.ReferralCampaign, error)
+	dependencyGetReferralCampaigns            func(userID int64, currency string, limit, offset int64) ([]models.ReferralCampaign, error)
+	dependencyGetNumberOfReferralCampaigns    func(userID int64) (int64, error)
+	dependencyIncrementReferralCampaignClicks func(code string) error
 
 	// auth token
 	dependencyCreateAuthToken func(models.AuthToken) error
@@ -30,17 +54,75 @@ type (
 	dependencySendEmail func(recipients []string, subject string, html string) error
 
 	// total reward
-	dependencyGetLatestTotalReward func() models.TotalReward
-	dependencyIncrementTotalReward func(time.Time, int64) error
+	dependencyGetLatestTotalReward func(currency string) models.TotalReward
 
 	// reward rate
-	dependencyGetRewardRatesByType func(string) []models.RewardRate
+	dependencyGetRewardRatesByCountry func(currency, country, rewardRateType string) []models.RewardRate
+
+	// geo
+	dependencyGetCountryByIP func(ip string) string
+
+	// level
+	dependencyGetLevels func() models.Levels
+
+	// streak bonus
+	dependencyGetStreakBonuses func() models.StreakBonuses
+
+	// achievement
+	dependencyGetAchievements     func() models.Achievements
+	dependencyGetUserAchievements func(userID int64) ([]models.UserAchievement, error)
+	dependencyGetAchievementStats func(userID int64) (models.AchievementStats, error)
+
+	// reward rule
+	dependencyGetRewardRules func() models.RewardRules
+
+	// fair seed
+	dependencyGetActiveFairSeed func(userID int64) (models.FairSeed, error)
+	dependencyRotateFairSeed    func(models.FairSeed) (models.FairSeed, error)
+	dependencyGetFairRoll       func(serverSeedHash string, nonce int64) (models.FairRoll, error)
 
 	// system config
-	dependencyGetSystemConfig func() models.Config
+	dependencyGetSystemConfig    func() models.Config
+	dependencyGetConfigs         func(limit, offset int64) ([]models.Config, error)
+	dependencyGetNumberOfConfigs func() (int64, error)
+	dependencyCreateConfig       func(models.Config) (int64, error)
+
+	// reward rate management
+	dependencyGetAllRewardRates func() ([]models.RewardRate, error)
+	dependencyCreateRewardRate  func(models.RewardRate) error
+	dependencyUpdateRewardRate  func(models.RewardRate) error
+	dependencyDeleteRewardRate  func(id int64) error
+
+	// user management
+	dependencyGetUsers              func(q models.UserQuery, limit, offset int64) ([]models.User, error)
+	dependencyGetNumberOfUsers      func(q models.UserQuery) (int64, error)
+	dependencyBanUser               func(id int64, reason string) error
+	dependencyUnbanUser             func(id int64, reason string) error
+	dependencyGetUserStatusLogs     func(userID int64) ([]models.UserStatusLog, error)
+	dependencyCreateAdminAdjustment func(models.AdminAdjustment) error
+	dependencyGetAdminAdjustments   func(userID int64) ([]models.AdminAdjustment, error)
+
+	// role
+	dependencyGetRoles                  func() ([]models.Role, error)
+	dependencyGetRolePermissions        func() models.RolePermissions
+	dependencyUpdateUserRole            func(userID int64, role string) error
+	dependencyGetAdminAuditLogs         func(limit, offset int64) ([]models.AdminAuditLog, error)
+	dependencyGetNumberOfAdminAuditLogs func() (int64, error)
+
+	// stats
+	dependencyGetUserStats         func(since time.Time) (models.UserStats, error)
+	dependencyGetWithdrawalStats   func(currency string) ([]models.WithdrawalStats, error)
+	dependencyGetLiabilities       func(currency string) (float64, error)
+	dependencyGetTotalRewards      func(currency string, since, until time.Time) ([]models.TotalReward, error)
+	dependencyGetOfferwallRevenues func(since, until time.Time) ([]models.OfferwallRevenue, error)
+	dependencyGetWalletBalance     func(currency string) (float64, error)
+	dependencyGetWalletRunway      func(currency string) (models.WalletRunway, error)
+
+	// cache
+	dependencyUpdateCache func()
 
 	// income
-	dependencyCreateRewardIncome          func(models.Income, time.Time) error
+	dependencyCreateRewardIncome          func(models.Income, time.Time, *models.FairRoll) error
 	dependencyCreateSuperrewardsIncome    func(income models.Income, transactionID, offerID string) error
 	dependencyCreateKiwiwallIncome        func(income models.Income, transactionID, offerID string) error
 	dependencyCreateAdscendMediaIncome    func(income models.Income, transactionID, offerID string) error
@@ -55,6 +137,10 @@ type (
 	dependencyGetNumberOfOfferwallIncomes func(userID int64) (int64, error)
 	dependencyInsertIncome                func(interface{}) // cache for broadcasting
 	dependencyChargebackIncome            func(incomeID int64) error
+	dependencyGetRefereeIncomes           func(q models.RefereeIncomeQuery, limit, offset int64) ([]models.RefereeIncome, error)
+	dependencyGetNumberOfRefereeIncomes   func(q models.RefereeIncomeQuery) (int64, error)
+	dependencyGetIncomes                  func(userID int64, limit, offset int64) ([]models.Income, error)
+	dependencyGetNumberOfIncomes          func(userID int64) (int64, error)
 
 	// websocket
 	dependencyPutConn          func(*websocket.Conn)
@@ -65,10 +151,28 @@ type (
 	// withdrawals
 	dependencyGetWithdrawals         func(userID int64, limit, offset int64) ([]models.Withdrawal, error)
 	dependencyGetNumberOfWithdrawals func(userID int64) (int64, error)
-	dependencyConstructTxURL         func(tx string) string
+	dependencyCreateWithdrawal       func(withdrawal models.Withdrawal, interval time.Duration) error
+	dependencyConstructTxURL         func(currency, tx string) string
+
+	// withdrawal review
+	dependencyGetWithdrawalsByStatus         func(status int64, limit, offset int64) ([]models.Withdrawal, error)
+	dependencyGetNumberOfWithdrawalsByStatus func(status int64) (int64, error)
+	dependencyApproveWithdrawal              func(id int64) error
+	dependencyRejectWithdrawal               func(id int64, reason string) (models.Withdrawal, error)
+
+	// alipay withdrawal reconciliation
+	dependencyUpdatePendingWithdrawalStatusToProcessing func() ([]models.Withdrawal, error)
+	dependencyUpdateWithdrawalStatusToProcessed         func(ids []int64, transactionID string) error
+	dependencyFailWithdrawal                            func(id int64, reason string) (models.Withdrawal, error)
+
+	// payout batch review
+	dependencyGetPayoutBatchesByStatus              func(status int64, limit, offset int64) ([]models.PayoutBatch, error)
+	dependencyGetNumberOfPayoutBatchesByStatus      func(status int64) (int64, error)
+	dependencyGetProcessingWithdrawalsByPayoutBatch func(batchID string) ([]models.Withdrawal, error)
+	dependencyResolvePayoutBatchWithdrawal          func(batchID string, id int64, status int64, transactionID, reason string) (models.Withdrawal, error)
 
 	// validation
-	dependencyValidateAddress func(string) (bool, error)
+	dependencyValidateAddress func(currency, address string) (bool, error)
 
 	// captcha
 	dependencyRegisterCaptcha func() (string, error)
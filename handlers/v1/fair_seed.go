@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/utils"
+)
+
+// FairSeedInfo returns user's active fair seed with server seed hidden as response
+func FairSeedInfo(getActiveFairSeed dependencyGetActiveFairSeed) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		seed, err := getActiveFairSeed(authToken.UserID)
+		switch err {
+		case nil:
+		case errors.ErrNotFound:
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		default:
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, seed.Masked())
+	}
+}
+
+type rotateFairSeedPayload struct {
+	ClientSeed string `json:"client_seed" binding:"required,max=64"`
+}
+
+// RotateFairSeed reveals user's active fair seed and commits to a new server seed with client seed given
+func RotateFairSeed(rotateFairSeed dependencyRotateFairSeed) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		payload := rotateFairSeedPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		serverSeed := utils.NewServerSeed()
+		seed := models.FairSeed{
+			UserID:         authToken.UserID,
+			ServerSeed:     serverSeed,
+			ServerSeedHash: utils.HashServerSeed(serverSeed),
+			ClientSeed:     payload.ClientSeed,
+			Status:         models.FairSeedStatusActive,
+			CreatedAt:      time.Now(),
+		}
+		revealed, err := rotateFairSeed(seed)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		response := struct {
+			Active   models.FairSeed  `json:"active"`
+			Revealed *models.FairSeed `json:"revealed"`
+		}{Active: seed.Masked()}
+		if revealed.ID > 0 {
+			response.Revealed = &revealed
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// VerifyFairSeed reproduces the reward of a claim with revealed server seed and reward rates recorded at roll time,
+// reward is the amount before level, streak and other multipliers are applied
+func VerifyFairSeed(getFairRoll dependencyGetFairRoll) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serverSeed := c.Query("server_seed")
+		clientSeed := c.Query("client_seed")
+		if serverSeed == "" || clientSeed == "" {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("server_seed and client_seed are required"))
+			return
+		}
+
+		nonce, err := strconv.ParseInt(c.Query("nonce"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		serverSeedHash := utils.HashServerSeed(serverSeed)
+		roll, err := getFairRoll(serverSeedHash, nonce)
+		switch err {
+		case nil:
+		case errors.ErrNotFound:
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		default:
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		rates := []models.RewardRate{}
+		if err := json.Unmarshal([]byte(roll.RewardRates), &rates); err != nil || len(rates) == 0 {
+			c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("invalid reward rates of fair roll %v: %v", roll.ID, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"server_seed_hash": serverSeedHash,
+			"client_seed":      clientSeed,
+			"nonce":            nonce,
+			"currency":         roll.Currency,
+			"reward_rates":     rates,
+			"rolled_reward":    roll.Reward,
+			"reward":           utils.FairReward(rates, serverSeed, clientSeed, nonce),
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestFairSeedInfo(t *testing.T) {
+	testdata := []struct {
+		when              string
+		getActiveFairSeed dependencyGetActiveFairSeed
+		code              int
+	}{
+		{
+			"no active fair seed",
+			func(int64) (models.FairSeed, error) { return models.FairSeed{}, errors.ErrNotFound },
+			404,
+		},
+		{
+			"errored getActiveFairSeed dependency",
+			func(int64) (models.FairSeed, error) { return models.FairSeed{}, fmt.Errorf("") },
+			500,
+		},
+		{
+			"active fair seed",
+			func(int64) (models.FairSeed, error) { return models.FairSeed{}, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given fair seed info controller", t, func() {
+			handler := FairSeedInfo(v.getActiveFairSeed)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/fair_seeds"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestRotateFairSeed(t *testing.T) {
+	testdata := []struct {
+		when           string
+		requestData    string
+		rotateFairSeed dependencyRotateFairSeed
+		code           int
+	}{
+		{
+			"invalid json data",
+			"huhu",
+			nil,
+			400,
+		},
+		{
+			"errored rotateFairSeed dependency",
+			`{"client_seed":"lucky"}`,
+			func(models.FairSeed) (models.FairSeed, error) { return models.FairSeed{}, fmt.Errorf("") },
+			500,
+		},
+		{
+			"valid payload",
+			`{"client_seed":"lucky"}`,
+			func(models.FairSeed) (models.FairSeed, error) { return models.FairSeed{ID: 1}, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given rotate fair seed controller", t, func() {
+			handler := RotateFairSeed(v.rotateFairSeed)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/fair_seeds"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.PUT(route, handler)
+				req, _ := http.NewRequest("PUT", route, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestVerifyFairSeed(t *testing.T) {
+	roll := models.FairRoll{RewardRates: `[{"min":1,"max":10,"weight":1}]`}
+	testdata := []struct {
+		when        string
+		query       string
+		getFairRoll dependencyGetFairRoll
+		code        int
+	}{
+		{"missing seeds", "?nonce=1", nil, 400},
+		{"invalid nonce", "?server_seed=s&client_seed=c&nonce=x", nil, 400},
+		{"unrolled nonce", "?server_seed=s&client_seed=c&nonce=1", mockGetFairRoll(models.FairRoll{}, errors.ErrNotFound), 404},
+		{"errored getFairRoll dependency", "?server_seed=s&client_seed=c&nonce=1", mockGetFairRoll(models.FairRoll{}, fmt.Errorf("")), 500},
+		{"invalid recorded reward rates", "?server_seed=s&client_seed=c&nonce=1", mockGetFairRoll(models.FairRoll{RewardRates: "x"}, nil), 500},
+		{"valid query", "?server_seed=s&client_seed=c&nonce=1", mockGetFairRoll(roll, nil), 200},
+	}
+
+	for _, v := range testdata {
+		Convey("Given verify fair seed controller", t, func() {
+			handler := VerifyFairSeed(v.getFairRoll)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/fair_seeds/verify"
+				_, resp, r := gin.CreateTestContext()
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package v1
 
 import (
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
 )
 
 func min(v1, v2 int64) int64 {
@@ -38,3 +42,45 @@ func paginationResult(result interface{}, count int64) interface{} {
 		"data":  result,
 	}
 }
+
+const (
+	dateLayout           = "2006-01-02"
+	dateRangeDefaultDays = 30
+	dateRangeMaxDays     = 366
+)
+
+// parse date range [since, until] from query, last 30 days by default,
+// e.g. ?since=2016-10-01&until=2016-10-31, until is returned exclusive
+func parseDateRange(c *gin.Context, now time.Time) (since, until time.Time, err error) {
+	until = now.UTC().Truncate(24 * time.Hour)
+	if v := c.Query("until"); v != "" {
+		if until, err = time.Parse(dateLayout, v); err != nil {
+			return
+		}
+	}
+
+	since = until.AddDate(0, 0, 1-dateRangeDefaultDays)
+	if v := c.Query("since"); v != "" {
+		if since, err = time.Parse(dateLayout, v); err != nil {
+			return
+		}
+	}
+
+	// until is inclusive in query string but exclusive in storage
+	until = until.AddDate(0, 0, 1)
+	if !since.Before(until) || until.Sub(since) > dateRangeMaxDays*24*time.Hour {
+		err = fmt.Errorf("invalid date range [%v, %v)", since, until)
+	}
+
+	return
+}
+
+// parse currency from query, base currency by default, e.g. ?currency=doge
+func parseCurrency(c *gin.Context, getCurrency dependencyGetCurrency) (models.Currency, error) {
+	currency, ok := getCurrency(c.Query("currency"))
+	if !ok {
+		return currency, errors.ErrInvalidCurrency
+	}
+
+	return currency, nil
+}
@@ -22,6 +22,7 @@ type kiwiwallPayload struct {
 // KiwiwallCallback handles callback from kiwiwall
 func KiwiwallCallback(
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getNumberOfKiwiwallOffers dependencyGetNumberOfKiwiwallOffers,
 	getSystemConfig dependencyGetSystemConfig,
 	createKiwiwallIncome dependencyCreateKiwiwallIncome,
@@ -60,12 +61,14 @@ func KiwiwallCallback(
 			return
 		}
 
-		// create income kiwiwall
-		amount := payload.Amount / 1e8
+		// create income kiwiwall in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount / 1e8)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypeKiwiwall,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -76,11 +79,12 @@ func KiwiwallCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, amount, "kiwiwall", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, amount, currency.Code, "kiwiwall", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
@@ -21,6 +21,7 @@ type offertoroPayload struct {
 // OffertoroCallback handles callback from offertoro
 func OffertoroCallback(
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getNumberOfOffertoroOffers dependencyGetNumberOfOffertoroOffers,
 	getSystemConfig dependencyGetSystemConfig,
 	createOffertoroIncome dependencyCreateOffertoroIncome,
@@ -58,12 +59,14 @@ func OffertoroCallback(
 			return
 		}
 
-		// create income offertoro
-		amount := payload.Amount / 1e8
+		// create income offertoro in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount / 1e8)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypeOffertoro,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -74,11 +77,12 @@ func OffertoroCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, amount, "offertoro", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, amount, currency.Code, "offertoro", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
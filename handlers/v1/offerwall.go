@@ -37,3 +37,13 @@ func OfferwallList(
 		c.JSON(http.StatusOK, paginationResult(offerwalls, count))
 	}
 }
+
+// currency offerwall earnings of user go to, base currency if the one chosen is not enabled
+func offerwallCurrency(getCurrency dependencyGetCurrency, user models.User) models.Currency {
+	if currency, ok := getCurrency(user.Currency); ok {
+		return currency
+	}
+
+	currency, _ := getCurrency("")
+	return currency
+}
@@ -20,6 +20,7 @@ type personalyPayload struct {
 // PersonalyCallback handles callback from personaly
 func PersonalyCallback(
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getNumberOfPersonalyOffers dependencyGetNumberOfPersonalyOffers,
 	getSystemConfig dependencyGetSystemConfig,
 	createPersonalyIncome dependencyCreatePersonalyIncome,
@@ -56,12 +57,14 @@ func PersonalyCallback(
 			return
 		}
 
-		// create income personaly
-		amount := payload.Amount / 1e8
+		// create income personaly in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount / 1e8)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypePersonaly,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -72,11 +75,12 @@ func PersonalyCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, amount, "personaly", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, amount, currency.Code, "personaly", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// PromotionList returns active and upcoming reward rules as response
+func PromotionList(getRewardRules dependencyGetRewardRules) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now()
+
+		active, upcoming := models.RewardRules{}, models.RewardRules{}
+		for _, rule := range getRewardRules() {
+			switch {
+			case rule.ActiveAt(now):
+				active = append(active, rule)
+			case !rule.Expired(now):
+				upcoming = append(upcoming, rule)
+			}
+		}
+
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"active":   active,
+			"upcoming": upcoming,
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestPromotionList(t *testing.T) {
+	Convey("Given promotion list controller", t, func() {
+		past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+		handler := PromotionList(mockGetRewardRules(models.RewardRules{
+			{Name: "active"},
+			{Name: "upcoming", StartsAt: &future},
+			{Name: "expired", EndsAt: &past},
+		}))
+
+		Convey("When get promotion list", func() {
+			route := "/promotions"
+			_, resp, r := gin.CreateTestContext()
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 200", func() {
+				So(resp.Code, ShouldEqual, 200)
+			})
+		})
+	})
+}
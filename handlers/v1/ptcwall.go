@@ -21,6 +21,7 @@ type ptcwallPayload struct {
 // PtcwallCallback handles callback from ptcwall
 func PtcwallCallback(
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getSystemConfig dependencyGetSystemConfig,
 	createPtcwallIncome dependencyCreatePtcwallIncome,
 	broadcast dependencyBroadcast,
@@ -44,12 +45,14 @@ func PtcwallCallback(
 			return
 		}
 
-		// create income ptcwall
-		amount := payload.Amount / 1e8
+		// create income ptcwall in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount / 1e8)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypePtcwall,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -60,11 +63,12 @@ func PtcwallCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, payload.Amount, "ptcwall", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, payload.Amount, currency.Code, "ptcwall", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
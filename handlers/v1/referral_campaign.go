@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+type referralCampaignPayload struct {
+	Code string `json:"code" binding:"required,alphanum,min=3,max=31"`
+	Name string `json:"name" binding:"required,max=63"`
+}
+
+// CreateReferralCampaign creates a new referral campaign with unique code for user
+func CreateReferralCampaign(createReferralCampaign dependencyCreateReferralCampaign) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		payload := referralCampaignPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		campaign := models.ReferralCampaign{
+			UserID: authToken.UserID,
+			Code:   payload.Code,
+			Name:   payload.Name,
+		}
+		id, err := createReferralCampaign(campaign)
+		if err != nil {
+			switch err {
+			case errors.ErrDuplicatedReferralCode:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+		campaign.ID = id
+
+		c.JSON(http.StatusCreated, campaign)
+	}
+}
+
+// ReferralCampaignList returns user's referral campaigns with clicks, signups and earnings in currency of query as response
+func ReferralCampaignList(
+	getCurrency dependencyGetCurrency,
+	getReferralCampaigns dependencyGetReferralCampaigns,
+	getNumberOfReferralCampaigns dependencyGetNumberOfReferralCampaigns,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		currency, err := parseCurrency(c, getCurrency)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		campaigns, err := getReferralCampaigns(authToken.UserID, currency.Code, limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfReferralCampaigns(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(campaigns, count))
+	}
+}
+
+// ReferralCampaignRedirect counts a click on referral campaign link and redirects to app
+func ReferralCampaignRedirect(
+	incrementReferralCampaignClicks dependencyIncrementReferralCampaignClicks,
+	appurl string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		code := c.Param("code")
+
+		// redirect to app anyway, link should never be broken for visitors
+		location := appurl
+		switch err := incrementReferralCampaignClicks(code); err {
+		case nil:
+			location = fmt.Sprintf("%s?referral_code=%s", appurl, url.QueryEscape(code))
+		case errors.ErrNotFound:
+		default:
+			c.Error(err)
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":         models.EventReferralCampaignClick,
+			"referral_code": code,
+			"ip":            c.ClientIP(),
+		}).Debug("referral campaign link clicked")
+
+		c.Redirect(http.StatusFound, location)
+	}
+}
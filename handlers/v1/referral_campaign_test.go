@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestCreateReferralCampaign(t *testing.T) {
+	testdata := []struct {
+		when                   string
+		requestData            string
+		createReferralCampaign dependencyCreateReferralCampaign
+		code                   int
+	}{
+		{
+			"invalid json data",
+			"huhu",
+			nil,
+			400,
+		},
+		{
+			"invalid code",
+			`{"code":"a-b","name":"banner"}`,
+			nil,
+			400,
+		},
+		{
+			"duplicated code",
+			`{"code":"abc","name":"banner"}`,
+			func(models.ReferralCampaign) (int64, error) { return 0, errors.ErrDuplicatedReferralCode },
+			409,
+		},
+		{
+			"errored createReferralCampaign dependency",
+			`{"code":"abc","name":"banner"}`,
+			func(models.ReferralCampaign) (int64, error) { return 0, fmt.Errorf("") },
+			500,
+		},
+		{
+			"valid payload",
+			`{"code":"abc","name":"banner"}`,
+			func(models.ReferralCampaign) (int64, error) { return 3, nil },
+			201,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given create referral campaign controller", t, func() {
+			handler := CreateReferralCampaign(v.createReferralCampaign)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/users/referral_campaigns"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.POST(route, handler)
+				req, _ := http.NewRequest("POST", route, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given create referral campaign controller", t, func() {
+		handler := CreateReferralCampaign(func(models.ReferralCampaign) (int64, error) { return 3, nil })
+
+		Convey("When request with valid payload", func() {
+			route := "/users/referral_campaigns"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route, bytes.NewBufferString(`{"code":"abc","name":"banner"}`))
+			r.ServeHTTP(resp, req)
+
+			Convey("Response should carry id of created campaign", func() {
+				So(resp.Body.String(), ShouldContainSubstring, `"id":3`)
+			})
+		})
+	})
+}
+
+func TestReferralCampaignList(t *testing.T) {
+	Convey("Given referral campaign list controller with errored getReferralCampaigns dependency", t, func() {
+		getReferralCampaigns := func(int64, string, int64, int64) ([]models.ReferralCampaign, error) { return nil, fmt.Errorf("") }
+		handler := ReferralCampaignList(mockGetCurrency(), getReferralCampaigns, nil)
+
+		Convey("When get referral campaign list with invalid limit", func() {
+			route := "/users/referral_campaigns"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route+"?limit=3i", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 400", func() {
+				So(resp.Code, ShouldEqual, 400)
+			})
+		})
+
+		Convey("When get referral campaign list with invalid currency", func() {
+			route := "/users/referral_campaigns"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route+"?currency=xxx", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 400", func() {
+				So(resp.Code, ShouldEqual, 400)
+			})
+		})
+
+		Convey("When get referral campaign list", func() {
+			route := "/users/referral_campaigns"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 500", func() {
+				So(resp.Code, ShouldEqual, 500)
+			})
+		})
+	})
+
+	Convey("Given referral campaign list controller with correct dependencies injected", t, func() {
+		getReferralCampaigns := func(int64, string, int64, int64) ([]models.ReferralCampaign, error) { return nil, nil }
+		getNumberOfReferralCampaigns := func(int64) (int64, error) { return 0, nil }
+		handler := ReferralCampaignList(mockGetCurrency(), getReferralCampaigns, getNumberOfReferralCampaigns)
+
+		Convey("When get referral campaign list", func() {
+			route := "/users/referral_campaigns"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 200", func() {
+				So(resp.Code, ShouldEqual, 200)
+			})
+		})
+	})
+}
+
+func TestReferralCampaignRedirect(t *testing.T) {
+	testdata := []struct {
+		when     string
+		err      error
+		location string
+	}{
+		{"existing referral code", nil, "http://app.url?referral_code=abc"},
+		{"non-existing referral code", errors.ErrNotFound, "http://app.url"},
+		{"errored incrementReferralCampaignClicks dependency", fmt.Errorf(""), "http://app.url"},
+	}
+
+	for _, v := range testdata {
+		Convey("Given referral campaign redirect controller", t, func() {
+			handler := ReferralCampaignRedirect(func(string) error { return v.err }, "http://app.url")
+
+			Convey(fmt.Sprintf("When click link with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.GET("/referral_campaigns/:code", handler)
+				req, _ := http.NewRequest("GET", "/referral_campaigns/abc", nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response should redirect to %s", v.location), func() {
+					So(resp.Code, ShouldEqual, http.StatusFound)
+					So(resp.Header().Get("Location"), ShouldEqual, v.location)
+				})
+			})
+		})
+	}
+}
@@ -7,16 +7,24 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/models"
 	"github.com/solefaucet/sole-server/utils"
 )
 
-// GetReward randomly gives users reward
+// GetReward randomly gives users reward in currency of query, base currency by default
 func GetReward(
+	getCurrency dependencyGetCurrency,
 	getUserByID dependencyGetUserByID,
+	getUserBalance dependencyGetUserBalance,
 	getLatestTotalReward dependencyGetLatestTotalReward,
 	getSystemConfig dependencyGetSystemConfig,
-	getRewardRatesByType dependencyGetRewardRatesByType,
+	getRewardRatesByCountry dependencyGetRewardRatesByCountry,
+	getCountryByIP dependencyGetCountryByIP,
+	getLevels dependencyGetLevels,
+	getStreakBonuses dependencyGetStreakBonuses,
+	getRewardRules dependencyGetRewardRules,
+	getActiveFairSeed dependencyGetActiveFairSeed,
 	createRewardIncome dependencyCreateRewardIncome,
 	cacheIncome dependencyInsertIncome,
 	broadcast dependencyBroadcast,
@@ -25,6 +33,12 @@ func GetReward(
 		authToken := c.MustGet("auth_token").(models.AuthToken)
 		now := time.Now()
 
+		currency, err := parseCurrency(c, getCurrency)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
 		// get user
 		user, err := getUserByID(authToken.UserID)
 		if err != nil {
@@ -32,66 +46,122 @@ func GetReward(
 			return
 		}
 
-		// check last rewarded time
-		if user.RewardedAt.Add(time.Second * time.Duration(user.RewardInterval)).After(now) {
+		balance, err := getUserBalance(user.ID, currency.Code)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		// check last rewarded time of currency, reward interval of currency is shortened by level
+		levels := getLevels()
+		level := levels.Of(user.XP)
+		interval := level.RewardIntervalOf(currency.RewardIntervalOf(user))
+		if balance.RewardedAt.Add(time.Second * time.Duration(interval)).After(now) {
 			c.AbortWithStatus(statusCodeTooManyRequests)
 			return
 		}
 
-		// get random reward
+		// get random reward, threshold is configured in base currency
 		config := getSystemConfig()
-		latestTotalReward := getLatestTotalReward()
+		latestTotalReward := getLatestTotalReward(currency.Code)
 		rewardRateType := models.RewardRateTypeLess
-		if latestTotalReward.IsSameDay(now) && latestTotalReward.Total > config.TotalRewardThreshold {
+		if latestTotalReward.IsSameDay(now) && latestTotalReward.Total > currency.FromBase(config.TotalRewardThreshold) {
 			rewardRateType = models.RewardRateTypeMore
 		}
-		rewardRates := getRewardRatesByType(rewardRateType)
-		reward := utils.RandomReward(rewardRates)
-		rewardReferer := reward * config.RefererRewardRate
-
-		// double reward if needed
-		doubled := config.DoubleToday()
-		if doubled {
-			reward *= 2
+		country := getCountryByIP(c.ClientIP())
+		rewardRates := getRewardRatesByCountry(currency.Code, country, rewardRateType)
+		if len(rewardRates) == 0 {
+			// faucet is not claimable in currency without reward rates
+			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidCurrency)
+			return
 		}
 
+		// roll provably fair reward if user has committed to a fair seed,
+		// nonce is used and the roll is recorded along with the income
+		var reward float64
+		var roll *models.FairRoll
+		fairSeed, err := getActiveFairSeed(user.ID)
+		switch err {
+		case nil:
+			reward = utils.FairReward(rewardRates, fairSeed.ServerSeed, fairSeed.ClientSeed, fairSeed.Nonce)
+			// keep rates of the roll, cached rates may change before user verifies it
+			rates, _ := json.Marshal(rewardRates)
+			roll = &models.FairRoll{
+				FairSeedID:  fairSeed.ID,
+				Nonce:       fairSeed.Nonce,
+				Currency:    currency.Code,
+				RewardRates: string(rates),
+				Reward:      reward,
+			}
+		case errors.ErrNotFound:
+			reward = utils.RandomReward(rewardRates)
+		default:
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		// referer reward rate is raised by referer's level
+		referer, _ := getUserByID(user.RefererID)
+		rewardReferer := reward * levels.Of(referer.XP).RefererRewardRateOf(config)
+
+		// stack multipliers of promotion rules, some of them apply to referer as well
+		ruleMultiplier, refererRuleMultiplier, rules := getRewardRules().Apply(now, user, level.Level)
+		rewardReferer *= refererRuleMultiplier
+
+		// multiply reward by level, streak bonus and promotion rules
+		streak := user.NextStreak(now)
+		streakBonus := getStreakBonuses().Of(streak)
+		reward = utils.ToFixed(reward*level.RewardMultiplier*streakBonus.Multiplier*ruleMultiplier, 8)
+
 		// create income reward
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypeReward,
+			Currency:      currency.Code,
 			Income:        reward,
 			RefererIncome: rewardReferer,
 		}
-		if err := createRewardIncome(income, now); err != nil {
-			c.AbortWithError(http.StatusInternalServerError, err)
+		if err := createRewardIncome(income, now, roll); err != nil {
+			switch err {
+			case errors.ErrFairSeedChanged:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
 			return
 		}
 
 		// cache delta income
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, reward, "reward", now}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, reward, currency.Code, "reward", now}
 		cacheIncome(deltaIncome)
 
 		// broadcast delta income to all clients
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
-		referer, _ := getUserByID(user.RefererID)
 		logrus.WithFields(logrus.Fields{
 			"event":            models.EventReward,
 			"user_email":       user.Email,
 			"user_address":     user.Address,
 			"user_ip":          c.ClientIP(),
-			"user_rewarded_at": user.RewardedAt,
+			"country":          country,
+			"currency":         currency.Code,
+			"user_rewarded_at": balance.RewardedAt,
 			"referer_email":    referer.Email,
 			"reward_rate_type": rewardRateType,
+			"level":            level.Level,
+			"streak_days":      streak,
+			"fair_seed_id":     fairSeed.ID,
+			"fair_seed_nonce":  fairSeed.Nonce,
 			"amount":           reward,
-			"reward_doubled":   doubled,
+			"reward_rules":     rules.Names(),
 		}).Info("user get reward")
 
 		c.JSON(http.StatusOK, income)
@@ -8,13 +8,54 @@ import (
 
 	"github.com/gin-gonic/gin"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/models"
 )
 
 func TestGetReward(t *testing.T) {
+	Convey("Given get reward controller", t, func() {
+		handler := GetReward(mockGetCurrency(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		Convey("When get reward in invalid currency", func() {
+			route := "/incomes/rewards"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route+"?currency=xxx", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 400", func() {
+				So(resp.Code, ShouldEqual, 400)
+			})
+		})
+	})
+
 	Convey("Given get reward controller with errored getUserByID dependency", t, func() {
 		getUserByID := mockGetUserByID(models.User{}, fmt.Errorf(""))
-		handler := GetReward(getUserByID, nil, nil, nil, nil, nil, nil)
+		handler := GetReward(mockGetCurrency(), getUserByID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		Convey("When get reward", func() {
+			route := "/incomes/rewards"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 500", func() {
+				So(resp.Code, ShouldEqual, 500)
+			})
+		})
+	})
+
+	Convey("Given get reward controller with errored getUserBalance dependency", t, func() {
+		getUserByID := mockGetUserByID(models.User{}, nil)
+		getUserBalance := mockGetUserBalance(models.UserBalance{}, fmt.Errorf(""))
+		handler := GetReward(mockGetCurrency(), getUserByID, getUserBalance, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		Convey("When get reward", func() {
 			route := "/incomes/rewards"
@@ -33,8 +74,53 @@ func TestGetReward(t *testing.T) {
 	})
 
 	Convey("Given get reward controller with not valid last_rewarded", t, func() {
-		getUserByID := mockGetUserByID(models.User{RewardedAt: time.Now(), RewardInterval: 5}, nil)
-		handler := GetReward(getUserByID, nil, nil, nil, nil, nil, nil)
+		getUserByID := mockGetUserByID(models.User{RewardInterval: 5}, nil)
+		getUserBalance := mockGetUserBalance(models.UserBalance{RewardedAt: time.Now().Add(-10 * time.Second)}, nil)
+		handler := GetReward(mockGetCurrency(), getUserByID, getUserBalance, nil, nil, nil, nil, mockGetLevels(nil), nil, nil, nil, nil, nil, nil)
+
+		Convey("When get reward in currency with longer reward interval", func() {
+			route := "/incomes/rewards"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route+"?currency=doge", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 429", func() {
+				So(resp.Code, ShouldEqual, statusCodeTooManyRequests)
+			})
+		})
+	})
+
+	Convey("Given get reward controller without reward rates of currency", t, func() {
+		getUserByID := mockGetUserByID(models.User{}, nil)
+		getUserBalance := mockGetUserBalance(models.UserBalance{}, nil)
+		getLatestTotalReward := mockGetLatestTotalReward(models.TotalReward{})
+		getSystemConfig := mockGetSystemConfig(models.Config{})
+		handler := GetReward(mockGetCurrency(), getUserByID, getUserBalance, getLatestTotalReward, getSystemConfig, mockGetRewardRatesByCountry(nil), mockGetCountryByIP("US"), mockGetLevels(nil), nil, nil, nil, nil, nil, nil)
+
+		Convey("When get reward", func() {
+			route := "/incomes/rewards"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route+"?currency=doge", nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 400", func() {
+				So(resp.Code, ShouldEqual, 400)
+			})
+		})
+	})
+
+	Convey("Given get reward controller with not valid last_rewarded of currency", t, func() {
+		getUserByID := mockGetUserByID(models.User{RewardInterval: 5}, nil)
+		getUserBalance := mockGetUserBalance(models.UserBalance{RewardedAt: time.Now()}, nil)
+		handler := GetReward(mockGetCurrency(), getUserByID, getUserBalance, nil, nil, nil, nil, mockGetLevels(nil), nil, nil, nil, nil, nil, nil)
 
 		Convey("When get reward", func() {
 			route := "/incomes/rewards"
@@ -56,13 +142,13 @@ func TestGetReward(t *testing.T) {
 		getUserByID := mockGetUserByID(models.User{}, nil)
 		getLatestTotalReward := mockGetLatestTotalReward(models.TotalReward{CreatedAt: time.Now(), Total: 11})
 		getSystemConfig := mockGetSystemConfig(models.Config{TotalRewardThreshold: 10, RefererRewardRate: 10})
-		getRewardRatesByType := mockGetRewardRatesByType([]models.RewardRate{
+		getRewardRatesByCountry := mockGetRewardRatesByCountry([]models.RewardRate{
 			{Weight: 1, Min: 1, Max: 10},
 			{Weight: 2, Min: 11, Max: 20},
 			{Weight: 3, Min: 21, Max: 30},
 		})
 		createRewardIncome := mockCreateRewardIncome(fmt.Errorf(""))
-		handler := GetReward(getUserByID, getLatestTotalReward, getSystemConfig, getRewardRatesByType, createRewardIncome, nil, nil)
+		handler := GetReward(mockGetCurrency(), getUserByID, mockGetUserBalance(models.UserBalance{}, nil), getLatestTotalReward, getSystemConfig, getRewardRatesByCountry, mockGetCountryByIP("US"), mockGetLevels(nil), mockGetStreakBonuses(nil), mockGetRewardRules(nil), mockGetActiveFairSeed(models.FairSeed{}, errors.ErrNotFound), createRewardIncome, nil, nil)
 
 		Convey("When get reward", func() {
 			route := "/incomes/rewards"
@@ -80,11 +166,36 @@ func TestGetReward(t *testing.T) {
 		})
 	})
 
+	Convey("Given get reward controller with fair seed used by another claim", t, func() {
+		getUserByID := mockGetUserByID(models.User{}, nil)
+		getLatestTotalReward := mockGetLatestTotalReward(models.TotalReward{})
+		getSystemConfig := mockGetSystemConfig(models.Config{})
+		getRewardRatesByCountry := mockGetRewardRatesByCountry([]models.RewardRate{{Weight: 1, Min: 1, Max: 10}})
+		getActiveFairSeed := mockGetActiveFairSeed(models.FairSeed{ServerSeed: "server", ClientSeed: "client"}, nil)
+		createRewardIncome := mockCreateRewardIncome(errors.ErrFairSeedChanged)
+		handler := GetReward(mockGetCurrency(), getUserByID, mockGetUserBalance(models.UserBalance{}, nil), getLatestTotalReward, getSystemConfig, getRewardRatesByCountry, mockGetCountryByIP("US"), mockGetLevels(nil), mockGetStreakBonuses(nil), mockGetRewardRules(nil), getActiveFairSeed, createRewardIncome, nil, nil)
+
+		Convey("When get reward", func() {
+			route := "/incomes/rewards"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 409", func() {
+				So(resp.Code, ShouldEqual, 409)
+			})
+		})
+	})
+
 	Convey("Given get reward controller with everything correctly configured", t, func() {
 		getUserByID := mockGetUserByID(models.User{}, nil)
 		getLatestTotalReward := mockGetLatestTotalReward(models.TotalReward{CreatedAt: time.Now().UTC(), Total: 11})
 		getSystemConfig := mockGetSystemConfig(models.Config{TotalRewardThreshold: 10, RefererRewardRate: 10})
-		getRewardRatesByType := mockGetRewardRatesByType([]models.RewardRate{
+		getRewardRatesByCountry := mockGetRewardRatesByCountry([]models.RewardRate{
 			{Weight: 1, Min: 1, Max: 10},
 			{Weight: 2, Min: 11, Max: 20},
 			{Weight: 3, Min: 21, Max: 30},
@@ -92,10 +203,17 @@ func TestGetReward(t *testing.T) {
 		createRewardIncome := mockCreateRewardIncome(nil)
 		insertIncome := mockInsertIncome()
 		broadcast := mockBroadcast()
-		handler := GetReward(getUserByID,
+		handler := GetReward(mockGetCurrency(),
+			getUserByID,
+			mockGetUserBalance(models.UserBalance{}, nil),
 			getLatestTotalReward,
 			getSystemConfig,
-			getRewardRatesByType,
+			getRewardRatesByCountry,
+			mockGetCountryByIP("US"),
+			mockGetLevels(models.Levels{{Level: 1, RewardMultiplier: 1}, {Level: 2, MinXP: 100, RewardMultiplier: 1.5}}),
+			mockGetStreakBonuses(models.StreakBonuses{{Days: 1, Multiplier: 1.1}}),
+			mockGetRewardRules(models.RewardRules{{Name: "double", Multiplier: 2, ApplyToReferer: true, Final: true}, {Name: "skipped", Multiplier: 3}}),
+			mockGetActiveFairSeed(models.FairSeed{ServerSeed: "server", ClientSeed: "client"}, nil),
 			createRewardIncome,
 			insertIncome,
 			broadcast,
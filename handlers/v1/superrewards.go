@@ -26,6 +26,7 @@ type superrewardPayload struct {
 func SuperrewardsCallback(
 	secretKey string,
 	getUserByID dependencyGetUserByID,
+	getCurrency dependencyGetCurrency,
 	getNumberOfSuperrewardsOffers dependencyGetNumberOfSuperrewardsOffers,
 	getSystemConfig dependencyGetSystemConfig,
 	createSuperrewardsIncome dependencyCreateSuperrewardsIncome,
@@ -69,12 +70,14 @@ func SuperrewardsCallback(
 			return
 		}
 
-		// create income superrewards
-		amount := payload.Amount / 1e8
+		// create income superrewards in currency user chose, offerwall pays in base currency
+		currency := offerwallCurrency(getCurrency, user)
+		amount := currency.FromBase(payload.Amount / 1e8)
 		income := models.Income{
 			UserID:        user.ID,
 			RefererID:     user.RefererID,
 			Type:          models.IncomeTypeSuperrewards,
+			Currency:      currency.Code,
 			Income:        amount,
 			RefererIncome: amount * getSystemConfig().RefererRewardRate,
 		}
@@ -85,11 +88,12 @@ func SuperrewardsCallback(
 
 		// broadcast delta income to all clients
 		deltaIncome := struct {
-			Address string    `json:"address"`
-			Amount  float64   `json:"amount"`
-			Type    string    `json:"type"`
-			Time    time.Time `json:"time"`
-		}{user.Address, amount, "superrewards", time.Now()}
+			Address  string    `json:"address"`
+			Amount   float64   `json:"amount"`
+			Currency string    `json:"currency"`
+			Type     string    `json:"type"`
+			Time     time.Time `json:"time"`
+		}{user.Address, amount, currency.Code, "superrewards", time.Now()}
 		msg, _ := json.Marshal(models.WebsocketMessage{DeltaIncome: deltaIncome})
 		broadcast(msg)
 
@@ -12,7 +12,7 @@ import (
 
 func TestSuperrewardsCallback(t *testing.T) {
 	Convey("Given superrewards callback handler with invalid parameters", t, func() {
-		handler := SuperrewardsCallback("", nil, nil, nil, nil, nil)
+		handler := SuperrewardsCallback("", nil, mockGetCurrency(), nil, nil, nil, nil)
 
 		Convey("When callback", func() {
 			route := "/callback"
@@ -28,7 +28,7 @@ func TestSuperrewardsCallback(t *testing.T) {
 	})
 
 	Convey("Given superrewards callback handler with invalid signature", t, func() {
-		handler := SuperrewardsCallback("secret", nil, nil, nil, nil, nil)
+		handler := SuperrewardsCallback("secret", nil, mockGetCurrency(), nil, nil, nil, nil)
 		query := "id=id&uid=1&new=13.2&sig=4b2ae6c496"
 
 		Convey("When callback", func() {
@@ -46,7 +46,7 @@ func TestSuperrewardsCallback(t *testing.T) {
 
 	Convey("Given superrewards callback handler with errored getUserByID", t, func() {
 		getUserByID := mockGetUserByID(models.User{}, fmt.Errorf(""))
-		handler := SuperrewardsCallback("secret", getUserByID, nil, nil, nil, nil)
+		handler := SuperrewardsCallback("secret", getUserByID, mockGetCurrency(), nil, nil, nil, nil)
 		query := "id=id&uid=1&new=13.2&sig=4b2ae6c496f862b258e8b6b9d3242257"
 
 		Convey("When callback", func() {
@@ -65,7 +65,7 @@ func TestSuperrewardsCallback(t *testing.T) {
 	Convey("Given superrewards callback handler with non-err-not-found errored getNumberOfSuperrewardsOffers", t, func() {
 		getUserByID := mockGetUserByID(models.User{}, nil)
 		getNumberOfSuperrewardsOffers := mockGetNumberOfSuperrewardsOffers(0, fmt.Errorf(""))
-		handler := SuperrewardsCallback("secret", getUserByID, getNumberOfSuperrewardsOffers, nil, nil, nil)
+		handler := SuperrewardsCallback("secret", getUserByID, mockGetCurrency(), getNumberOfSuperrewardsOffers, nil, nil, nil)
 		query := "id=id&uid=1&new=13.2&sig=4b2ae6c496f862b258e8b6b9d3242257"
 
 		Convey("When callback", func() {
@@ -84,7 +84,7 @@ func TestSuperrewardsCallback(t *testing.T) {
 	Convey("Given superrewards callback handler with getNumberOfSuperrewardsOffers returning nil error", t, func() {
 		getUserByID := mockGetUserByID(models.User{}, nil)
 		getNumberOfSuperrewardsOffers := mockGetNumberOfSuperrewardsOffers(2, nil)
-		handler := SuperrewardsCallback("secret", getUserByID, getNumberOfSuperrewardsOffers, nil, nil, nil)
+		handler := SuperrewardsCallback("secret", getUserByID, mockGetCurrency(), getNumberOfSuperrewardsOffers, nil, nil, nil)
 		query := "id=id&uid=1&new=13.2&sig=4b2ae6c496f862b258e8b6b9d3242257"
 
 		Convey("When callback", func() {
@@ -109,7 +109,7 @@ func TestSuperrewardsCallback(t *testing.T) {
 		getNumberOfSuperrewardsOffers := mockGetNumberOfSuperrewardsOffers(0, nil)
 		getSystemConfig := mockGetSystemConfig(models.Config{})
 		createSuperrewardsIncome := mockCreateSuperrewardsIncome(fmt.Errorf(""))
-		handler := SuperrewardsCallback("secret", getUserByID, getNumberOfSuperrewardsOffers, getSystemConfig, createSuperrewardsIncome, nil)
+		handler := SuperrewardsCallback("secret", getUserByID, mockGetCurrency(), getNumberOfSuperrewardsOffers, getSystemConfig, createSuperrewardsIncome, nil)
 		query := "id=id&uid=1&new=13.2&sig=4b2ae6c496f862b258e8b6b9d3242257"
 
 		Convey("When callback", func() {
@@ -130,7 +130,7 @@ func TestSuperrewardsCallback(t *testing.T) {
 		getNumberOfSuperrewardsOffers := mockGetNumberOfSuperrewardsOffers(0, nil)
 		getSystemConfig := mockGetSystemConfig(models.Config{})
 		createSuperrewardsIncome := mockCreateSuperrewardsIncome(nil)
-		handler := SuperrewardsCallback("secret", getUserByID, getNumberOfSuperrewardsOffers, getSystemConfig, createSuperrewardsIncome, func([]byte) {})
+		handler := SuperrewardsCallback("secret", getUserByID, mockGetCurrency(), getNumberOfSuperrewardsOffers, getSystemConfig, createSuperrewardsIncome, func([]byte) {})
 		query := "id=id&uid=1&new=13.2&sig=4b2ae6c496f862b258e8b6b9d3242257"
 
 		Convey("When callback", func() {
@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/utils"
+)
+
+type totpPayload struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GenerateTOTP generates a new TOTP secret for user to set up authenticator,
+// it takes effect only after being enabled with a valid code
+func GenerateTOTP(
+	getUserByID dependencyGetUserByID,
+	updateUserTOTP dependencyUpdateUserTOTP,
+	issuer string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		user, err := getUserByID(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if user.TOTPEnabled {
+			c.AbortWithStatus(http.StatusConflict)
+			return
+		}
+
+		secret := utils.NewTOTPSecret()
+		if err := updateUserTOTP(user.ID, secret, false); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, map[string]interface{}{
+			"secret": secret,
+			"url":    utils.TOTPURL(issuer, user.Email, secret),
+		})
+	}
+}
+
+// EnableTOTP requires TOTP code on withdrawal once user proves the secret is set up
+func EnableTOTP(
+	getUserByID dependencyGetUserByID,
+	useTOTPCounter dependencyUseTOTPCounter,
+	updateUserTOTP dependencyUpdateUserTOTP,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := userWithTOTPCode(c, getUserByID, useTOTPCounter)
+		if !ok {
+			return
+		}
+
+		if user.TOTPEnabled {
+			c.AbortWithStatus(http.StatusConflict)
+			return
+		}
+
+		if err := updateUserTOTP(user.ID, user.TOTPSecret, true); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// DisableTOTP removes TOTP secret of user, a valid code is required as well
+func DisableTOTP(
+	getUserByID dependencyGetUserByID,
+	useTOTPCounter dependencyUseTOTPCounter,
+	updateUserTOTP dependencyUpdateUserTOTP,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := userWithTOTPCode(c, getUserByID, useTOTPCounter)
+		if !ok {
+			return
+		}
+
+		if !user.TOTPEnabled {
+			c.AbortWithStatus(http.StatusConflict)
+			return
+		}
+
+		if err := updateUserTOTP(user.ID, "", false); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// get authorized user and check code in payload against user's TOTP secret,
+// code is used up so that it cannot be replayed,
+// request is aborted if returned ok is false
+func userWithTOTPCode(c *gin.Context, getUserByID dependencyGetUserByID, useTOTPCounter dependencyUseTOTPCounter) (models.User, bool) {
+	authToken := c.MustGet("auth_token").(models.AuthToken)
+
+	payload := totpPayload{}
+	if err := c.BindJSON(&payload); err != nil {
+		return models.User{}, false
+	}
+
+	user, err := getUserByID(authToken.UserID)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return user, false
+	}
+
+	counter, ok := utils.ValidateTOTP(user.TOTPSecret, payload.Code, time.Now())
+	if !ok {
+		c.AbortWithError(http.StatusForbidden, errors.ErrInvalidTOTPCode)
+		return user, false
+	}
+
+	return user, useTOTPCode(c, useTOTPCounter, user.ID, counter)
+}
+
+// use TOTP code of counter, request is aborted if returned ok is false
+func useTOTPCode(c *gin.Context, useTOTPCounter dependencyUseTOTPCounter, userID, counter int64) bool {
+	switch err := useTOTPCounter(userID, counter); err {
+	case nil:
+		return true
+	case errors.ErrInvalidTOTPCode:
+		c.AbortWithError(http.StatusForbidden, err)
+	default:
+		c.AbortWithError(http.StatusInternalServerError, err)
+	}
+	return false
+}
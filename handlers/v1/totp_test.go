@@ -0,0 +1,210 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/utils"
+)
+
+func mockUpdateUserTOTP(err error) dependencyUpdateUserTOTP {
+	return func(int64, string, bool) error {
+		return err
+	}
+}
+
+func mockUseTOTPCounter(err error) dependencyUseTOTPCounter {
+	return func(int64, int64) error {
+		return err
+	}
+}
+
+func TestGenerateTOTP(t *testing.T) {
+	testdata := []struct {
+		when           string
+		getUserByID    dependencyGetUserByID
+		updateUserTOTP dependencyUpdateUserTOTP
+		code           int
+	}{
+		{
+			"errored getUserByID dependency",
+			mockGetUserByID(models.User{}, fmt.Errorf("")),
+			nil,
+			500,
+		},
+		{
+			"totp already enabled",
+			mockGetUserByID(models.User{TOTPEnabled: true}, nil),
+			nil,
+			409,
+		},
+		{
+			"errored updateUserTOTP dependency",
+			mockGetUserByID(models.User{}, nil),
+			mockUpdateUserTOTP(fmt.Errorf("")),
+			500,
+		},
+		{
+			"correct dependencies",
+			mockGetUserByID(models.User{}, nil),
+			mockUpdateUserTOTP(nil),
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given generate totp controller", t, func() {
+			handler := GenerateTOTP(v.getUserByID, v.updateUserTOTP, "sole")
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/users/totp"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.POST(route, handler)
+				req, _ := http.NewRequest("POST", route, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestEnableAndDisableTOTP(t *testing.T) {
+	secret := utils.NewTOTPSecret()
+	code, _ := utils.TOTPCode(secret, time.Now())
+	validPayload := fmt.Sprintf(`{"code":"%s"}`, code)
+
+	testdata := []struct {
+		when           string
+		handler        func(dependencyGetUserByID, dependencyUseTOTPCounter, dependencyUpdateUserTOTP) gin.HandlerFunc
+		requestData    string
+		getUserByID    dependencyGetUserByID
+		useTOTPCounter dependencyUseTOTPCounter
+		updateUserTOTP dependencyUpdateUserTOTP
+		code           int
+	}{
+		{
+			"enable without code",
+			EnableTOTP,
+			`{}`,
+			nil,
+			nil,
+			nil,
+			400,
+		},
+		{
+			"enable with errored getUserByID dependency",
+			EnableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{}, fmt.Errorf("")),
+			nil,
+			nil,
+			500,
+		},
+		{
+			"enable with invalid code",
+			EnableTOTP,
+			`{"code":"abc123"}`,
+			mockGetUserByID(models.User{TOTPSecret: secret}, nil),
+			nil,
+			nil,
+			403,
+		},
+		{
+			"enable with replayed code",
+			EnableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{TOTPSecret: secret}, nil),
+			mockUseTOTPCounter(errors.ErrInvalidTOTPCode),
+			nil,
+			403,
+		},
+		{
+			"enable with errored useTOTPCounter dependency",
+			EnableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{TOTPSecret: secret}, nil),
+			mockUseTOTPCounter(fmt.Errorf("")),
+			nil,
+			500,
+		},
+		{
+			"enable when already enabled",
+			EnableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{TOTPSecret: secret, TOTPEnabled: true}, nil),
+			mockUseTOTPCounter(nil),
+			nil,
+			409,
+		},
+		{
+			"enable with errored updateUserTOTP dependency",
+			EnableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{TOTPSecret: secret}, nil),
+			mockUseTOTPCounter(nil),
+			mockUpdateUserTOTP(fmt.Errorf("")),
+			500,
+		},
+		{
+			"enable with valid code",
+			EnableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{TOTPSecret: secret}, nil),
+			mockUseTOTPCounter(nil),
+			mockUpdateUserTOTP(nil),
+			200,
+		},
+		{
+			"disable when not enabled",
+			DisableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{TOTPSecret: secret}, nil),
+			mockUseTOTPCounter(nil),
+			nil,
+			409,
+		},
+		{
+			"disable with valid code",
+			DisableTOTP,
+			validPayload,
+			mockGetUserByID(models.User{TOTPSecret: secret, TOTPEnabled: true}, nil),
+			mockUseTOTPCounter(nil),
+			mockUpdateUserTOTP(nil),
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given totp controller", t, func() {
+			handler := v.handler(v.getUserByID, v.useTOTPCounter, v.updateUserTOTP)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/users/totp"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.PATCH(route, handler)
+				req, _ := http.NewRequest("PATCH", route, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -12,9 +13,10 @@ import (
 )
 
 type signupPayload struct {
-	Email     string `json:"email" binding:"required,email"`
-	Address   string `json:"address" binding:"required"`
-	RefererID int64  `json:"referer_id,omitempty" binding:"-"`
+	Email        string `json:"email" binding:"required,email"`
+	Address      string `json:"address" binding:"required"`
+	RefererID    int64  `json:"referer_id,omitempty" binding:"-"`
+	ReferralCode string `json:"referral_code,omitempty" binding:"-"`
 }
 
 func userWithSignupPayload(p signupPayload) models.User {
@@ -30,13 +32,15 @@ func Signup(
 	validateAddress dependencyValidateAddress,
 	createUser dependencyCreateUser,
 	getUserByID dependencyGetUserByID,
+	getReferralCampaignByCode dependencyGetReferralCampaignByCode,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		payload := signupPayload{}
 		if err := c.BindJSON(&payload); err != nil {
 			return
 		}
-		valid, _ := validateAddress(payload.Address)
+		// signup address is of base currency
+		valid, _ := validateAddress("", payload.Address)
 		if !valid {
 			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidAddress)
 			return
@@ -47,6 +51,14 @@ func Signup(
 		referer, _ := getUserByID(payload.RefererID)
 		user.RefererID = referer.ID
 
+		// referral campaign takes precedence over referer_id
+		if payload.ReferralCode != "" {
+			if campaign, err := getReferralCampaignByCode(payload.ReferralCode); err == nil {
+				user.RefererID = campaign.UserID
+				user.ReferralCampaignID = campaign.ID
+			}
+		}
+
 		if err := createUser(user); err != nil {
 			switch err {
 			case errors.ErrDuplicatedEmail:
@@ -60,9 +72,10 @@ func Signup(
 		}
 
 		logrus.WithFields(logrus.Fields{
-			"event":   models.EventUserSignup,
-			"email":   payload.Email,
-			"address": payload.Address,
+			"event":         models.EventUserSignup,
+			"email":         payload.Email,
+			"address":       payload.Address,
+			"referral_code": payload.ReferralCode,
 		}).Info("succeed to signup user")
 
 		c.JSON(http.StatusOK, user)
@@ -113,8 +126,43 @@ func VerifyEmail(
 	}
 }
 
-// UserInfo returns user's info as response
-func UserInfo(getUserByID dependencyGetUserByID) gin.HandlerFunc {
+type levelProgress struct {
+	Level       int64 `json:"level"`
+	XP          int64 `json:"xp"`
+	NextLevel   int64 `json:"next_level,omitempty"`
+	NextLevelXP int64 `json:"next_level_xp,omitempty"`
+}
+
+func levelProgressOf(levels models.Levels, xp int64) levelProgress {
+	p := levelProgress{Level: levels.Of(xp).Level, XP: xp}
+	if next, ok := levels.Next(xp); ok {
+		p.NextLevel, p.NextLevelXP = next.Level, next.MinXP
+	}
+	return p
+}
+
+type streakProgress struct {
+	Days           int64   `json:"days"`
+	Multiplier     float64 `json:"multiplier"`
+	NextDays       int64   `json:"next_days,omitempty"`
+	NextMultiplier float64 `json:"next_multiplier,omitempty"`
+}
+
+func streakProgressOf(bonuses models.StreakBonuses, days int64) streakProgress {
+	p := streakProgress{Days: days, Multiplier: bonuses.Of(days).Multiplier}
+	if next, ok := bonuses.Next(days); ok {
+		p.NextDays, p.NextMultiplier = next.Days, next.Multiplier
+	}
+	return p
+}
+
+// UserInfo returns user's info with balances of every currency as response
+func UserInfo(
+	getUserByID dependencyGetUserByID,
+	getUserBalances dependencyGetUserBalances,
+	getLevels dependencyGetLevels,
+	getStreakBonuses dependencyGetStreakBonuses,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authToken := c.MustGet("auth_token").(models.AuthToken)
 
@@ -128,7 +176,72 @@ func UserInfo(getUserByID dependencyGetUserByID) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, user)
+		balances, err := getUserBalances(user.ID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, struct {
+			models.User
+			Balances []models.UserBalance `json:"balances"`
+			Level    levelProgress        `json:"level"`
+			Streak   streakProgress       `json:"streak"`
+		}{
+			user,
+			balances,
+			levelProgressOf(getLevels(), user.XP),
+			streakProgressOf(getStreakBonuses(), user.CurrentStreak(time.Now())),
+		})
+	}
+}
+
+type userSettingsPayload struct {
+	AutoWithdrawal *bool   `json:"auto_withdrawal" binding:"-"`
+	Currency       *string `json:"currency" binding:"-"`
+}
+
+// UpdateUserSettings updates user's settings, e.g. opt out of auto withdrawal or choose currency offerwall earnings go to
+func UpdateUserSettings(
+	getCurrency dependencyGetCurrency,
+	updateUserAutoWithdrawal dependencyUpdateUserAutoWithdrawal,
+	updateUserCurrency dependencyUpdateUserCurrency,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		payload := userSettingsPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		// validator treats false as missing, check presence manually
+		if payload.AutoWithdrawal == nil && payload.Currency == nil {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("auto_withdrawal or currency is required"))
+			return
+		}
+
+		if payload.Currency != nil {
+			currency, ok := getCurrency(*payload.Currency)
+			if !ok {
+				c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidCurrency)
+				return
+			}
+
+			if err := updateUserCurrency(authToken.UserID, currency.Code); err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		if payload.AutoWithdrawal != nil {
+			if err := updateUserAutoWithdrawal(authToken.UserID, *payload.AutoWithdrawal); err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				return
+			}
+		}
+
+		c.Status(http.StatusOK)
 	}
 }
 
@@ -162,3 +275,69 @@ func RefereeList(
 		c.JSON(http.StatusOK, paginationResult(referees, count))
 	}
 }
+
+// RefereeIncomeList returns referer commission per referee aggregated by day or week as response
+func RefereeIncomeList(
+	getRefereeIncomes dependencyGetRefereeIncomes,
+	getNumberOfRefereeIncomes dependencyGetNumberOfRefereeIncomes,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		// parse pagination args
+		limit, offset, err := parsePagination(c)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		q, err := parseRefereeIncomeQuery(c, authToken.UserID, time.Now())
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		incomes, err := getRefereeIncomes(q, limit, offset)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		count, err := getNumberOfRefereeIncomes(q)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, paginationResult(incomes, count))
+	}
+}
+
+// parse date range [since, until], period and sort from query
+// e.g. ?since=2016-10-01&until=2016-10-31&period=week&sort=-amount
+func parseRefereeIncomeQuery(c *gin.Context, refererID int64, now time.Time) (models.RefereeIncomeQuery, error) {
+	q := models.RefereeIncomeQuery{RefererID: refererID}
+
+	var err error
+	if q.Since, q.Until, err = parseDateRange(c, now); err != nil {
+		return q, err
+	}
+
+	q.Period = c.DefaultQuery("period", models.RefereeIncomePeriodDay)
+	switch q.Period {
+	case models.RefereeIncomePeriodDay, models.RefereeIncomePeriodWeek:
+	default:
+		return q, fmt.Errorf("invalid period %v", q.Period)
+	}
+
+	sort := c.DefaultQuery("sort", "-"+models.RefereeIncomeSortByPeriod)
+	q.Desc = strings.HasPrefix(sort, "-")
+	q.SortBy = strings.TrimPrefix(sort, "-")
+	switch q.SortBy {
+	case models.RefereeIncomeSortByPeriod, models.RefereeIncomeSortByAmount, models.RefereeIncomeSortByRefereeID:
+	default:
+		return q, fmt.Errorf("invalid sort %v", sort)
+	}
+
+	return q, nil
+}
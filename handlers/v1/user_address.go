@@ -0,0 +1,224 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/satori/go.uuid"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// UserAddressList returns user's address book as response
+func UserAddressList(getUserAddresses dependencyGetUserAddresses) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		addresses, err := getUserAddresses(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, addresses)
+	}
+}
+
+type userAddressPayload struct {
+	Address  string `json:"address" binding:"required"`
+	Currency string `json:"currency" binding:"-"`
+	Label    string `json:"label" binding:"max=31"`
+}
+
+// CreateUserAddress adds an address of currency, base currency if not specified, to user's address book,
+// verification url is sent via email as the address can not be made default until verified,
+// address is removed again if the email fails so that user can simply retry
+func CreateUserAddress(
+	getCurrency dependencyGetCurrency,
+	validateAddress dependencyValidateAddress,
+	getUserByID dependencyGetUserByID,
+	createUserAddress dependencyCreateUserAddress,
+	deleteUserAddress dependencyDeleteUserAddress,
+	sendEmail dependencySendEmail,
+	tmpl *template.Template,
+	appname string,
+	appurl string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		payload := userAddressPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+		currency, ok := getCurrency(payload.Currency)
+		if !ok {
+			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidCurrency)
+			return
+		}
+
+		payload.Address = strings.TrimSpace(payload.Address)
+		valid, _ := validateAddress(currency.Code, payload.Address)
+		if !valid {
+			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidAddress)
+			return
+		}
+
+		user, err := getUserByID(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		address := models.UserAddress{
+			UserID:      user.ID,
+			Currency:    currency.Code,
+			Address:     payload.Address,
+			Label:       payload.Label,
+			VerifyToken: uuid.NewV4().String(),
+		}
+		address.ID, err = createUserAddress(address)
+		if err != nil {
+			switch err {
+			case errors.ErrDuplicatedAddress, errors.ErrTooManyAddresses:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		// send email
+		w := bytes.NewBufferString("")
+		tmpl.Execute(w, map[string]interface{}{
+			"appname":  appname,
+			"url":      appurl,
+			"currency": address.Currency,
+			"address":  address.Address,
+			"id":       address.ID,
+			"token":    url.QueryEscape(address.VerifyToken),
+		})
+		if err := sendEmail([]string{user.Email}, fmt.Sprintf("%s --- Verify your withdrawal address", appname), w.String()); err != nil {
+			if err := deleteUserAddress(user.ID, address.ID); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"event":   models.EventUserAddressChange,
+					"email":   user.Email,
+					"id":      address.ID,
+					"address": address.Address,
+					"error":   err.Error(),
+				}).Error("failed to delete address whose verification email is not sent")
+			}
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":    models.EventUserAddressChange,
+			"email":    user.Email,
+			"currency": address.Currency,
+			"address":  address.Address,
+			"action":   "create",
+		}).Info("user added address")
+
+		c.JSON(http.StatusCreated, address)
+	}
+}
+
+// VerifyUserAddress verifies user's address with token sent via email
+func VerifyUserAddress(verifyUserAddress dependencyVerifyUserAddress) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		token := c.Query("token")
+		if token == "" {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if err := verifyUserAddress(authToken.UserID, id, token); err != nil {
+			switch err {
+			case errors.ErrNotFound:
+				c.AbortWithError(http.StatusNotFound, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// SetDefaultUserAddress makes user's verified address the one withdrawals of its currency are paid to
+func SetDefaultUserAddress(setDefaultUserAddress dependencySetDefaultUserAddress) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		if err := setDefaultUserAddress(authToken.UserID, id); err != nil {
+			switch err {
+			case errors.ErrNotFound:
+				c.AbortWithError(http.StatusNotFound, err)
+			case errors.ErrAddressNotVerified:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":   models.EventUserAddressChange,
+			"user_id": authToken.UserID,
+			"id":      id,
+			"action":  "set default",
+		}).Info("user changed default address")
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// DeleteUserAddress removes address other than default from user's address book
+func DeleteUserAddress(deleteUserAddress dependencyDeleteUserAddress) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		if err := deleteUserAddress(authToken.UserID, id); err != nil {
+			switch err {
+			case errors.ErrNotFound:
+				c.AbortWithError(http.StatusNotFound, err)
+			case errors.ErrDefaultAddress:
+				c.AbortWithError(http.StatusConflict, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
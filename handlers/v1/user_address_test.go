@@ -0,0 +1,392 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestUserAddressList(t *testing.T) {
+	testdata := []struct {
+		when             string
+		getUserAddresses dependencyGetUserAddresses
+		code             int
+	}{
+		{
+			"errored getUserAddresses dependency",
+			func(int64) ([]models.UserAddress, error) { return nil, fmt.Errorf("") },
+			500,
+		},
+		{
+			"correct dependencies",
+			func(int64) ([]models.UserAddress, error) { return []models.UserAddress{{IsDefault: true}}, nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given user address list controller", t, func() {
+			handler := UserAddressList(v.getUserAddresses)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/users/addresses"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestCreateUserAddress(t *testing.T) {
+	validAddress := func(string, string) (bool, error) { return true, nil }
+	createUserAddress := func(err error) dependencyCreateUserAddress {
+		return func(models.UserAddress) (int64, error) { return 2, err }
+	}
+
+	testdata := []struct {
+		when              string
+		requestData       string
+		validateAddress   dependencyValidateAddress
+		getUserByID       dependencyGetUserByID
+		createUserAddress dependencyCreateUserAddress
+		deleteUserAddress dependencyDeleteUserAddress
+		sendEmail         dependencySendEmail
+		code              int
+	}{
+		{
+			"invalid json data",
+			"huhu",
+			nil, nil, nil, nil, nil,
+			400,
+		},
+		{
+			"invalid currency",
+			`{"address":"abc","currency":"xxx"}`,
+			nil, nil, nil, nil, nil,
+			400,
+		},
+		{
+			"invalid address",
+			`{"address":"abc","label":"segwit"}`,
+			func(string, string) (bool, error) { return false, nil },
+			nil, nil, nil, nil,
+			400,
+		},
+		{
+			"errored getUserByID dependency",
+			`{"address":"abc","label":"segwit"}`,
+			validAddress,
+			mockGetUserByID(models.User{}, fmt.Errorf("")),
+			nil, nil, nil,
+			500,
+		},
+		{
+			"full address book",
+			`{"address":"abc","label":"segwit"}`,
+			validAddress,
+			mockGetUserByID(models.User{}, nil),
+			createUserAddress(errors.ErrTooManyAddresses),
+			nil, nil,
+			409,
+		},
+		{
+			"duplicated address",
+			`{"address":"abc","label":"segwit"}`,
+			validAddress,
+			mockGetUserByID(models.User{}, nil),
+			createUserAddress(errors.ErrDuplicatedAddress),
+			nil, nil,
+			409,
+		},
+		{
+			"errored createUserAddress dependency",
+			`{"address":"abc","label":"segwit"}`,
+			validAddress,
+			mockGetUserByID(models.User{}, nil),
+			createUserAddress(fmt.Errorf("")),
+			nil, nil,
+			500,
+		},
+		{
+			"errored sendEmail dependency",
+			`{"address":"abc","label":"segwit"}`,
+			validAddress,
+			mockGetUserByID(models.User{}, nil),
+			createUserAddress(nil),
+			func(int64, int64) error { return nil },
+			mockSendEmail(fmt.Errorf("")),
+			500,
+		},
+		{
+			"errored sendEmail and deleteUserAddress dependency",
+			`{"address":"abc","label":"segwit"}`,
+			validAddress,
+			mockGetUserByID(models.User{}, nil),
+			createUserAddress(nil),
+			func(int64, int64) error { return fmt.Errorf("") },
+			mockSendEmail(fmt.Errorf("")),
+			500,
+		},
+		{
+			"valid payload",
+			`{"address":"abc","currency":"doge","label":"segwit"}`,
+			validAddress,
+			mockGetUserByID(models.User{}, nil),
+			createUserAddress(nil),
+			nil,
+			mockSendEmail(nil),
+			201,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given create user address controller", t, func() {
+			tmpl := template.Must(template.New("template").Parse(`address: {{.address}} token: {{.token}}`))
+			handler := CreateUserAddress(mockGetCurrency(), v.validateAddress, v.getUserByID, v.createUserAddress, v.deleteUserAddress, v.sendEmail, tmpl, "", "")
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/users/addresses"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.POST(route, handler)
+				req, _ := http.NewRequest("POST", route, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+
+	Convey("Given create user address controller with failing email", t, func() {
+		deletedID := int64(0)
+		tmpl := template.Must(template.New("template").Parse(`address: {{.address}} token: {{.token}}`))
+		handler := CreateUserAddress(
+			mockGetCurrency(),
+			validAddress,
+			mockGetUserByID(models.User{}, nil),
+			createUserAddress(nil),
+			func(userID, id int64) error {
+				deletedID = id
+				return nil
+			},
+			mockSendEmail(fmt.Errorf("")),
+			tmpl, "", "",
+		)
+
+		Convey("When create address", func() {
+			route := "/users/addresses"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.POST(route, handler)
+			req, _ := http.NewRequest("POST", route, bytes.NewBufferString(`{"address":"abc"}`))
+			r.ServeHTTP(resp, req)
+
+			Convey("Address should be deleted", func() {
+				So(resp.Code, ShouldEqual, 500)
+				So(deletedID, ShouldEqual, 2)
+			})
+		})
+	})
+}
+
+func TestVerifyUserAddress(t *testing.T) {
+	testdata := []struct {
+		when              string
+		url               string
+		verifyUserAddress dependencyVerifyUserAddress
+		code              int
+	}{
+		{
+			"invalid id",
+			"/users/addresses/x/verification?token=t",
+			nil,
+			400,
+		},
+		{
+			"empty token",
+			"/users/addresses/2/verification",
+			nil,
+			400,
+		},
+		{
+			"unmatched token",
+			"/users/addresses/2/verification?token=t",
+			func(int64, int64, string) error { return errors.ErrNotFound },
+			404,
+		},
+		{
+			"errored verifyUserAddress dependency",
+			"/users/addresses/2/verification?token=t",
+			func(int64, int64, string) error { return fmt.Errorf("") },
+			500,
+		},
+		{
+			"matched token",
+			"/users/addresses/2/verification?token=t",
+			func(int64, int64, string) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given verify user address controller", t, func() {
+			handler := VerifyUserAddress(v.verifyUserAddress)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.POST("/users/addresses/:id/verification", handler)
+				req, _ := http.NewRequest("POST", v.url, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestSetDefaultUserAddress(t *testing.T) {
+	testdata := []struct {
+		when                  string
+		url                   string
+		setDefaultUserAddress dependencySetDefaultUserAddress
+		code                  int
+	}{
+		{
+			"invalid id",
+			"/users/addresses/x/default",
+			nil,
+			400,
+		},
+		{
+			"address of other user",
+			"/users/addresses/2/default",
+			func(int64, int64) error { return errors.ErrNotFound },
+			404,
+		},
+		{
+			"unverified address",
+			"/users/addresses/2/default",
+			func(int64, int64) error { return errors.ErrAddressNotVerified },
+			409,
+		},
+		{
+			"errored setDefaultUserAddress dependency",
+			"/users/addresses/2/default",
+			func(int64, int64) error { return fmt.Errorf("") },
+			500,
+		},
+		{
+			"verified address",
+			"/users/addresses/2/default",
+			func(int64, int64) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given set default user address controller", t, func() {
+			handler := SetDefaultUserAddress(v.setDefaultUserAddress)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.POST("/users/addresses/:id/default", handler)
+				req, _ := http.NewRequest("POST", v.url, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
+func TestDeleteUserAddress(t *testing.T) {
+	testdata := []struct {
+		when              string
+		url               string
+		deleteUserAddress dependencyDeleteUserAddress
+		code              int
+	}{
+		{
+			"invalid id",
+			"/users/addresses/x",
+			nil,
+			400,
+		},
+		{
+			"address of other user",
+			"/users/addresses/2",
+			func(int64, int64) error { return errors.ErrNotFound },
+			404,
+		},
+		{
+			"default address",
+			"/users/addresses/2",
+			func(int64, int64) error { return errors.ErrDefaultAddress },
+			409,
+		},
+		{
+			"errored deleteUserAddress dependency",
+			"/users/addresses/2",
+			func(int64, int64) error { return fmt.Errorf("") },
+			500,
+		},
+		{
+			"non-default address",
+			"/users/addresses/2",
+			func(int64, int64) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given delete user address controller", t, func() {
+			handler := DeleteUserAddress(v.deleteUserAddress)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.DELETE("/users/addresses/:id", handler)
+				req, _ := http.NewRequest("DELETE", v.url, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
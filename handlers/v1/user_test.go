@@ -35,7 +35,7 @@ func TestSignup(t *testing.T) {
 		code            int
 		getUserByID     dependencyGetUserByID
 		createUser      dependencyCreateUser
-		validateAddress func(string) (bool, error)
+		validateAddress func(string, string) (bool, error)
 	}{
 		{
 			"invalid json data",
@@ -59,7 +59,7 @@ func TestSignup(t *testing.T) {
 			400,
 			nil,
 			nil,
-			func(string) (bool, error) { return false, nil },
+			func(string, string) (bool, error) { return false, nil },
 		},
 		{
 			"duplicate email",
@@ -67,7 +67,7 @@ func TestSignup(t *testing.T) {
 			409,
 			mockGetUserByID(models.User{}, nil),
 			mockCreateUser(errors.ErrDuplicatedEmail),
-			func(string) (bool, error) { return true, nil },
+			func(string, string) (bool, error) { return true, nil },
 		},
 		{
 			"valid email, but create user unknown error",
@@ -75,7 +75,7 @@ func TestSignup(t *testing.T) {
 			500,
 			mockGetUserByID(models.User{}, nil),
 			mockCreateUser(fmt.Errorf("")),
-			func(string) (bool, error) { return true, nil },
+			func(string, string) (bool, error) { return true, nil },
 		},
 		{
 			"valid email",
@@ -83,13 +83,13 @@ func TestSignup(t *testing.T) {
 			200,
 			mockGetUserByID(models.User{}, nil),
 			mockCreateUser(nil),
-			func(string) (bool, error) { return true, nil },
+			func(string, string) (bool, error) { return true, nil },
 		},
 	}
 
 	for _, v := range testdata {
 		Convey("Given Signup controller", t, func() {
-			handler := Signup(v.validateAddress, v.createUser, v.getUserByID)
+			handler := Signup(v.validateAddress, v.createUser, v.getUserByID, nil)
 
 			Convey(fmt.Sprintf("When request with %s", v.when), func() {
 				route := "/users"
@@ -106,6 +106,38 @@ func TestSignup(t *testing.T) {
 	}
 }
 
+func TestSignupWithReferralCode(t *testing.T) {
+	Convey("Given Signup controller with referral campaign", t, func() {
+		createdUser := models.User{}
+		createUser := func(u models.User) error {
+			createdUser = u
+			return nil
+		}
+		getReferralCampaignByCode := mockGetReferralCampaignByCode(models.ReferralCampaign{ID: 3, UserID: 5}, nil)
+		handler := Signup(func(string, string) (bool, error) { return true, nil }, createUser, mockGetUserByID(models.User{ID: 2}, nil), getReferralCampaignByCode)
+
+		Convey("When request with referral code", func() {
+			route := "/users"
+			_, resp, r := gin.CreateTestContext()
+			r.POST(route, handler)
+			raw, _ := json.Marshal(map[string]interface{}{
+				"email":         validEmail,
+				"address":       "address",
+				"referer_id":    2,
+				"referral_code": "code",
+			})
+			req, _ := http.NewRequest("POST", route, bytes.NewBuffer(raw))
+			r.ServeHTTP(resp, req)
+
+			Convey("User should be attributed to referral campaign", func() {
+				So(resp.Code, ShouldEqual, 200)
+				So(createdUser.RefererID, ShouldEqual, 5)
+				So(createdUser.ReferralCampaignID, ShouldEqual, 3)
+			})
+		})
+	})
+}
+
 func TestVerifyEmail(t *testing.T) {
 	Convey("Given verify email controller with expired session and errored getSessionByToken dependency", t, func() {
 		getSessionByToken := mockGetSessionByToken(models.Session{}, fmt.Errorf(""))
@@ -219,7 +251,28 @@ func TestVerifyEmail(t *testing.T) {
 func TestGetUserInfo(t *testing.T) {
 	Convey("Given get user info controller with errored getUserByID dependency", t, func() {
 		getUserByID := mockGetUserByID(models.User{}, errors.ErrNotFound)
-		handler := UserInfo(getUserByID)
+		handler := UserInfo(getUserByID, nil, mockGetLevels(nil), mockGetStreakBonuses(nil))
+
+		Convey("When get user info", func() {
+			route := "/users"
+			_, resp, r := gin.CreateTestContext()
+			r.Use(func(c *gin.Context) {
+				c.Set("auth_token", models.AuthToken{})
+			})
+			r.GET(route, handler)
+			req, _ := http.NewRequest("GET", route, nil)
+			r.ServeHTTP(resp, req)
+
+			Convey("Response code should be 500", func() {
+				So(resp.Code, ShouldEqual, 500)
+			})
+		})
+	})
+
+	Convey("Given get user info controller with errored getUserBalances dependency", t, func() {
+		getUserByID := mockGetUserByID(models.User{}, nil)
+		getUserBalances := func(int64) ([]models.UserBalance, error) { return nil, fmt.Errorf("") }
+		handler := UserInfo(getUserByID, getUserBalances, mockGetLevels(nil), mockGetStreakBonuses(nil))
 
 		Convey("When get user info", func() {
 			route := "/users"
@@ -239,7 +292,8 @@ func TestGetUserInfo(t *testing.T) {
 
 	Convey("Given get user info controller with correctly dependencies injected", t, func() {
 		getUserByID := mockGetUserByID(models.User{}, nil)
-		handler := UserInfo(getUserByID)
+		getUserBalances := func(int64) ([]models.UserBalance, error) { return []models.UserBalance{{Currency: "btc"}}, nil }
+		handler := UserInfo(getUserByID, getUserBalances, mockGetLevels(nil), mockGetStreakBonuses(nil))
 
 		Convey("When get user info", func() {
 			route := "/users"
@@ -258,6 +312,80 @@ func TestGetUserInfo(t *testing.T) {
 	})
 }
 
+func TestUpdateUserSettings(t *testing.T) {
+	testdata := []struct {
+		when                     string
+		requestData              string
+		updateUserAutoWithdrawal dependencyUpdateUserAutoWithdrawal
+		updateUserCurrency       dependencyUpdateUserCurrency
+		code                     int
+	}{
+		{
+			"missing auto_withdrawal and currency",
+			`{}`,
+			nil,
+			nil,
+			400,
+		},
+		{
+			"errored updateUserAutoWithdrawal dependency",
+			`{"auto_withdrawal":false}`,
+			func(int64, bool) error { return fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"valid payload",
+			`{"auto_withdrawal":false}`,
+			func(int64, bool) error { return nil },
+			nil,
+			200,
+		},
+		{
+			"invalid currency",
+			`{"currency":"xxx"}`,
+			nil,
+			nil,
+			400,
+		},
+		{
+			"errored updateUserCurrency dependency",
+			`{"currency":"doge"}`,
+			nil,
+			func(int64, string) error { return fmt.Errorf("") },
+			500,
+		},
+		{
+			"valid payload with currency",
+			`{"currency":"doge","auto_withdrawal":true}`,
+			func(int64, bool) error { return nil },
+			func(int64, string) error { return nil },
+			200,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given update user settings controller", t, func() {
+			handler := UpdateUserSettings(mockGetCurrency(), v.updateUserAutoWithdrawal, v.updateUserCurrency)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/users/settings"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.PATCH(route, handler)
+				req, _ := http.NewRequest("PATCH", route, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
+
 func TestGetReferees(t *testing.T) {
 	Convey("Given referee list handler with errored dependency", t, func() {
 		handler := RefereeList(mockGetReferees(nil, fmt.Errorf("")), nil)
@@ -312,3 +440,52 @@ func TestGetReferees(t *testing.T) {
 		})
 	})
 }
+
+func TestRefereeIncomeList(t *testing.T) {
+	getRefereeIncomes := func(models.RefereeIncomeQuery, int64, int64) ([]models.RefereeIncome, error) { return nil, nil }
+	getNumberOfRefereeIncomes := func(models.RefereeIncomeQuery) (int64, error) { return 0, nil }
+
+	testdata := []struct {
+		when              string
+		query             string
+		getRefereeIncomes dependencyGetRefereeIncomes
+		code              int
+	}{
+		{"invalid limit", "?limit=3i", nil, 400},
+		{"invalid since", "?since=2016-13-01", nil, 400},
+		{"since after until", "?since=2016-10-02&until=2016-10-01", nil, 400},
+		{"date range too large", "?since=2015-01-01&until=2016-10-01", nil, 400},
+		{"invalid period", "?period=month", nil, 400},
+		{"invalid sort", "?sort=-email", nil, 400},
+		{
+			"errored getRefereeIncomes dependency",
+			"",
+			func(models.RefereeIncomeQuery, int64, int64) ([]models.RefereeIncome, error) {
+				return nil, fmt.Errorf("")
+			},
+			500,
+		},
+		{"valid query", "?since=2016-10-01&until=2016-10-31&period=week&sort=-amount", getRefereeIncomes, 200},
+	}
+
+	for _, v := range testdata {
+		Convey("Given referee income list controller", t, func() {
+			handler := RefereeIncomeList(v.getRefereeIncomes, getNumberOfRefereeIncomes)
+
+			Convey(fmt.Sprintf("When get referee income list with %s", v.when), func() {
+				route := "/users/referees/incomes"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.GET(route, handler)
+				req, _ := http.NewRequest("GET", route+v.query, nil)
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
@@ -37,6 +37,12 @@ func mockUpdateUserStatus(err error) dependencyUpdateUserStatus {
 	}
 }
 
+func mockGetReferralCampaignByCode(campaign models.ReferralCampaign, err error) dependencyGetReferralCampaignByCode {
+	return func(string) (models.ReferralCampaign, error) {
+		return campaign, err
+	}
+}
+
 func mockCreateAuthToken(err error) dependencyCreateAuthToken {
 	return func(models.AuthToken) error {
 		return err
@@ -67,8 +73,19 @@ func mockSendEmail(err error) dependencySendEmail {
 	}
 }
 
+func mockGetCurrency() dependencyGetCurrency {
+	// btc is base currency
+	return models.Currencies{{Code: "btc", ExchangeRate: 1}, {Code: "doge", ExchangeRate: 1000, RewardInterval: 60}}.Of
+}
+
+func mockGetUserBalance(balance models.UserBalance, err error) dependencyGetUserBalance {
+	return func(int64, string) (models.UserBalance, error) {
+		return balance, err
+	}
+}
+
 func mockGetLatestTotalReward(r models.TotalReward) dependencyGetLatestTotalReward {
-	return func() models.TotalReward {
+	return func(string) models.TotalReward {
 		return r
 	}
 }
@@ -79,14 +96,50 @@ func mockGetSystemConfig(c models.Config) dependencyGetSystemConfig {
 	}
 }
 
-func mockGetRewardRatesByType(rates []models.RewardRate) dependencyGetRewardRatesByType {
-	return func(string) []models.RewardRate {
+func mockGetRewardRatesByCountry(rates []models.RewardRate) dependencyGetRewardRatesByCountry {
+	return func(string, string, string) []models.RewardRate {
 		return rates
 	}
 }
 
+func mockGetCountryByIP(country string) dependencyGetCountryByIP {
+	return func(string) string {
+		return country
+	}
+}
+
+func mockGetLevels(levels models.Levels) dependencyGetLevels {
+	return func() models.Levels {
+		return levels
+	}
+}
+
+func mockGetStreakBonuses(bonuses models.StreakBonuses) dependencyGetStreakBonuses {
+	return func() models.StreakBonuses {
+		return bonuses
+	}
+}
+
+func mockGetRewardRules(rules models.RewardRules) dependencyGetRewardRules {
+	return func() models.RewardRules {
+		return rules
+	}
+}
+
+func mockGetActiveFairSeed(seed models.FairSeed, err error) dependencyGetActiveFairSeed {
+	return func(int64) (models.FairSeed, error) {
+		return seed, err
+	}
+}
+
+func mockGetFairRoll(roll models.FairRoll, err error) dependencyGetFairRoll {
+	return func(string, int64) (models.FairRoll, error) {
+		return roll, err
+	}
+}
+
 func mockCreateRewardIncome(err error) dependencyCreateRewardIncome {
-	return func(models.Income, time.Time) error {
+	return func(models.Income, time.Time, *models.FairRoll) error {
 		return err
 	}
 }
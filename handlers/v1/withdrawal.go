@@ -1,11 +1,15 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/utils"
 )
 
 // WithdrawalList returns user's withdrawal list as response
@@ -37,18 +41,170 @@ func WithdrawalList(
 		}
 
 		result := make([]struct {
-			UpdatedAt time.Time `json:"updated_at"`
-			Amount    float64   `json:"amount"`
-			TxURL     string    `json:"tx_url"`
-			Status    int64     `json:"status"`
+			UpdatedAt     time.Time  `json:"updated_at"`
+			Currency      string     `json:"currency"`
+			Amount        float64    `json:"amount"`
+			Fee           float64    `json:"fee"`
+			TxURL         string     `json:"tx_url"`
+			Confirmations int64      `json:"confirmations"`
+			BlockTime     *time.Time `json:"block_time"`
+			Stuck         bool       `json:"stuck"`
+			Status        int64      `json:"status"`
 		}, len(withdrawals))
 		for i := range withdrawals {
 			result[i].UpdatedAt = withdrawals[i].UpdatedAt
+			result[i].Currency = withdrawals[i].Currency
 			result[i].Amount = withdrawals[i].Amount
-			result[i].TxURL = constructTxURL(withdrawals[i].TransactionID)
+			result[i].Fee = withdrawals[i].Fee
+			result[i].TxURL = constructTxURL(withdrawals[i].Currency, withdrawals[i].TransactionID)
+			result[i].Confirmations = withdrawals[i].Confirmations
+			result[i].BlockTime = withdrawals[i].BlockTime
+			result[i].Stuck = withdrawals[i].Stuck
 			result[i].Status = withdrawals[i].Status
 		}
 
 		c.JSON(http.StatusOK, paginationResult(result, count))
 	}
 }
+
+type withdrawalPayload struct {
+	Amount   float64 `json:"amount" binding:"required,gt=0"`
+	Currency string  `json:"currency" binding:"-"`
+	TOTPCode string  `json:"totp_code" binding:"-"`
+}
+
+// CreateWithdrawal withdraws amount of balance of currency requested by user to user's default address of the currency,
+// currency is base currency if not specified
+func CreateWithdrawal(
+	getCurrency dependencyGetCurrency,
+	getUserByID dependencyGetUserByID,
+	useTOTPCounter dependencyUseTOTPCounter,
+	getUserBalance dependencyGetUserBalance,
+	getDefaultUserAddress dependencyGetDefaultUserAddress,
+	getSystemConfig dependencyGetSystemConfig,
+	createWithdrawal dependencyCreateWithdrawal,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+		now := time.Now()
+
+		payload := withdrawalPayload{}
+		if err := c.BindJSON(&payload); err != nil {
+			return
+		}
+
+		currency, ok := getCurrency(payload.Currency)
+		if !ok {
+			c.AbortWithError(http.StatusBadRequest, errors.ErrInvalidCurrency)
+			return
+		}
+
+		user, err := getUserByID(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		// only verified users are able to withdraw, same as auto withdrawal
+		if user.Status != models.UserStatusVerified {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		totpCounter, validTOTP := utils.ValidateTOTP(user.TOTPSecret, payload.TOTPCode, now)
+		if user.TOTPEnabled && !validTOTP {
+			c.AbortWithError(http.StatusForbidden, errors.ErrInvalidTOTPCode)
+			return
+		}
+
+		// amounts of system config are in base currency
+		config := getSystemConfig()
+		minAmount, fee := currency.FromBase(config.MinWithdrawalAmount), currency.Floor(currency.FromBase(config.WithdrawalFee))
+		if currency.Floor(payload.Amount) != payload.Amount {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("amount should have at most %v decimals", currency.Decimals()))
+			return
+		}
+
+		if payload.Amount < minAmount {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("amount should be at least %v", minAmount))
+			return
+		}
+
+		if payload.Amount <= fee {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("amount should be more than fee %v", fee))
+			return
+		}
+
+		balance, err := getUserBalance(user.ID, currency.Code)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if payload.Amount > balance.Balance {
+			c.AbortWithError(http.StatusConflict, errors.ErrInsufficientBalance)
+			return
+		}
+
+		// coins are sent to default address of currency
+		address, err := getDefaultUserAddress(user.ID, currency.Code)
+		switch err {
+		case nil:
+		case errors.ErrNotFound:
+			c.AbortWithError(http.StatusConflict, err)
+			return
+		default:
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		withdrawal := models.Withdrawal{
+			UserID:   user.ID,
+			Currency: currency.Code,
+			Amount:   payload.Amount,
+			Fee:      fee,
+			Address:  address.Address,
+			Status:   models.WithdrawalStatusPending,
+		}
+
+		// hold withdrawal for manual review if any review rule matches
+		withdrawal.ReviewReason = config.WithdrawalReviewReason(user, currency.ToBase(withdrawal.Amount), now)
+		if withdrawal.ReviewReason != "" {
+			withdrawal.Status = models.WithdrawalStatusReview
+		}
+
+		// TOTP code is used up right before withdrawal is created
+		if user.TOTPEnabled && !useTOTPCode(c, useTOTPCounter, user.ID, totpCounter) {
+			return
+		}
+
+		// withdrawal rate is limited by the latest withdrawal of user
+		if err := createWithdrawal(withdrawal, time.Second*time.Duration(config.WithdrawalInterval)); err != nil {
+			switch err {
+			case errors.ErrInsufficientBalance:
+				c.AbortWithError(http.StatusConflict, err)
+			case errors.ErrTooManyWithdrawals:
+				c.AbortWithError(statusCodeTooManyRequests, err)
+			default:
+				c.AbortWithError(http.StatusInternalServerError, err)
+			}
+			return
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":    models.EventCreateWithdrawals,
+			"email":    user.Email,
+			"currency": withdrawal.Currency,
+			"address":  withdrawal.Address,
+			"amount":   withdrawal.Amount,
+			"reason":   withdrawal.ReviewReason,
+		}).Info("user requested withdrawal")
+
+		c.JSON(http.StatusCreated, map[string]interface{}{
+			"currency": withdrawal.Currency,
+			"amount":   withdrawal.Amount,
+			"fee":      withdrawal.Fee,
+			"status":   withdrawal.Status,
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package v1
 
 import (
+	"bytes"
+	"encoding/base32"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/utils"
 )
 
 func TestWithdrawalList(t *testing.T) {
@@ -47,7 +52,7 @@ func TestWithdrawalList(t *testing.T) {
 
 	Convey("Given withdrawal list controller with correct dependencies injected", t, func() {
 		getWithdrawals := mockGetWithdrawals([]models.Withdrawal{{}}, nil)
-		handler := WithdrawalList(getWithdrawals, func(int64) (int64, error) { return 0, nil }, func(tx string) string { return tx })
+		handler := WithdrawalList(getWithdrawals, func(int64) (int64, error) { return 0, nil }, func(currency, tx string) string { return tx })
 
 		Convey("When get withdrawal list", func() {
 			route := "/withdrawals"
@@ -65,3 +70,182 @@ func TestWithdrawalList(t *testing.T) {
 		})
 	})
 }
+
+func TestCreateWithdrawal(t *testing.T) {
+	verified := models.User{Status: models.UserStatusVerified}
+	getUserBalance := mockGetUserBalance(models.UserBalance{Balance: 10}, nil)
+	defaultAddress := func(int64, string) (models.UserAddress, error) { return models.UserAddress{Address: "b"}, nil }
+	// secret of RFC 6238 test vectors
+	totpUser := verified
+	totpUser.TOTPEnabled = true
+	totpUser.TOTPSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	totpCode, _ := utils.TOTPCode(totpUser.TOTPSecret, time.Now())
+	config := models.Config{MinWithdrawalAmount: 1, WithdrawalInterval: 3600, WithdrawalFee: 2}
+
+	testdata := []struct {
+		when                  string
+		requestData           string
+		getUserByID           dependencyGetUserByID
+		getDefaultUserAddress dependencyGetDefaultUserAddress
+		createWithdrawal      dependencyCreateWithdrawal
+		code                  int
+	}{
+		{
+			"zero amount",
+			`{"amount":0}`,
+			nil,
+			nil,
+			nil,
+			400,
+		},
+		{
+			"errored getUserByID dependency",
+			`{"amount":5}`,
+			mockGetUserByID(models.User{}, fmt.Errorf("")),
+			nil,
+			nil,
+			500,
+		},
+		{
+			"unverified user",
+			`{"amount":5}`,
+			mockGetUserByID(models.User{Status: models.UserStatusUnverified}, nil),
+			nil,
+			nil,
+			403,
+		},
+		{
+			"invalid totp code",
+			`{"amount":5,"totp_code":"abc123"}`,
+			mockGetUserByID(totpUser, nil),
+			nil,
+			nil,
+			403,
+		},
+		{
+			"replayed totp code",
+			fmt.Sprintf(`{"amount":5,"totp_code":"%s"}`, totpCode),
+			mockGetUserByID(totpUser, nil),
+			defaultAddress,
+			nil,
+			403,
+		},
+		{
+			"amount below minimum",
+			`{"amount":0.5}`,
+			mockGetUserByID(verified, nil),
+			nil,
+			nil,
+			400,
+		},
+		{
+			"amount with more decimals than currency",
+			`{"amount":5.000000001}`,
+			mockGetUserByID(verified, nil),
+			nil,
+			nil,
+			400,
+		},
+		{
+			"amount not more than fee",
+			`{"amount":2}`,
+			mockGetUserByID(verified, nil),
+			nil,
+			nil,
+			400,
+		},
+		{
+			"amount above balance",
+			`{"amount":11}`,
+			mockGetUserByID(verified, nil),
+			nil,
+			nil,
+			409,
+		},
+		{
+			"invalid currency",
+			`{"amount":5,"currency":"xxx"}`,
+			nil,
+			nil,
+			nil,
+			400,
+		},
+		{
+			"amount below minimum exchanged into currency",
+			`{"amount":5,"currency":"doge"}`,
+			mockGetUserByID(verified, nil),
+			nil,
+			nil,
+			400,
+		},
+		{
+			"no default address of currency",
+			`{"amount":5}`,
+			mockGetUserByID(verified, nil),
+			func(int64, string) (models.UserAddress, error) { return models.UserAddress{}, errors.ErrNotFound },
+			nil,
+			409,
+		},
+		{
+			"errored getDefaultUserAddress dependency",
+			`{"amount":5}`,
+			mockGetUserByID(verified, nil),
+			func(int64, string) (models.UserAddress, error) { return models.UserAddress{}, fmt.Errorf("") },
+			nil,
+			500,
+		},
+		{
+			"withdrawal within interval",
+			`{"amount":5}`,
+			mockGetUserByID(verified, nil),
+			defaultAddress,
+			func(models.Withdrawal, time.Duration) error { return errors.ErrTooManyWithdrawals },
+			429,
+		},
+		{
+			"insufficient balance on creation",
+			`{"amount":5}`,
+			mockGetUserByID(verified, nil),
+			defaultAddress,
+			func(models.Withdrawal, time.Duration) error { return errors.ErrInsufficientBalance },
+			409,
+		},
+		{
+			"errored createWithdrawal dependency",
+			`{"amount":5}`,
+			mockGetUserByID(verified, nil),
+			defaultAddress,
+			func(models.Withdrawal, time.Duration) error { return fmt.Errorf("") },
+			500,
+		},
+		{
+			"valid payload",
+			`{"amount":5}`,
+			mockGetUserByID(verified, nil),
+			defaultAddress,
+			func(models.Withdrawal, time.Duration) error { return nil },
+			201,
+		},
+	}
+
+	for _, v := range testdata {
+		Convey("Given create withdrawal controller", t, func() {
+			handler := CreateWithdrawal(mockGetCurrency(), v.getUserByID, mockUseTOTPCounter(errors.ErrInvalidTOTPCode), getUserBalance, v.getDefaultUserAddress, mockGetSystemConfig(config), v.createWithdrawal)
+
+			Convey(fmt.Sprintf("When request with %s", v.when), func() {
+				route := "/withdrawals"
+				_, resp, r := gin.CreateTestContext()
+				r.Use(func(c *gin.Context) {
+					c.Set("auth_token", models.AuthToken{})
+				})
+				r.POST(route, handler)
+				req, _ := http.NewRequest("POST", route, bytes.NewBufferString(v.requestData))
+				r.ServeHTTP(resp, req)
+
+				Convey(fmt.Sprintf("Response code should be equal to %d", v.code), func() {
+					So(resp.Code, ShouldEqual, v.code)
+				})
+			})
+		})
+	}
+}
@@ -1,31 +1,38 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"html/template"
 	"log"
+	"math"
+	"net"
 	"os"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
-	"github.com/btcsuite/btcrpcclient"
 	"github.com/gin-gonic/gin"
 	"github.com/oschwald/geoip2-golang"
 	"github.com/robfig/cron"
 	gt "github.com/solefaucet/geetest"
+	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/handlers/v1"
 	"github.com/solefaucet/sole-server/middlewares"
 	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/services/addressvalidator"
 	"github.com/solefaucet/sole-server/services/cache"
 	"github.com/solefaucet/sole-server/services/cache/memory"
 	"github.com/solefaucet/sole-server/services/hub"
 	"github.com/solefaucet/sole-server/services/hub/list"
 	"github.com/solefaucet/sole-server/services/mail"
 	"github.com/solefaucet/sole-server/services/mail/mandrill"
+	"github.com/solefaucet/sole-server/services/payout"
+	"github.com/solefaucet/sole-server/services/payout/bitcoind"
+	"github.com/solefaucet/sole-server/services/payout/ethereum"
 	"github.com/solefaucet/sole-server/services/storage"
 	"github.com/solefaucet/sole-server/services/storage/mysql"
-	"github.com/solefaucet/sole-server/utils"
 	grayloghook "github.com/yumimobi/logrus-graylog2-hook"
 )
 
@@ -35,7 +42,9 @@ var (
 	store       storage.Storage
 	memoryCache cache.Cache
 	connsHub    hub.Hub
-	coinClient  *btcrpcclient.Client
+	currencies  models.Currencies
+	payouters   = map[string]payout.Payout{}   // by currency, alipay is paid without wallet
+	monitors    = map[string]*payout.Monitor{} // by currency
 	geetest     *gt.Geetest
 	geo         *geoip2.Reader
 )
@@ -76,21 +85,35 @@ func init() {
 	s.SetMaxIdleConns(config.DB.MaxIdleConns)
 	store = s
 
+	// currencies
+	currencies = config.currencies()
+	labelLegacyCurrency(currencies.Base().Code)
+
 	// cache
 	memoryCache = memory.New(config.Cache.NumCachedIncomes)
-	total := must(store.GetLatestTotalReward()).(models.TotalReward)
-	memoryCache.IncrementTotalReward(total.CreatedAt, total.Total)
+	for _, currency := range currencies {
+		total := must(store.GetLatestTotalReward(currency.Code)).(models.TotalReward)
+		memoryCache.IncrementTotalReward(currency.Code, total.CreatedAt, total.Total)
+	}
 	updateCache()
 
-	// coin client
-	initCoinClient(config.Coin.Type)
-
-	// cronjob
-	initCronjob(config.Coin.Type, config.CronjobSpec.CreateWithdrawal, config.CronjobSpec.ProcessWithdrawal)
-
 	// mailer
 	mailer = mandrill.New(config.Mandrill.Key, config.Mandrill.FromEmail, config.Mandrill.FromName)
 
+	// payout
+	initPayout()
+
+	// wallet monitor, depends on payout and mailer
+	initMonitor()
+
+	// payout journal, must be reconciled before cronjob processes withdrawals
+	for code := range payouters {
+		reconcilePayoutBatches(code)
+	}
+
+	// cronjob
+	initCronjob(config.CronjobSpec.CreateWithdrawal, config.CronjobSpec.ProcessWithdrawal)
+
 	// geetest
 	geetest = gt.New(config.Geetest.CaptchaID, config.Geetest.PrivateKey, false, time.Second*10, time.Second*10, 2048)
 
@@ -99,6 +122,14 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	gin.SetMode(config.HTTP.Mode)
 	router := gin.New()
 
@@ -119,10 +150,39 @@ func main() {
 
 	// user endpoints
 	v1UserEndpoints := v1Endpoints.Group("/users")
-	v1UserEndpoints.GET("", authRequired, v1.UserInfo(store.GetUserByID))
-	v1UserEndpoints.POST("", v1.Signup(validateAddressFunc(config.Coin.Type), store.CreateUser, store.GetUserByID))
-	v1UserEndpoints.PUT("/:id/status", v1.VerifyEmail(store.GetSessionByToken, store.GetUserByID, store.UpdateUserStatus))
+	v1UserEndpoints.GET("", authRequired, v1.UserInfo(store.GetUserByID, store.GetUserBalances, memoryCache.GetLevels, memoryCache.GetStreakBonuses))
+	v1UserEndpoints.POST("", v1.Signup(validateAddress, createUser, store.GetUserByID, store.GetReferralCampaignByCode))
+	v1UserEndpoints.PUT("/:id/status", v1.VerifyEmail(store.GetSessionByToken, store.GetUserByID, updateUserStatus))
 	v1UserEndpoints.GET("/referees", authRequired, v1.RefereeList(store.GetReferees, store.GetNumberOfReferees))
+	v1UserEndpoints.GET("/referees/incomes", authRequired, v1.RefereeIncomeList(store.GetRefereeIncomes, store.GetNumberOfRefereeIncomes))
+	v1UserEndpoints.GET("/referral_campaigns", authRequired, v1.ReferralCampaignList(currencies.Of, store.GetReferralCampaigns, store.GetNumberOfReferralCampaigns))
+	v1UserEndpoints.POST("/referral_campaigns", authRequired, v1.CreateReferralCampaign(store.CreateReferralCampaign))
+	v1UserEndpoints.PATCH("/settings", authRequired, v1.UpdateUserSettings(currencies.Of, store.UpdateUserAutoWithdrawal, store.UpdateUserCurrency))
+	v1UserEndpoints.POST("/totp", authRequired, v1.GenerateTOTP(store.GetUserByID, store.UpdateUserTOTP, config.App.Name))
+	v1UserEndpoints.PATCH("/totp", authRequired, v1.EnableTOTP(store.GetUserByID, store.UseTOTPCounter, store.UpdateUserTOTP))
+	v1UserEndpoints.DELETE("/totp", authRequired, v1.DisableTOTP(store.GetUserByID, store.UseTOTPCounter, store.UpdateUserTOTP))
+	v1UserEndpoints.GET("/achievements", authRequired, v1.AchievementList(memoryCache.GetAchievements, store.GetUserAchievements, store.GetAchievementStats))
+
+	// address book endpoints, withdrawals are paid to default address
+	addressVerificationTemplate := template.Must(template.ParseFiles(config.Template.AddressVerificationTemplate))
+	v1UserEndpoints.GET("/addresses", authRequired, v1.UserAddressList(store.GetUserAddresses))
+	v1UserEndpoints.POST("/addresses", authRequired, v1.CreateUserAddress(
+		currencies.Of,
+		validateAddress,
+		store.GetUserByID,
+		store.CreateUserAddress,
+		store.DeleteUserAddress,
+		mailer.SendEmail,
+		addressVerificationTemplate,
+		config.App.Name,
+		config.App.URL,
+	))
+	v1UserEndpoints.POST("/addresses/:id/verification", authRequired, v1.VerifyUserAddress(store.VerifyUserAddress))
+	v1UserEndpoints.POST("/addresses/:id/default", authRequired, v1.SetDefaultUserAddress(setDefaultUserAddress))
+	v1UserEndpoints.DELETE("/addresses/:id", authRequired, v1.DeleteUserAddress(store.DeleteUserAddress))
+
+	// referral campaign link endpoint
+	v1Endpoints.GET("/referral_campaigns/:code", v1.ReferralCampaignRedirect(store.IncrementReferralCampaignClicks, config.App.URL))
 
 	// auth token endpoints
 	v1AuthTokenEndpoints := v1Endpoints.Group("/auth_tokens")
@@ -139,10 +199,17 @@ func main() {
 	// income endpoints
 	v1IncomeEndpoints := v1Endpoints.Group("/incomes", authRequired)
 	v1IncomeEndpoints.POST("/rewards", catpchaValidationRequired,
-		v1.GetReward(store.GetUserByID,
+		v1.GetReward(currencies.Of,
+			store.GetUserByID,
+			store.GetUserBalance,
 			memoryCache.GetLatestTotalReward,
 			memoryCache.GetLatestConfig,
-			memoryCache.GetRewardRatesByType,
+			memoryCache.GetRewardRatesByCountry,
+			getCountryByIP,
+			memoryCache.GetLevels,
+			memoryCache.GetStreakBonuses,
+			memoryCache.GetRewardRules,
+			store.GetActiveFairSeed,
 			createRewardIncome,
 			memoryCache.InsertIncome,
 			connsHub.Broadcast),
@@ -150,8 +217,28 @@ func main() {
 	v1IncomeEndpoints.GET("/rewards", v1.RewardList(store.GetRewardIncomes, store.GetNumberOfRewardIncomes))
 	v1IncomeEndpoints.GET("/offerwalls", v1.OfferwallList(store.GetOfferwallIncomes, store.GetNumberOfOfferwallIncomes))
 
-	// withdrawal endpoint
+	// provably fair seed endpoints
+	v1FairSeedEndpoints := v1Endpoints.Group("/fair_seeds")
+	v1FairSeedEndpoints.GET("", authRequired, v1.FairSeedInfo(store.GetActiveFairSeed))
+	v1FairSeedEndpoints.PUT("", authRequired, v1.RotateFairSeed(store.RotateFairSeed))
+	v1FairSeedEndpoints.GET("/verify", v1.VerifyFairSeed(store.GetFairRoll))
+
+	// promotion endpoint
+	v1Endpoints.GET("/promotions", v1.PromotionList(memoryCache.GetRewardRules))
+
+	// withdrawal endpoints
 	v1Endpoints.GET("/withdrawals", authRequired, v1.WithdrawalList(store.GetWithdrawals, store.GetNumberOfWithdrawals, constructTxURL))
+	v1Endpoints.POST("/withdrawals", authRequired, v1.CreateWithdrawal(
+		currencies.Of,
+		store.GetUserByID,
+		store.UseTOTPCounter,
+		store.GetUserBalance,
+		store.GetDefaultUserAddress,
+		memoryCache.GetLatestConfig,
+		func(withdrawal models.Withdrawal, interval time.Duration) error {
+			return checkAchievementsOnSuccess(withdrawal.UserID, store.CreateWithdrawal(withdrawal, interval))
+		},
+	))
 
 	// captcha endpoint
 	v1Endpoints.GET("/captchas", v1.RegisterCaptcha(geetest.Register, geetest.CaptchaID))
@@ -164,9 +251,12 @@ func main() {
 		v1.SuperrewardsCallback(
 			config.Offerwall.Superrewards.SecretKey,
 			store.GetUserByID,
+			currencies.Of,
 			store.GetNumberOfSuperrewardsOffers,
 			memoryCache.GetLatestConfig,
-			store.CreateSuperrewardsIncome,
+			func(income models.Income, transactionID, offerID string) error {
+				return checkAchievementsOnSuccess(income.UserID, store.CreateSuperrewardsIncome(income, transactionID, offerID))
+			},
 			connsHub.Broadcast,
 		),
 	)
@@ -175,8 +265,11 @@ func main() {
 	v1OfferwallEndpoints.GET("/ptcwall", ptcwallAuthRequired,
 		v1.PtcwallCallback(
 			store.GetUserByID,
+			currencies.Of,
 			memoryCache.GetLatestConfig,
-			store.CreatePtcwallIncome,
+			func(income models.Income) error {
+				return checkAchievementsOnSuccess(income.UserID, store.CreatePtcwallIncome(income))
+			},
 			connsHub.Broadcast,
 		),
 	)
@@ -185,9 +278,12 @@ func main() {
 		v1.ClixwallCallback(
 			config.Offerwall.Clixwall.SecretPassword,
 			store.GetUserByID,
+			currencies.Of,
 			store.GetNumberOfClixwallOffers,
 			memoryCache.GetLatestConfig,
-			store.CreateClixwallIncome,
+			func(income models.Income, offerID string) error {
+				return checkAchievementsOnSuccess(income.UserID, store.CreateClixwallIncome(income, offerID))
+			},
 			connsHub.Broadcast,
 		),
 	)
@@ -196,49 +292,139 @@ func main() {
 	personalyAuthRequired := middlewares.PersonalyAuthRequired(personaly.WhitelistIps, personaly.AppHash, personaly.SecretKey)
 	v1OfferwallEndpoints.GET("/personaly", personalyAuthRequired, v1.PersonalyCallback(
 		store.GetUserByID,
+		currencies.Of,
 		store.GetNumberOfPersonalyOffers,
 		memoryCache.GetLatestConfig,
-		store.CreatePersonalyIncome,
+		func(income models.Income, offerID string) error {
+			return checkAchievementsOnSuccess(income.UserID, store.CreatePersonalyIncome(income, offerID))
+		},
 		connsHub.Broadcast,
 	))
 
 	kiwiwallAuthRequired := middlewares.KiwiwallAuthRequired(config.Offerwall.Kiwiwall.WhitelistIps, config.Offerwall.Kiwiwall.SecretKey)
 	v1OfferwallEndpoints.GET("/kiwiwall", kiwiwallAuthRequired, v1.KiwiwallCallback(
 		store.GetUserByID,
+		currencies.Of,
 		store.GetNumberOfKiwiwallOffers,
 		memoryCache.GetLatestConfig,
-		store.CreateKiwiwallIncome,
+		func(income models.Income, transactionID, offerID string) error {
+			return checkAchievementsOnSuccess(income.UserID, store.CreateKiwiwallIncome(income, transactionID, offerID))
+		},
 		connsHub.Broadcast,
 	))
 
 	adscendMediaAuthRequired := middlewares.AdscendMediaAuthRequired(config.Offerwall.AdscendMedia.WhitelistIps)
 	v1OfferwallEndpoints.GET("/adscend_media", adscendMediaAuthRequired, v1.AdscendMediaCallback(
 		store.GetUserByID,
+		currencies.Of,
 		store.GetAdscendMediaOffer,
 		store.ChargebackIncome,
 		memoryCache.GetLatestConfig,
-		store.CreateAdscendMediaIncome,
+		func(income models.Income, transactionID, offerID string) error {
+			return checkAchievementsOnSuccess(income.UserID, store.CreateAdscendMediaIncome(income, transactionID, offerID))
+		},
 		connsHub.Broadcast,
 	))
 
 	adgateMediaAuthRequired := middlewares.AdgateMediaAuthRequired(config.Offerwall.AdgateMedia.WhitelistIps)
 	v1OfferwallEndpoints.GET("/adgate_media", adgateMediaAuthRequired, v1.AdgateMediaCallback(
 		store.GetUserByID,
+		currencies.Of,
 		store.GetNumberOfAdgateMediaOffers,
 		memoryCache.GetLatestConfig,
-		store.CreateAdgateMediaIncome,
+		func(income models.Income, transactionID, offerID string) error {
+			return checkAchievementsOnSuccess(income.UserID, store.CreateAdgateMediaIncome(income, transactionID, offerID))
+		},
 		connsHub.Broadcast,
 	))
 
 	offertoroAuthRequired := middlewares.OffertoroAuthRequired(config.Offerwall.Offertoro.SecretKey)
 	v1OfferwallEndpoints.GET("/offertoro", offertoroAuthRequired, v1.OffertoroCallback(
 		store.GetUserByID,
+		currencies.Of,
 		store.GetNumberOfOffertoroOffers,
 		memoryCache.GetLatestConfig,
-		store.CreateOffertoroIncome,
+		func(income models.Income, transactionID, offerID string) error {
+			return checkAchievementsOnSuccess(income.UserID, store.CreateOffertoroIncome(income, transactionID, offerID))
+		},
 		connsHub.Broadcast,
 	))
 
+	// admin endpoints, every request is audited
+	refreshCache := safeFuncWrapper(updateCache)
+	permissionRequired := func(permission string) gin.HandlerFunc {
+		return middlewares.PermissionRequired(store.GetUserByID, memoryCache.GetRolePermissions, permission)
+	}
+	configsRead := permissionRequired(models.PermissionConfigsRead)
+	configsWrite := permissionRequired(models.PermissionConfigsWrite)
+	usersRead := permissionRequired(models.PermissionUsersRead)
+	usersWrite := permissionRequired(models.PermissionUsersWrite)
+	balancesWrite := permissionRequired(models.PermissionBalancesWrite)
+	rolesWrite := permissionRequired(models.PermissionRolesWrite)
+	withdrawalsRead := permissionRequired(models.PermissionWithdrawalsRead)
+	withdrawalsReview := permissionRequired(models.PermissionWithdrawalsReview)
+	statsRead := permissionRequired(models.PermissionStatsRead)
+	auditLogsRead := permissionRequired(models.PermissionAuditLogsRead)
+
+	v1AdminEndpoints := v1Endpoints.Group("/admin", authRequired, middlewares.AdminAuditLog(store.CreateAdminAuditLog))
+	v1AdminEndpoints.GET("/configs", configsRead, v1.AdminConfigList(store.GetConfigs, store.GetNumberOfConfigs))
+	v1AdminEndpoints.POST("/configs", configsWrite, v1.AdminCreateConfig(store.CreateConfig, refreshCache))
+	v1AdminEndpoints.GET("/reward_rates", configsRead, v1.AdminRewardRateList(store.GetAllRewardRates))
+	v1AdminEndpoints.POST("/reward_rates", configsWrite, v1.AdminCreateRewardRate(currencies.Of, store.CreateRewardRate, refreshCache))
+	v1AdminEndpoints.PUT("/reward_rates/:id", configsWrite, v1.AdminUpdateRewardRate(currencies.Of, store.UpdateRewardRate, refreshCache))
+	v1AdminEndpoints.DELETE("/reward_rates/:id", configsWrite, v1.AdminDeleteRewardRate(store.DeleteRewardRate, refreshCache))
+	v1AdminEndpoints.GET("/users", usersRead, v1.AdminUserList(store.GetUsers, store.GetNumberOfUsers))
+	v1AdminEndpoints.GET("/users/:id", usersRead, v1.AdminUserDetail(store.GetUserByID, store.GetUserStatusLogs, store.GetAdminAdjustments))
+	v1AdminEndpoints.GET("/users/:id/incomes", usersRead, v1.AdminUserIncomeList(store.GetIncomes, store.GetNumberOfIncomes))
+	v1AdminEndpoints.GET("/users/:id/withdrawals", usersRead, v1.AdminUserWithdrawalList(store.GetWithdrawals, store.GetNumberOfWithdrawals))
+	v1AdminEndpoints.POST("/users/:id/ban", usersWrite, v1.AdminBanUser(store.BanUser))
+	v1AdminEndpoints.POST("/users/:id/unban", usersWrite, v1.AdminUnbanUser(store.UnbanUser))
+	v1AdminEndpoints.POST("/users/:id/adjustments", balancesWrite, v1.AdminCreateAdjustment(currencies.Of, store.CreateAdminAdjustment))
+	v1AdminEndpoints.PUT("/users/:id/role", rolesWrite, v1.AdminUpdateUserRole(store.UpdateUserRole))
+	v1AdminEndpoints.GET("/roles", rolesWrite, v1.AdminRoleList(store.GetRoles, memoryCache.GetRolePermissions))
+	v1AdminEndpoints.GET("/audit_logs", auditLogsRead, v1.AdminAuditLogList(store.GetAdminAuditLogs, store.GetNumberOfAdminAuditLogs))
+	v1AdminEndpoints.GET("/stats", statsRead, v1.AdminStats(
+		currencies.Of,
+		memoryCache.GetLatestTotalReward,
+		store.GetUserStats,
+		store.GetWithdrawalStats,
+		store.GetLiabilities,
+		getWalletBalance,
+		connsHub.Len,
+	))
+	v1AdminEndpoints.GET("/stats/wallet", statsRead, v1.AdminWalletRunway(currencies.Of, getWalletRunway))
+	v1AdminEndpoints.GET("/stats/rewards", statsRead, v1.AdminRewardStats(currencies.Of, store.GetTotalRewards))
+	v1AdminEndpoints.GET("/stats/offerwalls", statsRead, v1.AdminOfferwallStats(store.GetOfferwallRevenues))
+	withdrawalRejectionTemplate := template.Must(template.ParseFiles(config.Template.WithdrawalRejectionTemplate))
+	v1AdminEndpoints.GET("/withdrawals", withdrawalsRead, v1.AdminWithdrawalList(store.GetWithdrawalsByStatus, store.GetNumberOfWithdrawalsByStatus))
+	v1AdminEndpoints.POST("/withdrawals/:id/approve", withdrawalsReview, v1.AdminApproveWithdrawal(store.ApproveWithdrawal))
+	v1AdminEndpoints.POST("/withdrawals/:id/reject", withdrawalsReview, v1.AdminRejectWithdrawal(
+		store.RejectWithdrawal,
+		store.GetUserByID,
+		mailer.SendEmail,
+		withdrawalRejectionTemplate,
+		config.App.Name,
+	))
+
+	// payout batches in review may have paid some of their withdrawals, which are resolved after checked in wallet
+	v1AdminEndpoints.GET("/payout_batches", withdrawalsRead, v1.AdminPayoutBatchList(store.GetPayoutBatchesByStatus, store.GetNumberOfPayoutBatchesByStatus))
+	v1AdminEndpoints.GET("/payout_batches/:batch_id/withdrawals", withdrawalsRead, v1.AdminPayoutBatchWithdrawalList(store.GetProcessingWithdrawalsByPayoutBatch))
+	v1AdminEndpoints.POST("/payout_batches/:batch_id/withdrawals/:id/resolve", withdrawalsReview, v1.AdminResolvePayoutBatchWithdrawal(store.ResolvePayoutBatchWithdrawal))
+
+	// alipay withdrawals are paid out by operators with exported file
+	if _, ok := currencies.Of(models.CoinTypeAlipay); ok {
+		v1AdminEndpoints.POST("/alipay/exports", withdrawalsReview, v1.AdminExportAlipayWithdrawals(
+			func() ([]models.Withdrawal, error) {
+				return store.UpdatePendingWithdrawalStatusToProcessing(models.CoinTypeAlipay)
+			},
+			config.App.Name,
+		))
+		v1AdminEndpoints.POST("/alipay/results", withdrawalsReview, v1.AdminImportAlipayResults(
+			store.UpdateWithdrawalStatusToProcessed,
+			store.FailWithdrawal,
+		))
+	}
+
 	// websocket endpoint
 	v1Endpoints.GET("/websocket",
 		v1.Websocket(
@@ -256,8 +442,15 @@ func main() {
 	}
 }
 
-func createRewardIncome(income models.Income, now time.Time) error {
-	if err := store.CreateRewardIncome(income, now); err != nil {
+// fair roll, if any, is recorded in the same transaction as the income
+func createRewardIncome(income models.Income, now time.Time, roll *models.FairRoll) error {
+	var err error
+	if roll != nil {
+		err = store.CreateFairRewardIncome(income, now, *roll)
+	} else {
+		err = store.CreateRewardIncome(income, now)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -265,11 +458,109 @@ func createRewardIncome(income models.Income, now time.Time) error {
 	if income.RefererID > 0 {
 		totalReward += income.RefererIncome
 	}
-	memoryCache.IncrementTotalReward(now, totalReward)
+	memoryCache.IncrementTotalReward(income.Currency, now, totalReward)
+
+	return checkAchievementsOnSuccess(income.UserID, nil)
+}
+
+// signup address becomes default address of base currency
+func createUser(u models.User) error {
+	return store.CreateUser(u, currencies.Base().Code)
+}
+
+// signup address, i.e. default address of base currency, is kept in sync with users table
+func setDefaultUserAddress(userID, id int64) error {
+	return store.SetDefaultUserAddress(userID, id, currencies.Base().Code)
+}
+
+func updateUserStatus(id int64, status string) error {
+	if err := store.UpdateUserStatus(id, status); err != nil {
+		return err
+	}
+
+	// referer may unlock achievements when referee gets verified
+	if status == models.UserStatusVerified {
+		go safeFuncWrapper(func() {
+			if user, err := store.GetUserByID(id); err == nil && user.RefererID > 0 {
+				checkAchievements(user.RefererID)
+			}
+		})()
+	}
 
 	return nil
 }
 
+// check achievements asynchronously if err is nil, err is returned as is
+func checkAchievementsOnSuccess(userID int64, err error) error {
+	if err == nil {
+		go safeFuncWrapper(func() { checkAchievements(userID) })()
+	}
+	return err
+}
+
+// unlock achievements that user reaches and announce them to all clients
+func checkAchievements(userID int64) {
+	unlocked, err := store.GetUserAchievements(userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":   models.EventAchievementUnlocked,
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Error("failed to get user achievements")
+		return
+	}
+
+	stats, err := store.GetAchievementStats(userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":   models.EventAchievementUnlocked,
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Error("failed to get achievement stats")
+		return
+	}
+
+	for _, achievement := range memoryCache.GetAchievements().Unlockable(stats, unlocked) {
+		ok, err := store.UnlockAchievement(userID, achievement, currencies.Base().Code)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"event":       models.EventAchievementUnlocked,
+				"user_id":     userID,
+				"achievement": achievement.Name,
+				"error":       err.Error(),
+			}).Error("failed to unlock achievement")
+			continue
+		}
+
+		// unlocked concurrently by another check
+		if !ok {
+			continue
+		}
+
+		user, err := store.GetUserByID(userID)
+		if err != nil {
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"event":        models.EventAchievementUnlocked,
+			"user_email":   user.Email,
+			"user_address": user.Address,
+			"achievement":  achievement.Name,
+			"bonus":        achievement.Bonus,
+		}).Info("user unlocked achievement")
+
+		// broadcast achievement to all clients
+		msg, _ := json.Marshal(models.WebsocketMessage{Achievement: struct {
+			Address string  `json:"address"`
+			Name    string  `json:"name"`
+			Title   string  `json:"title"`
+			Bonus   float64 `json:"bonus"`
+		}{user.Address, achievement.Name, achievement.Title, achievement.Bonus}})
+		connsHub.Broadcast(msg)
+	}
+}
+
 func updateCache() {
 	memoryCache.SetLatestConfig(must(store.GetLatestConfig()).(models.Config))
 
@@ -278,230 +569,327 @@ func updateCache() {
 
 	moreRates := must(store.GetRewardRatesByType(models.RewardRateTypeMore)).([]models.RewardRate)
 	memoryCache.SetRewardRates(models.RewardRateTypeMore, moreRates)
+
+	memoryCache.SetScopedRewardRates(must(store.GetScopedRewardRates()).([]models.RewardRate))
+	memoryCache.SetCountryGroups(must(store.GetCountryGroups()).([]models.CountryGroup))
+
+	memoryCache.SetLevels(must(store.GetLevels()).(models.Levels))
+	memoryCache.SetStreakBonuses(must(store.GetStreakBonuses()).(models.StreakBonuses))
+	memoryCache.SetAchievements(must(store.GetAchievements()).(models.Achievements))
+	memoryCache.SetRewardRules(must(store.GetRewardRules()).(models.RewardRules))
+	memoryCache.SetRolePermissions(must(store.GetRolePermissions()).(models.RolePermissions))
 }
 
-func initCronjob(coinType, createWithdrawalCronjobSpec, processWithdrawalCronjobSpec string) {
+func initCronjob(createWithdrawalCronjobSpec, processWithdrawalCronjobSpec string) {
 	c := cron.New()
 
-	switch coinType {
-	case models.CoinTypeEthereum, models.CoinTypeAlipay:
-	default:
-		must(nil, c.AddFunc(processWithdrawalCronjobSpec, safeFuncWrapper(processWithdrawals))) // default: process withdraw request every half hour
-		must(nil, c.AddFunc("@every 6h", safeFuncWrapper(logBalanceAndAddress)))                // log balance and address every 6 hours
+	// every wallet is processed on its own, alipay has no wallet
+	for code := range payouters {
+		code := code
+		must(nil, c.AddFunc(processWithdrawalCronjobSpec, safeFuncWrapper(func() { processWithdrawals(code) }))) // default: process withdraw request every half hour
+		must(nil, c.AddFunc("@every 6h", safeFuncWrapper(func() { logBalanceAndAddress(code) })))                // log balance and address every 6 hours
+		must(nil, c.AddFunc("@every 10m", safeFuncWrapper(func() { trackWithdrawals(code) })))                   // track confirmations every 10 minutes
+		must(nil, c.AddFunc("@every 1h", safeFuncWrapper(monitors[code].Check)))                                 // check wallet runway every hour
 	}
 
-	must(nil, c.AddFunc(createWithdrawalCronjobSpec, safeFuncWrapper(createWithdrawal))) // default: create withdrawal every day
-	must(nil, c.AddFunc("@every 1m", safeFuncWrapper(updateCache)))                      // update cache every 1 minute
+	must(nil, c.AddFunc(createWithdrawalCronjobSpec, safeFuncWrapper(createWithdrawals))) // default: create withdrawal every day
+	must(nil, c.AddFunc("@every 1m", safeFuncWrapper(updateCache)))                       // update cache every 1 minute
 	c.Start()
 }
 
-// automatically create withdrawal
-func createWithdrawal() {
-	users, err := store.GetWithdrawableUsers(memoryCache.GetLatestConfig().MinWithdrawalAmount)
+// automatically create withdrawal of every currency
+func createWithdrawals() {
+	for _, currency := range currencies {
+		createWithdrawal(currency)
+	}
+}
+
+// automatically create withdrawal of currency to default address of currency
+func createWithdrawal(currency models.Currency) {
+	// balance must cover fee so that something is left to send, amounts of config are in base currency
+	config := memoryCache.GetLatestConfig()
+	// amounts are floored to decimals of currency, the rest is left in balance
+	minAmount, fee := currency.FromBase(config.MinWithdrawalAmount), currency.Floor(currency.FromBase(config.WithdrawalFee))
+	balances, err := store.GetWithdrawableBalances(currency.Code, math.Max(minAmount, fee))
 	if err != nil {
-		logger.Printf("get withdrawable users error: %v\n", err)
+		logger.Printf("get withdrawable balances of %v error: %v\n", currency.Code, err)
 		logrus.WithFields(logrus.Fields{
-			"event": models.EventCreateWithdrawals,
-			"error": err,
-		}).Error("failed to get withdrawable users")
+			"event":    models.EventCreateWithdrawals,
+			"currency": currency.Code,
+			"error":    err,
+		}).Error("failed to get withdrawable balances")
 		return
 	}
 
-	f := func(users []models.User, handler func(err error, u models.User)) {
-		for i := range users {
-			handler(store.CreateWithdrawal(models.Withdrawal{
-				UserID:  users[i].ID,
-				Amount:  users[i].Balance,
-				Address: users[i].Address,
-			}), users[i])
+	f := func(balances []models.UserBalance, handler func(err error, b models.UserBalance)) {
+		now := time.Now()
+		for i := range balances {
+			user, err := store.GetUserByID(balances[i].UserID)
+			if err != nil {
+				handler(err, balances[i])
+				continue
+			}
+
+			// balance is kept until user adds address of currency
+			address, err := store.GetDefaultUserAddress(user.ID, currency.Code)
+			if err == errors.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				handler(err, balances[i])
+				continue
+			}
+
+			withdrawal := models.Withdrawal{
+				UserID:   user.ID,
+				Currency: currency.Code,
+				Amount:   currency.Floor(balances[i].Balance),
+				Fee:      fee,
+				Address:  address.Address,
+			}
+
+			// hold withdrawal for manual review if any review rule matches
+			withdrawal.ReviewReason = config.WithdrawalReviewReason(user, currency.ToBase(withdrawal.Amount), now)
+			if withdrawal.ReviewReason != "" {
+				withdrawal.Status = models.WithdrawalStatusReview
+				logrus.WithFields(logrus.Fields{
+					"event":    models.EventCreateWithdrawals,
+					"email":    user.Email,
+					"currency": withdrawal.Currency,
+					"address":  withdrawal.Address,
+					"amount":   withdrawal.Amount,
+					"reason":   withdrawal.ReviewReason,
+				}).Info("withdrawal held for review")
+			}
+
+			// auto withdrawal is not limited by withdrawal interval
+			handler(store.CreateWithdrawal(withdrawal, 0), balances[i])
 		}
 	}
 
 	// create withdrawal, move errored ones into retry queue
-	retryUsers := []models.User{}
-	f(users, func(err error, u models.User) {
+	retryBalances := []models.UserBalance{}
+	f(balances, func(err error, b models.UserBalance) {
 		if err != nil {
-			retryUsers = append(retryUsers, u)
+			retryBalances = append(retryBalances, b)
+			return
 		}
+		checkAchievements(b.UserID)
 	})
 
 	// retry with error output
-	f(retryUsers, func(err error, u models.User) {
+	f(retryBalances, func(err error, b models.UserBalance) {
 		if err != nil {
-			logger.Printf("create withdrawal for user %v error: %v\n", u, err)
+			logger.Printf("create withdrawal for balance %v error: %v\n", b, err)
 			logrus.WithFields(logrus.Fields{
-				"event":   models.EventCreateWithdrawals,
-				"email":   u.Email,
-				"address": u.Address,
-				"balance": u.Balance,
-				"status":  u.Status,
-				"error":   err,
+				"event":    models.EventCreateWithdrawals,
+				"user_id":  b.UserID,
+				"currency": b.Currency,
+				"balance":  b.Balance,
+				"error":    err,
 			}).Error("failed to create withdrawal")
+			return
 		}
+		checkAchievements(b.UserID)
 	})
 }
 
-func initCoinClient(coinType string) {
-	switch coinType {
-	case models.CoinTypeEthereum, models.CoinTypeAlipay:
-		return
-	default:
-		config := &btcrpcclient.ConnConfig{
-			Host:         "localhost:8332",
-			User:         "rpcuser",
-			Pass:         "rpcpass",
-			HTTPPostMode: true, // Bitcoin core only supports HTTP POST mode
-			DisableTLS:   true, // Bitcoin core does not provide TLS by default
+// rows created before currencies were introduced carry empty currency, they belong to base currency,
+// servers of single currency deployment must be stopped before migration so that no income is left in users
+func labelLegacyCurrency(currency string) {
+	labeled := must(store.LabelLegacyCurrency(currency)).(map[string]int64)
+	for table, n := range labeled {
+		if n > 0 {
+			logrus.WithFields(logrus.Fields{
+				"table":    table,
+				"rows":     n,
+				"currency": currency,
+			}).Info("labeled rows of single currency deployment")
 		}
-		coinClient = must(btcrpcclient.New(config, nil)).(*btcrpcclient.Client)
 	}
 }
 
-func validateAddressFunc(coinType string) func(string) (bool, error) {
-	var validateAddress func(string) (bool, error)
-	switch coinType {
-	case models.CoinTypeEthereum, models.CoinTypeAlipay:
-		validateAddress = func(string) (bool, error) {
-			return true, nil
-		}
-	default:
-		validateAddress = func(address string) (bool, error) {
-			result, err := coinClient.ValidateAddress(address)
-			if err != nil {
-				logrus.WithFields(logrus.Fields{
-					"address": address,
-					"error":   err.Error(),
-				}).Debug("failed to validate address")
-				return false, err
-			}
-
-			return result.IsValid, nil
+func initPayout() {
+	for _, c := range config.Currencies {
+		switch c.Code {
+		case models.CoinTypeAlipay:
+			continue
+		case models.CoinTypeEthereum:
+			eth := c.Ethereum
+			payouters[c.Code] = must(ethereum.New(eth.RPCURL, eth.Address, eth.GasPrice)).(*ethereum.Payout)
+		default:
+			payouters[c.Code] = must(bitcoind.New(c.RPCHost, c.RPCUser, c.RPCPass, c.FeeConfTarget)).(bitcoind.Payout)
 		}
 	}
-	return validateAddress
 }
 
-func logBalanceAndAddress() {
-	logrus.WithFields(logrus.Fields{
-		"event":                   models.EventLogBalanceAndAddress,
-		"address_to_receive_coin": must(coinClient.GetAccountAddress("")).(string),
-		"balance":                 must(getBalance()).(float64),
-	}).Info("current balance and address")
+func initMonitor() {
+	recipients := []string{}
+	for _, v := range strings.Split(config.Payout.AlertEmails, ",") {
+		if email := strings.TrimSpace(v); email != "" {
+			recipients = append(recipients, email)
+		}
+	}
+	tmpl := template.Must(template.ParseFiles(config.Template.WalletAlertTemplate))
+
+	for code, payouter := range payouters {
+		code := code
+		monitors[code] = payout.NewMonitor(
+			payouter,
+			config.Payout.MinRunwayDays,
+			config.Payout.AlertInterval,
+			recipients,
+			tmpl,
+			config.App.Name,
+			code,
+			func() (float64, error) { return store.GetLiabilities(code) },
+			func() ([]models.WithdrawalStats, error) { return store.GetWithdrawalStats(code) },
+			func(since time.Time) (float64, error) { return store.GetPaidOutAmount(code, since) },
+			mailer.SendEmail,
+		)
+	}
 }
 
-func getBalance() (float64, error) {
-	balance, err := coinClient.GetBalance("")
+// get ISO country code of ip, empty if it can not be resolved
+func getCountryByIP(ip string) string {
+	record, err := geo.Country(net.ParseIP(ip))
 	if err != nil {
-		logger.Printf("get coin balance error: %v\n", err)
 		logrus.WithFields(logrus.Fields{
+			"event": models.EventGetGeoFromIP,
+			"ip":    ip,
 			"error": err.Error(),
-		}).Error("failed to get balance")
-		return 0, err
+		}).Debug("fail to get country information")
+		return ""
 	}
 
-	return balance.ToBTC(), nil
+	return record.Country.IsoCode
 }
 
-func processWithdrawals() {
-	start := time.Now()
-
-	withdrawals, err := store.GetPendingWithdrawals()
-	if err != nil {
-		logger.Printf("get pending withdrawals error: %v\n", err)
-		logrus.WithFields(logrus.Fields{
-			"event": models.EventProcessWithdrawals,
-			"error": err.Error(),
-		}).Error("failed to get pending withdrawals")
-		return
+// validate address of currency, empty currency means base currency
+func validateAddress(currency, address string) (bool, error) {
+	c, ok := currencies.Of(currency)
+	if !ok {
+		return false, nil
 	}
 
-	// do nothing if there is nothing to withdraw
-	if len(withdrawals) == 0 {
-		return
+	if c.Code == models.CoinTypeAlipay {
+		return true, nil
 	}
 
-	// parse data from withdrawals
-	balance := must(getBalance()).(float64)
-	total, totalWithdrawal := 0.0, 0.0
-	amounts := map[string]float64{}
-	withdrawalIDs := []int64{}
-	for _, v := range withdrawals {
-		total += v.Amount * 1.1 // NOTE: assume tx_fee = amount * 0.1
-
-		// process as much as it can when balance > 0.1
-		if balance > 0.1 && balance > total {
-			totalWithdrawal += v.Amount * 1.1
-			address := strings.TrimSpace(v.Address)
-			amounts[address] = utils.ToFixed(amounts[address]+v.Amount, 8)
-			withdrawalIDs = append(withdrawalIDs, v.ID)
-		}
+	cc, _ := config.currency(c.Code)
+	if !addressvalidator.Validate(c.Code, address, cc.Testnet) {
+		return false, nil
 	}
 
-	// nothing to withdraw
-	if len(withdrawalIDs) <= 0 {
-		logrus.WithFields(logrus.Fields{
-			"event":                   models.EventProcessWithdrawals,
-			"address_to_receive_coin": must(coinClient.GetAccountAddress("")).(string),
-			"total":                   total,
-			"current_balance":         balance,
-			"amount_of_coins_needed":  total - balance,
-			"number_of_address":       len(amounts),
-		}).Warn("need more coins to process withdrawal request")
-		return
+	payouter, ok := payouters[c.Code]
+	if !cc.ValidateAddressWithNode || !ok {
+		return true, nil
 	}
 
-	// update withdrawal status to processing
-	if err = store.UpdateWithdrawalStatusToProcessing(withdrawalIDs); err != nil {
-		logger.Printf("update withdrawal status to processing error: %v\n", err)
+	valid, err := payouter.ValidateAddress(address)
+	if err != nil {
 		logrus.WithFields(logrus.Fields{
-			"event":          models.EventProcessWithdrawals,
-			"error":          err.Error(),
-			"withdrawal_ids": withdrawalIDs,
-		}).Error("fail to update withdrawal status to processing")
-		return
+			"currency": c.Code,
+			"address":  address,
+			"error":    err.Error(),
+		}).Debug("failed to validate address")
+		return false, err
 	}
 
-	// send coins
-	hash, err := coinClient.SendManyComment("", amounts, 1, "Payment from solefaucet, visit us at "+config.App.URL)
+	return valid, nil
+}
+
+func logBalanceAndAddress(currency string) {
+	logrus.WithFields(logrus.Fields{
+		"event":                   models.EventLogBalanceAndAddress,
+		"currency":                currency,
+		"address_to_receive_coin": must(payouters[currency].GetReceiveAddress()).(string),
+		"balance":                 must(getBalance(currency)).(float64),
+	}).Info("current balance and address")
+}
+
+func getBalance(currency string) (float64, error) {
+	balance, err := payouters[currency].GetBalance()
 	if err != nil {
-		logger.Printf("sendmany error: %v\n", err)
+		logger.Printf("get %v balance error: %v\n", currency, err)
 		logrus.WithFields(logrus.Fields{
-			"event":   models.EventProcessWithdrawals,
-			"amounts": amounts,
-			"balance": balance,
-			"total":   totalWithdrawal,
-			"error":   err.Error(),
-		}).Error("fail to send coin")
-		return
+			"currency": currency,
+			"error":    err.Error(),
+		}).Error("failed to get balance")
+		return 0, err
 	}
 
-	// update withdrawal status to processed in db
-	if err := store.UpdateWithdrawalStatusToProcessed(withdrawalIDs, hash.String()); err != nil {
-		logger.Printf("update withdrawal status to processed and transaction id to %v error: %v\n", hash.String(), err)
-		logrus.WithFields(logrus.Fields{
-			"event":          models.EventProcessWithdrawals,
-			"id":             withdrawalIDs,
-			"transaction_id": hash.String(),
-			"error":          err.Error(),
-		}).Panic("failed to update withdrawal status to processed")
-		return
+	return balance, nil
+}
+
+// hot wallet balance, withdrawals of alipay are paid without wallet
+func getWalletBalance(currency string) (float64, error) {
+	if _, ok := payouters[currency]; !ok {
+		return 0, fmt.Errorf("no wallet for currency %v", currency)
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"event":                   models.EventProcessWithdrawals,
-		"duration":                float64(time.Since(start).Nanoseconds()) / 1e6,
-		"total":                   totalWithdrawal,
-		"remaining_balance":       must(getBalance()).(float64),
-		"address_to_receive_coin": must(coinClient.GetAccountAddress("")).(string),
-		"number_of_withdrawals":   len(amounts),
-	}).Info("succeed to process withdraw requests")
+	return getBalance(currency)
+}
+
+func getWalletRunway(currency string) (models.WalletRunway, error) {
+	monitor, ok := monitors[currency]
+	if !ok {
+		return models.WalletRunway{}, fmt.Errorf("no wallet for currency %v", currency)
+	}
+
+	return monitor.Runway()
+}
+
+// batches left unfinished by last run are reconciled first, processor sends nothing while any is left
+func processWithdrawals(currency string) {
+	reconcilePayoutBatches(currency)
+
+	c, _ := config.currency(currency)
+	getUnfinishedPayoutBatches := func() ([]models.PayoutBatch, error) { return store.GetUnfinishedPayoutBatches(currency) }
+	payout.NewProcessor(
+		payouters[currency],
+		"Payment from solefaucet, visit us at "+config.App.URL,
+		c.MaxFeePerWithdrawal,
+		config.Payout.MaxOutputs,
+		getUnfinishedPayoutBatches,
+		func() ([]models.Withdrawal, error) { return store.GetPendingWithdrawals(currency) },
+		store.CreatePayoutBatch,
+		store.UpdatePayoutBatchStatus,
+		store.UpdateWithdrawalStatusToProcessed,
+		store.UpdateWithdrawalStatusToPending,
+		store.QuarantineWithdrawals,
+	).Process()
+}
+
+// find outcome of payout batches left unfinished by last run before processing withdrawals again
+func reconcilePayoutBatches(currency string) {
+	payout.NewReconciler(
+		payouters[currency],
+		func() ([]models.PayoutBatch, error) { return store.GetUnfinishedPayoutBatches(currency) },
+		store.GetProcessingWithdrawalsByPayoutBatch,
+		store.UpdateWithdrawalStatusToProcessed,
+		store.UpdatePayoutBatchStatus,
+	).Reconcile()
+}
+
+func trackWithdrawals(currency string) {
+	payout.NewTracker(
+		payouters[currency],
+		config.Payout.Confirmations,
+		config.Payout.StuckAfter,
+		func(confirmations int64) ([]models.Withdrawal, error) {
+			return store.GetUnconfirmedWithdrawals(currency, confirmations)
+		},
+		store.UpdateWithdrawalConfirmations,
+	).Track()
 }
 
-func constructTxURL(tx string) string {
-	if tx == "" {
+func constructTxURL(currency, tx string) string {
+	c, ok := config.currency(currency)
+	if tx == "" || !ok {
 		return ""
 	}
-	return config.Coin.TxExplorer + tx
+	return c.TxExplorer + tx
 }
 
 // fail fast on initialization
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// lengths of columns of admin_audit_logs, counted in characters
+const (
+	maxAuditLogTargetLength = 255
+	maxAuditLogBodyLength   = 1024
+)
+
+type adminAuditLogDependencyCreateAdminAuditLog func(models.AdminAuditLog) error
+
+// AdminAuditLog records every admin request with actor, action and target after it is handled,
+// target is route params of request, e.g. id of user banned, along with body, e.g. amount adjusted,
+// it should be used after AuthRequired
+func AdminAuditLog(createAdminAuditLog adminAuditLogDependencyCreateAdminAuditLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// body is read ahead and put back for handler
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = ioutil.ReadAll(c.Request.Body)
+			c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		c.Next()
+
+		authToken, ok := c.Get("auth_token")
+		if !ok {
+			return
+		}
+
+		log := models.AdminAuditLog{
+			UserID:     authToken.(models.AuthToken).UserID,
+			Action:     c.Request.Method + " " + routeOf(c),
+			Target:     truncate(paramsOf(c), maxAuditLogTargetLength),
+			Body:       truncate(string(body), maxAuditLogBodyLength),
+			StatusCode: c.Writer.Status(),
+			IP:         c.ClientIP(),
+		}
+		if err := createAdminAuditLog(log); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"event":     models.EventAdminChange,
+				"audit_log": log,
+				"error":     err.Error(),
+			}).Error("failed to create admin audit log")
+		}
+	}
+}
+
+// route of request with params restored, e.g. /v1/admin/users/:id/ban
+func routeOf(c *gin.Context) string {
+	segments := strings.Split(c.Request.URL.Path, "/")
+	for i := range segments {
+		for _, p := range c.Params {
+			if p.Value != "" && segments[i] == p.Value {
+				segments[i] = ":" + p.Key
+				break
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// route params of request, e.g. id=1
+func paramsOf(c *gin.Context) string {
+	params := make([]string, len(c.Params))
+	for i, p := range c.Params {
+		params[i] = p.Key + "=" + p.Value
+	}
+	return strings.Join(params, "&")
+}
+
+// truncate s to at most n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/solefaucet/sole-server/models"
+)
+
+type (
+	permissionRequiredDependencyGetUserByID        func(int64) (models.User, error)
+	permissionRequiredDependencyGetRolePermissions func() models.RolePermissions
+)
+
+// PermissionRequired checks if role of authorized user is granted permission,
+// it should be used after AuthRequired
+func PermissionRequired(
+	getUserByID permissionRequiredDependencyGetUserByID,
+	getRolePermissions permissionRequiredDependencyGetRolePermissions,
+	permission string,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authToken := c.MustGet("auth_token").(models.AuthToken)
+
+		user, err := getUserByID(authToken.UserID)
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if user.Status == models.UserStatusBanned || !getRolePermissions().Has(user.Role, permission) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
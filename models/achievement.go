@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Achievement metrics
+const (
+	AchievementMetricRewards          = "rewards"
+	AchievementMetricOfferwalls       = "offerwalls"
+	AchievementMetricVerifiedReferees = "verified_referees"
+	AchievementMetricWithdrawals      = "withdrawals"
+)
+
+// Achievement model
+type Achievement struct {
+	ID        int64     `db:"id" json:"-"`
+	Name      string    `db:"name" json:"name"`
+	Title     string    `db:"title" json:"title"`
+	Metric    string    `db:"metric" json:"metric"`
+	Threshold int64     `db:"threshold" json:"threshold"`
+	Bonus     float64   `db:"bonus" json:"bonus"`
+	UpdatedAt time.Time `db:"updated_at" json:"-"`
+	CreatedAt time.Time `db:"created_at" json:"-"`
+}
+
+// Achievements defined in db
+type Achievements []Achievement
+
+// AchievementStats maps achievement metric to user's current value
+type AchievementStats map[string]int64
+
+// UserAchievement model
+type UserAchievement struct {
+	ID            int64     `db:"id"`
+	UserID        int64     `db:"user_id"`
+	AchievementID int64     `db:"achievement_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// Unlockable returns achievements reached by stats but not unlocked yet
+func (as Achievements) Unlockable(stats AchievementStats, unlocked []UserAchievement) Achievements {
+	ids := map[int64]bool{}
+	for _, v := range unlocked {
+		ids[v.AchievementID] = true
+	}
+
+	result := Achievements{}
+	for _, a := range as {
+		if !ids[a.ID] && stats[a.Metric] >= a.Threshold {
+			result = append(result, a)
+		}
+	}
+	return result
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AdminAdjustment model, a manual balance change made by admin,
+// credited or deducted as admin type income
+type AdminAdjustment struct {
+	ID        int64     `db:"id" json:"id"`
+	UserID    int64     `db:"user_id" json:"user_id"`
+	IncomeID  int64     `db:"income_id" json:"income_id"`
+	Currency  string    `db:"currency" json:"currency"`
+	Amount    float64   `db:"amount" json:"amount"`
+	Reason    string    `db:"reason" json:"reason"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
@@ -4,16 +4,15 @@ import "time"
 
 // Config model
 type Config struct {
-	ID                   int64     `db:"id"`
-	TotalRewardThreshold float64   `db:"total_reward_threshold"`
-	RefererRewardRate    float64   `db:"referer_reward_rate"`
-	DoubleOnWeekday      int       `db:"double_on_weekday"`
-	MinWithdrawalAmount  float64   `db:"min_withdrawal_amount"`
-	UpdatedAt            time.Time `db:"updated_at"`
-	CreatedAt            time.Time `db:"created_at"`
-}
-
-// DoubleToday returns true if double reward today
-func (c *Config) DoubleToday() bool {
-	return c.DoubleOnWeekday == int(time.Now().Weekday())
+	ID                   int64     `db:"id" json:"id"`
+	TotalRewardThreshold float64   `db:"total_reward_threshold" json:"total_reward_threshold"`
+	RefererRewardRate    float64   `db:"referer_reward_rate" json:"referer_reward_rate"`
+	MinWithdrawalAmount  float64   `db:"min_withdrawal_amount" json:"min_withdrawal_amount"`
+	WithdrawalInterval   int64     `db:"withdrawal_interval" json:"withdrawal_interval"`
+	WithdrawalFee        float64   `db:"withdrawal_fee" json:"withdrawal_fee"`
+	ReviewAmount         float64   `db:"review_amount" json:"review_amount"`
+	ReviewAccountDays    int64     `db:"review_account_days" json:"review_account_days"`
+	ReviewAddressDays    int64     `db:"review_address_days" json:"review_address_days"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+	CreatedAt            time.Time `db:"created_at" json:"created_at"`
 }
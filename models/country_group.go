@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// CountryGroup model, maps a country to a group sharing the same reward rates
+type CountryGroup struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Country   string    `db:"country"`
+	UpdatedAt time.Time `db:"updated_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
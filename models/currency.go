@@ -0,0 +1,67 @@
+package models
+
+import "math"
+
+// Currency model, a coin that balances, incomes and withdrawals are kept in.
+// Offerwall payouts and system config amounts are denominated in base currency,
+// i.e. coin type of deployment, and exchanged into other currencies by rate
+type Currency struct {
+	Code           string  `json:"code"`
+	ExchangeRate   float64 `json:"exchange_rate"`   // units of currency per unit of base currency
+	RewardInterval int64   `json:"reward_interval"` // seconds between claims, 0 falls back to user's reward interval
+}
+
+// FromBase exchanges amount of base currency into this currency
+func (c Currency) FromBase(amount float64) float64 {
+	return amount * c.ExchangeRate
+}
+
+// ToBase exchanges amount of this currency into base currency
+func (c Currency) ToBase(amount float64) float64 {
+	return amount / c.ExchangeRate
+}
+
+// Decimals returns number of decimals amounts of this currency are paid out in, alipay is paid in cents
+func (c Currency) Decimals() int {
+	if c.Code == CoinTypeAlipay {
+		return 2
+	}
+	return 8
+}
+
+// Floor rounds amount down to decimals of this currency, so that amount withdrawn is exactly amount sent
+func (c Currency) Floor(amount float64) float64 {
+	unit := math.Pow10(c.Decimals())
+	// tolerate float error, e.g. 0.29 * 100 is 28.999999999999996
+	return math.Floor(amount*unit+1e-6) / unit
+}
+
+// RewardIntervalOf returns reward interval of user claiming this currency
+func (c Currency) RewardIntervalOf(user User) int64 {
+	if c.RewardInterval > 0 {
+		return c.RewardInterval
+	}
+	return user.RewardInterval
+}
+
+// Currencies enabled in deployment, base currency first
+type Currencies []Currency
+
+// Base returns base currency
+func (cs Currencies) Base() Currency {
+	return cs[0]
+}
+
+// Of returns currency of code given, empty code means base currency
+func (cs Currencies) Of(code string) (Currency, bool) {
+	if code == "" {
+		return cs.Base(), true
+	}
+
+	for _, c := range cs {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Currency{}, false
+}
@@ -5,12 +5,19 @@ const (
 	EventHTTPRequest                  = "http request"
 	EventCreateWithdrawals            = "create withdrawals"
 	EventProcessWithdrawals           = "process withdrawals"
+	EventTrackWithdrawals             = "track withdrawals"
+	EventReconcilePayoutBatches       = "reconcile payout batches"
 	EventLogBalanceAndAddress         = "log balance and address"
+	EventMonitorWallet                = "monitor wallet"
 	EventValidateCaptcha              = "validate captcha"
 	EventRegisterCaptcha              = "register captcha"
 	EventReward                       = "reward"
 	EventGetGeoFromIP                 = "get geo from ip"
 	EventUserSignup                   = "user signup"
+	EventUserAddressChange            = "user address change"
+	EventReferralCampaignClick        = "referral campaign click"
+	EventAchievementUnlocked          = "achievement unlocked"
+	EventAdminChange                  = "admin change"
 	EventSuperrewardsCallback         = "superrewards callback"
 	EventSuperrewardsInvalidSignature = "superrewards invalid signature"
 	EventPTCWallCallback              = "ptcwall callback"
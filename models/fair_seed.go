@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// FairSeed status
+const (
+	FairSeedStatusActive   = "active"
+	FairSeedStatusRevealed = "revealed"
+)
+
+// FairSeed model, server seed is committed by its hash and revealed on rotation
+type FairSeed struct {
+	ID             int64      `db:"id" json:"-"`
+	UserID         int64      `db:"user_id" json:"-"`
+	ServerSeed     string     `db:"server_seed" json:"server_seed,omitempty"`
+	ServerSeedHash string     `db:"server_seed_hash" json:"server_seed_hash"`
+	ClientSeed     string     `db:"client_seed" json:"client_seed"`
+	Nonce          int64      `db:"nonce" json:"nonce"`
+	Status         string     `db:"status" json:"status"`
+	RevealedAt     *time.Time `db:"revealed_at" json:"revealed_at,omitempty"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"-"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+}
+
+// Masked returns fair seed with server seed hidden unless it has been revealed
+func (s FairSeed) Masked() FairSeed {
+	if s.Status != FairSeedStatusRevealed {
+		s.ServerSeed = ""
+	}
+	return s
+}
+
+// FairRoll model, reward rates used by a fair reward are kept so that it can be verified after rates change
+type FairRoll struct {
+	ID          int64     `db:"id" json:"-"`
+	FairSeedID  int64     `db:"fair_seed_id" json:"-"`
+	Nonce       int64     `db:"nonce" json:"nonce"`
+	Currency    string    `db:"currency" json:"currency"`
+	RewardRates string    `db:"reward_rates" json:"-"` // json of []RewardRate
+	Reward      float64   `db:"reward" json:"reward"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
@@ -24,6 +24,8 @@ const (
 	IncomeTypeAdscendMedia = 8
 	IncomeTypeAdgateMedia  = 9
 	IncomeTypeOffertoro    = 10
+	IncomeTypeAchievement  = 11
+	IncomeTypeAdmin        = 12
 )
 
 var incomeTypes = map[int64]string{
@@ -36,6 +38,13 @@ var incomeTypes = map[int64]string{
 	IncomeTypeAdscendMedia: "adscend media",
 	IncomeTypeAdgateMedia:  "adgate media",
 	IncomeTypeOffertoro:    "offertoro",
+	IncomeTypeAchievement:  "achievement",
+	IncomeTypeAdmin:        "admin",
+}
+
+// IncomeTypeName returns name of income type, e.g. offerwall provider
+func IncomeTypeName(t int64) string {
+	return incomeTypes[t]
 }
 
 // Income model
@@ -44,12 +53,29 @@ type Income struct {
 	UserID        int64     `db:"user_id"`
 	RefererID     int64     `db:"referer_id"`
 	Type          int64     `db:"type"`
+	Currency      string    `db:"currency"`
 	Income        float64   `db:"income"`
 	RefererIncome float64   `db:"referer_income"`
 	CreatedAt     time.Time `db:"created_at"`
 	Status        string    `db:"status"`
 }
 
+// IsOfferwall tells if income comes from offerwall
+func (i Income) IsOfferwall() bool {
+	return i.Type != IncomeTypeReward && i.Type != IncomeTypeAchievement && i.Type != IncomeTypeAdmin
+}
+
+// XP returns experience earned from income
+func (i Income) XP() int64 {
+	switch {
+	case i.Type == IncomeTypeReward:
+		return XPPerReward
+	case i.IsOfferwall():
+		return XPPerOfferwall
+	}
+	return 0
+}
+
 // MarshalJSON implements json.Marshaler interface
 func (i Income) MarshalJSON() ([]byte, error) {
 	t, ok := incomeTypes[i.Type]
@@ -58,12 +84,13 @@ func (i Income) MarshalJSON() ([]byte, error) {
 	}
 
 	// FIXME: it's silly to put income status in type, FUCK MY CODE
-	if i.Type != IncomeTypeReward {
+	if i.IsOfferwall() {
 		t = fmt.Sprintf("%s.%s", t, i.Status)
 	}
 
 	return json.Marshal(map[string]interface{}{
 		"type":           t,
+		"currency":       i.Currency,
 		"income":         i.Income,
 		"referer_income": i.RefererIncome,
 		"created_at":     i.CreatedAt,
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// XP earned per income
+const (
+	XPPerReward    = 1
+	XPPerOfferwall = 10
+)
+
+// Level model
+type Level struct {
+	ID                int64     `db:"id" json:"-"`
+	Level             int64     `db:"level" json:"level"`
+	MinXP             int64     `db:"min_xp" json:"min_xp"`
+	RewardInterval    int64     `db:"reward_interval" json:"reward_interval"`
+	RewardMultiplier  float64   `db:"reward_multiplier" json:"reward_multiplier"`
+	RefererRewardRate float64   `db:"referer_reward_rate" json:"referer_reward_rate"`
+	UpdatedAt         time.Time `db:"updated_at" json:"-"`
+	CreatedAt         time.Time `db:"created_at" json:"-"`
+}
+
+// RewardIntervalOf returns reward interval of this level if it is shorter than interval given
+func (l Level) RewardIntervalOf(interval int64) int64 {
+	if l.RewardInterval > 0 && l.RewardInterval < interval {
+		return l.RewardInterval
+	}
+	return interval
+}
+
+// RefererRewardRateOf returns referer reward rate of this level if it is higher than the system one
+func (l Level) RefererRewardRateOf(config Config) float64 {
+	if l.RefererRewardRate > config.RefererRewardRate {
+		return l.RefererRewardRate
+	}
+	return config.RefererRewardRate
+}
+
+// Levels sorted by min_xp ascending
+type Levels []Level
+
+// Of returns level that xp reaches, defaults to a level without any benefit
+func (ls Levels) Of(xp int64) Level {
+	level := Level{RewardMultiplier: 1}
+	for _, l := range ls {
+		if l.MinXP > xp {
+			break
+		}
+		level = l
+	}
+	return level
+}
+
+// Next returns next level that xp does not reach yet
+func (ls Levels) Next(xp int64) (Level, bool) {
+	for _, l := range ls {
+		if l.MinXP > xp {
+			return l, true
+		}
+	}
+	return Level{}, false
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PayoutBatchStatus, failed batch is known to pay nothing,
+// batch in review may have paid some of its withdrawals and needs manual check
+const (
+	PayoutBatchStatusSending = 0
+	PayoutBatchStatusSent    = 1
+	PayoutBatchStatusFailed  = 2
+	PayoutBatchStatusReview  = 3
+)
+
+// PayoutBatch model, journal of a transaction paying withdrawals out,
+// written before coins are sent so that unfinished batches can be reconciled with wallet
+type PayoutBatch struct {
+	ID        int64     `db:"id" json:"id"`
+	BatchID   string    `db:"batch_id" json:"batch_id"`
+	Status    int64     `db:"status" json:"status"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
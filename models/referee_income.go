@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// RefereeIncome period
+const (
+	RefereeIncomePeriodDay  = "day"
+	RefereeIncomePeriodWeek = "week"
+)
+
+// RefereeIncome sort by
+const (
+	RefereeIncomeSortByPeriod    = "period"
+	RefereeIncomeSortByAmount    = "amount"
+	RefereeIncomeSortByRefereeID = "referee_id"
+)
+
+// RefereeIncome model, referer commission from a referee aggregated by period and currency
+type RefereeIncome struct {
+	RefereeID       int64     `db:"referee_id" json:"referee_id"`
+	Address         string    `db:"address" json:"address"`
+	Period          time.Time `db:"period" json:"period"`
+	Currency        string    `db:"currency" json:"currency"`
+	Amount          float64   `db:"amount" json:"amount"`
+	NumberOfIncomes int64     `db:"number_of_incomes" json:"number_of_incomes"`
+}
+
+// RefereeIncomeQuery describes how referee incomes are filtered, aggregated and sorted
+type RefereeIncomeQuery struct {
+	RefererID int64
+	Since     time.Time // inclusive
+	Until     time.Time // exclusive
+	Period    string
+	SortBy    string
+	Desc      bool
+}
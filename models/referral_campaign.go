@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ReferralCampaign model
+type ReferralCampaign struct {
+	ID              int64     `db:"id" json:"id,omitempty"`
+	UserID          int64     `db:"user_id" json:"-"`
+	Code            string    `db:"code" json:"code"`
+	Name            string    `db:"name" json:"name"`
+	Clicks          int64     `db:"clicks" json:"clicks"`
+	Signups         int64     `db:"signups" json:"signups"`
+	VerifiedSignups int64     `db:"verified_signups" json:"verified_signups"`
+	Earnings        float64   `db:"earnings" json:"earnings"`
+	UpdatedAt       time.Time `db:"updated_at" json:"-"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
@@ -10,11 +10,13 @@ const (
 
 // RewardRate model
 type RewardRate struct {
-	ID        int64     `db:"id"`
-	Min       float64   `db:"min"`
-	Max       float64   `db:"max"`
-	Weight    int64     `db:"weight"`
-	Type      string    `db:"type"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID        int64     `db:"id" json:"id"`
+	Min       float64   `db:"min" json:"min"`
+	Max       float64   `db:"max" json:"max"`
+	Weight    int64     `db:"weight" json:"weight"`
+	Currency  string    `db:"currency" json:"currency"`
+	Type      string    `db:"type" json:"type"`
+	Scope     string    `db:"scope" json:"scope"` // empty for global, country code or country group name
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
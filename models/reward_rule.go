@@ -0,0 +1,111 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RewardRule model, a time-bound multiplier applied to reward
+type RewardRule struct {
+	ID             int64      `db:"id" json:"id"`
+	Name           string     `db:"name" json:"name"`
+	Description    string     `db:"description" json:"description"`
+	Priority       int64      `db:"priority" json:"-"`
+	Multiplier     float64    `db:"multiplier" json:"multiplier"`
+	StartsAt       *time.Time `db:"starts_at" json:"starts_at"`
+	EndsAt         *time.Time `db:"ends_at" json:"ends_at"`
+	Weekdays       string     `db:"weekdays" json:"weekdays"`
+	StartHour      int        `db:"start_hour" json:"start_hour"`
+	EndHour        int        `db:"end_hour" json:"end_hour"`
+	MaxAccountDays int64      `db:"max_account_days" json:"max_account_days"`
+	MinLevel       int64      `db:"min_level" json:"min_level"`
+	MaxLevel       int64      `db:"max_level" json:"max_level"`
+	ApplyToReferer bool       `db:"apply_to_referer" json:"apply_to_referer"`
+	Final          bool       `db:"final" json:"-"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"-"`
+	CreatedAt      time.Time  `db:"created_at" json:"-"`
+}
+
+// ActiveAt tells if time conditions of the rule are met, all in UTC
+func (r RewardRule) ActiveAt(now time.Time) bool {
+	now = now.UTC()
+	return !r.Expired(now) && (r.StartsAt == nil || !now.Before(*r.StartsAt)) &&
+		r.onWeekday(now.Weekday()) && r.atHour(now.Hour())
+}
+
+// Expired tells if the rule will never be active again
+func (r RewardRule) Expired(now time.Time) bool {
+	return r.EndsAt != nil && !now.Before(*r.EndsAt)
+}
+
+// Matches tells if the rule applies to user of level at the moment
+func (r RewardRule) Matches(now time.Time, user User, level int64) bool {
+	if !r.ActiveAt(now) {
+		return false
+	}
+
+	if r.MaxAccountDays > 0 && now.Sub(user.CreatedAt) > time.Duration(r.MaxAccountDays)*24*time.Hour {
+		return false
+	}
+
+	return (r.MinLevel == 0 || level >= r.MinLevel) && (r.MaxLevel == 0 || level <= r.MaxLevel)
+}
+
+func (r RewardRule) onWeekday(weekday time.Weekday) bool {
+	if r.Weekdays == "" {
+		return true
+	}
+
+	for _, v := range strings.Split(r.Weekdays, ",") {
+		if d, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && d == int(weekday) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RewardRule) atHour(hour int) bool {
+	switch {
+	case r.StartHour == r.EndHour:
+		return true
+	case r.StartHour < r.EndHour:
+		return hour >= r.StartHour && hour < r.EndHour
+	default: // wraps around midnight
+		return hour >= r.StartHour || hour < r.EndHour
+	}
+}
+
+// RewardRules sorted by priority ascending
+type RewardRules []RewardRule
+
+// Names returns names of rules
+func (rs RewardRules) Names() []string {
+	names := make([]string, len(rs))
+	for i := range rs {
+		names[i] = rs[i].Name
+	}
+	return names
+}
+
+// Apply stacks multipliers of rules matched in order until a final rule is matched,
+// returns multipliers of user and referer and the rules applied
+func (rs RewardRules) Apply(now time.Time, user User, level int64) (multiplier, refererMultiplier float64, applied RewardRules) {
+	multiplier, refererMultiplier = 1, 1
+	for _, r := range rs {
+		if !r.Matches(now, user, level) {
+			continue
+		}
+
+		applied = append(applied, r)
+		multiplier *= r.Multiplier
+		if r.ApplyToReferer {
+			refererMultiplier *= r.Multiplier
+		}
+
+		if r.Final {
+			break
+		}
+	}
+	return
+}
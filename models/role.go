@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Permission of admin endpoints
+const (
+	PermissionAll               = "*"
+	PermissionConfigsRead       = "configs.read"
+	PermissionConfigsWrite      = "configs.write"
+	PermissionUsersRead         = "users.read"
+	PermissionUsersWrite        = "users.write"
+	PermissionBalancesWrite     = "balances.write"
+	PermissionRolesWrite        = "roles.write"
+	PermissionWithdrawalsRead   = "withdrawals.read"
+	PermissionWithdrawalsReview = "withdrawals.review"
+	PermissionStatsRead         = "stats.read"
+	PermissionAuditLogsRead     = "audit_logs.read"
+)
+
+// Role model
+type Role struct {
+	ID          int64     `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"-"`
+}
+
+// RolePermission model, a permission granted to role
+type RolePermission struct {
+	Role       string `db:"role"`
+	Permission string `db:"permission"`
+}
+
+// RolePermissions maps role name to permissions granted
+type RolePermissions map[string][]string
+
+// NewRolePermissions groups permissions by role
+func NewRolePermissions(rps []RolePermission) RolePermissions {
+	result := RolePermissions{}
+	for _, rp := range rps {
+		result[rp.Role] = append(result[rp.Role], rp.Permission)
+	}
+	return result
+}
+
+// Has tells if role is granted permission, users without role have no permission
+func (r RolePermissions) Has(role, permission string) bool {
+	if role == "" {
+		return false
+	}
+
+	for _, p := range r[role] {
+		if p == PermissionAll || p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminAuditLog model, an admin request
+type AdminAuditLog struct {
+	ID         int64     `db:"id" json:"id"`
+	UserID     int64     `db:"user_id" json:"user_id"`
+	Action     string    `db:"action" json:"action"`
+	Target     string    `db:"target" json:"target"` // route params
+	Body       string    `db:"body" json:"body"`
+	StatusCode int       `db:"status_code" json:"status_code"`
+	IP         string    `db:"ip" json:"ip"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,68 @@
+package models
+
+// UserStats model, users aggregated for admin dashboard
+type UserStats struct {
+	Users    int64 `db:"users" json:"users"`
+	Verified int64 `db:"verified" json:"verified"`
+	Banned   int64 `db:"banned" json:"banned"`
+	Signups  int64 `db:"signups" json:"signups"`
+}
+
+// WithdrawalStats model, withdrawals aggregated per status
+type WithdrawalStats struct {
+	Status              int64   `db:"status" json:"status"`
+	NumberOfWithdrawals int64   `db:"number_of_withdrawals" json:"number_of_withdrawals"`
+	Amount              float64 `db:"amount" json:"amount"`
+}
+
+// OfferwallRevenue model, offerwall incomes aggregated per provider and currency
+type OfferwallRevenue struct {
+	Type             int64   `db:"type" json:"-"`
+	Provider         string  `db:"-" json:"provider"`
+	Currency         string  `db:"currency" json:"currency"`
+	NumberOfIncomes  int64   `db:"number_of_incomes" json:"number_of_incomes"`
+	Amount           float64 `db:"amount" json:"amount"`
+	RefererAmount    float64 `db:"referer_amount" json:"referer_amount"`
+	ChargebackAmount float64 `db:"chargeback_amount" json:"chargeback_amount"`
+}
+
+// WalletRunway model, hot wallet balance compared with coins owed to users
+type WalletRunway struct {
+	Balance            float64  `json:"balance"`
+	PendingWithdrawals float64  `json:"pending_withdrawals"` // withdrawals requested but not sent yet
+	UserBalances       float64  `json:"user_balances"`
+	Liabilities        float64  `json:"liabilities"`
+	Shortfall          float64  `json:"shortfall"`    // liabilities not covered by balance
+	DailyPayout        float64  `json:"daily_payout"` // average paid out per day recently
+	RunwayDays         *float64 `json:"runway_days"`  // days balance lasts after pending withdrawals, null if nothing is paid out recently
+}
+
+// NewWalletRunway estimates how long balance lasts at daily payout given
+func NewWalletRunway(balance, pendingWithdrawals, userBalances, dailyPayout float64) WalletRunway {
+	r := WalletRunway{
+		Balance:            balance,
+		PendingWithdrawals: pendingWithdrawals,
+		UserBalances:       userBalances,
+		Liabilities:        pendingWithdrawals + userBalances,
+		DailyPayout:        dailyPayout,
+	}
+	if r.Liabilities > balance {
+		r.Shortfall = r.Liabilities - balance
+	}
+
+	switch {
+	case balance <= pendingWithdrawals:
+		days := 0.0
+		r.RunwayDays = &days
+	case dailyPayout > 0:
+		days := (balance - pendingWithdrawals) / dailyPayout
+		r.RunwayDays = &days
+	}
+
+	return r
+}
+
+// Below reports if balance lasts less than days given
+func (r WalletRunway) Below(days float64) bool {
+	return r.RunwayDays != nil && *r.RunwayDays < days
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// StreakBonus model
+type StreakBonus struct {
+	ID         int64     `db:"id" json:"-"`
+	Days       int64     `db:"days" json:"days"`
+	Multiplier float64   `db:"multiplier" json:"multiplier"`
+	UpdatedAt  time.Time `db:"updated_at" json:"-"`
+	CreatedAt  time.Time `db:"created_at" json:"-"`
+}
+
+// StreakBonuses sorted by days ascending
+type StreakBonuses []StreakBonus
+
+// Of returns bonus that streak days reaches, defaults to no bonus
+func (bs StreakBonuses) Of(days int64) StreakBonus {
+	bonus := StreakBonus{Multiplier: 1}
+	for _, b := range bs {
+		if b.Days > days {
+			break
+		}
+		bonus = b
+	}
+	return bonus
+}
+
+// Next returns next bonus that streak days does not reach yet
+func (bs StreakBonuses) Next(days int64) (StreakBonus, bool) {
+	for _, b := range bs {
+		if b.Days > days {
+			return b, true
+		}
+	}
+	return StreakBonus{}, false
+}
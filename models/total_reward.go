@@ -4,9 +4,10 @@ import "time"
 
 // TotalReward model
 type TotalReward struct {
-	ID        int64     `db:"id"`
-	Total     float64   `db:"total"`
-	CreatedAt time.Time `db:"created_at"`
+	ID        int64     `db:"id" json:"-"`
+	Currency  string    `db:"currency" json:"currency"`
+	Total     float64   `db:"total" json:"total"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
 
 // IsSameDay checks if created_at and now are in the same day
@@ -11,23 +11,71 @@ const (
 
 // User model
 type User struct {
-	ID                      int64     `db:"id" json:"id,omitempty"`
-	Email                   string    `db:"email" json:"email,omitempty"`
-	EmailSentAt             time.Time `db:"email_sent_at" json:"email_sent_at,omitempty"`
-	Address                 string    `db:"address" json:"address,omitempty"`
-	Status                  string    `db:"status" json:"status,omitempty"`
-	Balance                 float64   `db:"balance" json:"balance"`
-	TotalIncome             float64   `db:"total_income" json:"total_income"`
-	TotalIncomeFromReferees float64   `db:"total_income_from_referees" json:"total_income_from_referees"`
-	RefererTotalIncome      float64   `db:"referer_total_income" json:"referer_total_income"`
-	RewardInterval          int64     `db:"reward_interval" json:"reward_interval"`
-	RewardedAt              time.Time `db:"rewarded_at" json:"rewarded_at"`
-	RefererID               int64     `db:"referer_id" json:"-"`
-	UpdatedAt               time.Time `db:"updated_at" json:"-"`
-	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+	ID                 int64      `db:"id" json:"id,omitempty"`
+	Email              string     `db:"email" json:"email,omitempty"`
+	EmailSentAt        time.Time  `db:"email_sent_at" json:"email_sent_at,omitempty"`
+	Address            string     `db:"address" json:"address,omitempty"`
+	AddressUpdatedAt   *time.Time `db:"address_updated_at" json:"-"`
+	Status             string     `db:"status" json:"status,omitempty"`
+	Role               string     `db:"role" json:"role,omitempty"`
+	AutoWithdrawal     bool       `db:"auto_withdrawal" json:"auto_withdrawal"`
+	TOTPSecret         string     `db:"totp_secret" json:"-"`
+	TOTPEnabled        bool       `db:"totp_enabled" json:"totp_enabled"`
+	TOTPCounter        int64      `db:"totp_counter" json:"-"`
+	RewardInterval     int64      `db:"reward_interval" json:"reward_interval"`
+	XP                 int64      `db:"xp" json:"xp"`
+	StreakDays         int64      `db:"streak_days" json:"-"`
+	RewardedAt         time.Time  `db:"rewarded_at" json:"rewarded_at"` // last claim in any currency
+	Currency           string     `db:"currency" json:"currency"`       // currency offerwall earnings go to, empty means base currency
+	RefererID          int64      `db:"referer_id" json:"-"`
+	ReferralCampaignID int64      `db:"referral_campaign_id" json:"-"`
+	UpdatedAt          time.Time  `db:"updated_at" json:"-"`
+	CreatedAt          time.Time  `db:"created_at" json:"created_at"`
 }
 
 // HasReferer indicates if the user is referred by another user
 func (u User) HasReferer() bool {
 	return u.RefererID > 0
 }
+
+// CurrentStreak returns consecutive days with at least one claim,
+// streak is reset once user misses a day
+func (u User) CurrentStreak(now time.Time) int64 {
+	if daysBetween(u.RewardedAt, now) > 1 {
+		return 0
+	}
+	return u.StreakDays
+}
+
+// NextStreak returns streak days if user claims at now, days are counted in UTC
+func (u User) NextStreak(now time.Time) int64 {
+	switch daysBetween(u.RewardedAt, now) {
+	case 0:
+		if u.StreakDays > 1 {
+			return u.StreakDays
+		}
+		return 1
+	case 1:
+		return u.StreakDays + 1
+	default:
+		return 1
+	}
+}
+
+// number of days between dates of t1 and t2 in UTC
+func daysBetween(t1, t2 time.Time) int64 {
+	date := func(t time.Time) time.Time {
+		y, m, d := t.UTC().Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int64(date(t2).Sub(date(t1)).Hours() / 24)
+}
+
+// UserQuery filters users searched by admin, zero value fields are ignored,
+// email and address are matched by prefix, address against whole address book of user
+type UserQuery struct {
+	ID        int64
+	RefererID int64
+	Email     string
+	Address   string
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// MaxUserAddresses limits addresses in address book of a user
+const MaxUserAddresses = 10
+
+// UserAddress model, an entry in user's address book, withdrawals of a currency
+// are paid to default address of the currency, the one of base currency is mirrored to users.address
+type UserAddress struct {
+	ID          int64     `db:"id" json:"id"`
+	UserID      int64     `db:"user_id" json:"-"`
+	Currency    string    `db:"currency" json:"currency"`
+	Address     string    `db:"address" json:"address"`
+	Label       string    `db:"label" json:"label"`
+	VerifyToken string    `db:"verify_token" json:"-"`
+	Verified    bool      `db:"verified" json:"verified"`
+	IsDefault   bool      `db:"is_default" json:"is_default"`
+	UpdatedAt   time.Time `db:"updated_at" json:"-"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
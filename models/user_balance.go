@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserBalance model, balance and earnings of user in a currency
+type UserBalance struct {
+	UserID                  int64     `db:"user_id" json:"-"`
+	Currency                string    `db:"currency" json:"currency"`
+	Balance                 float64   `db:"balance" json:"balance"`
+	TotalIncome             float64   `db:"total_income" json:"total_income"`
+	TotalIncomeFromReferees float64   `db:"total_income_from_referees" json:"total_income_from_referees"`
+	RefererTotalIncome      float64   `db:"referer_total_income" json:"referer_total_income"`
+	RewardedAt              time.Time `db:"rewarded_at" json:"rewarded_at"` // last claim of faucet in this currency
+}
@@ -1,22 +1,63 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // WithdrawalStatus
 const (
 	WithdrawalStatusPending    = 0
 	WithdrawalStatusProcessing = 1
 	WithdrawalStatusProcessed  = 2
+	WithdrawalStatusReview     = 3
+	WithdrawalStatusRejected   = 4
+	WithdrawalStatusFailed     = 5
 )
 
 // Withdrawal model
 type Withdrawal struct {
-	ID            int64     `db:"id" json:"id"`
-	UserID        int64     `db:"user_id" json:"user_id"`
-	Address       string    `db:"address" json:"address"`
-	Amount        float64   `db:"amount" json:"amount"`
-	Status        int64     `db:"status" json:"status"`
-	TransactionID string    `db:"transaction_id" json:"tx_id"`
-	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
-	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	ID            int64      `db:"id" json:"id"`
+	UserID        int64      `db:"user_id" json:"user_id"`
+	Currency      string     `db:"currency" json:"currency"`
+	Address       string     `db:"address" json:"address"`
+	Amount        float64    `db:"amount" json:"amount"`
+	Fee           float64    `db:"fee" json:"fee"`
+	Status        int64      `db:"status" json:"status"`
+	TransactionID string     `db:"transaction_id" json:"tx_id"`
+	PayoutBatchID string     `db:"payout_batch_id" json:"-"`
+	Confirmations int64      `db:"confirmations" json:"confirmations"`
+	BlockTime     *time.Time `db:"block_time" json:"block_time,omitempty"`
+	Stuck         bool       `db:"stuck" json:"stuck"`
+	ReviewReason  string     `db:"review_reason" json:"-"`
+	RejectReason  string     `db:"reject_reason" json:"reject_reason,omitempty"`
+	ProcessedAt   *time.Time `db:"processed_at" json:"processed_at,omitempty"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// AmountToSend returns amount paid out to user after fee charged
+func (w Withdrawal) AmountToSend() float64 {
+	return w.Amount - w.Fee
+}
+
+// WithdrawalReviewReason returns review rules of config matched by withdrawal of user,
+// empty string means withdrawal can be paid out without review
+func (c Config) WithdrawalReviewReason(u User, amount float64, now time.Time) string {
+	reasons := []string{}
+
+	if c.ReviewAmount > 0 && amount > c.ReviewAmount {
+		reasons = append(reasons, fmt.Sprintf("amount above %v", c.ReviewAmount))
+	}
+
+	if c.ReviewAccountDays > 0 && u.CreatedAt.AddDate(0, 0, int(c.ReviewAccountDays)).After(now) {
+		reasons = append(reasons, fmt.Sprintf("account younger than %v days", c.ReviewAccountDays))
+	}
+
+	if c.ReviewAddressDays > 0 && u.AddressUpdatedAt != nil && u.AddressUpdatedAt.AddDate(0, 0, int(c.ReviewAddressDays)).After(now) {
+		reasons = append(reasons, fmt.Sprintf("address changed within %v days", c.ReviewAddressDays))
+	}
+
+	return strings.Join(reasons, ", ")
 }
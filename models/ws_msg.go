@@ -5,4 +5,5 @@ type WebsocketMessage struct {
 	UsersOnline   int           `json:"users_online,omitempty"`
 	LatestIncomes []interface{} `json:"latest_incomes,omitempty"`
 	DeltaIncome   interface{}   `json:"delta_income,omitempty"`
+	Achievement   interface{}   `json:"achievement,omitempty"`
 }
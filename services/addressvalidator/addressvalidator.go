@@ -0,0 +1,62 @@
+package addressvalidator
+
+import (
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// address formats of a network of coin
+type addressParams struct {
+	versions  []byte // base58check version bytes of pay-to-pubkey-hash and pay-to-script-hash
+	segwitHRP string // human readable part of bech32 address, empty if segwit is not supported
+}
+
+var mainnetAddressParams = map[string]addressParams{
+	models.CoinTypeBitcoin:  {versions: []byte{0x00, 0x05}, segwitHRP: "bc"},
+	models.CoinTypeLitecoin: {versions: []byte{0x30, 0x32, 0x05}, segwitHRP: "ltc"}, // 0x05 is deprecated p2sh
+	models.CoinTypeDogecoin: {versions: []byte{0x1e, 0x16}},
+	models.CoinTypeDashcoin: {versions: []byte{0x4c, 0x10}},
+}
+
+var testnetAddressParams = map[string]addressParams{
+	models.CoinTypeBitcoin:  {versions: []byte{0x6f, 0xc4}, segwitHRP: "tb"},
+	models.CoinTypeLitecoin: {versions: []byte{0x6f, 0x3a, 0xc4}, segwitHRP: "tltc"},
+	models.CoinTypeDogecoin: {versions: []byte{0x71, 0xc4}},
+	models.CoinTypeDashcoin: {versions: []byte{0x8c, 0x13}},
+}
+
+// Validate checks address of coin type on mainnet or testnet offline,
+// base58check and segwit addresses are supported, eth address is checked against EIP-55 checksum,
+// address of other coin types is invalid
+func Validate(coinType, address string, testnet bool) bool {
+	if coinType == models.CoinTypeEthereum {
+		return IsValidEthereumAddress(address)
+	}
+
+	params, ok := mainnetAddressParams[coinType]
+	if testnet {
+		params, ok = testnetAddressParams[coinType]
+	}
+	if !ok {
+		return false
+	}
+
+	if params.segwitHRP != "" && strings.HasPrefix(strings.ToLower(address), params.segwitHRP+"1") {
+		_, _, err := decodeSegwitAddress(params.segwitHRP, address)
+		return err == nil
+	}
+
+	// payload is hash160 of pubkey or script
+	payload, version, err := base58.CheckDecode(address)
+	if err != nil || len(payload) != 20 {
+		return false
+	}
+	for _, v := range params.versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
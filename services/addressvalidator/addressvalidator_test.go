@@ -0,0 +1,66 @@
+package addressvalidator
+
+import (
+	"testing"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestValidate(t *testing.T) {
+	testdata := []struct {
+		coinType string
+		address  string
+		testnet  bool
+		expected bool
+	}{
+		// base58check of same hash160 with version of each network
+		{models.CoinTypeBitcoin, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", false, true},
+		{models.CoinTypeBitcoin, "3CNHUhP3uyB9EUtRLsmvFUmvGdjGdkTxJw", false, true},
+		{models.CoinTypeBitcoin, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMh", false, false}, // wrong checksum
+		{models.CoinTypeBitcoin, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", false, false},
+		{models.CoinTypeBitcoin, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", true, true},
+		{models.CoinTypeBitcoin, "2N3vVYSK5XRgVSGWy21PnsRmBUywSQNdCsf", true, true},
+		{models.CoinTypeBitcoin, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", true, false},
+		{models.CoinTypeLitecoin, "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ", false, true},
+		{models.CoinTypeLitecoin, "MJaRnao1s62a2zAKSkmG582KbLKianqb7v", false, true},
+		{models.CoinTypeLitecoin, "3CNHUhP3uyB9EUtRLsmvFUmvGdjGdkTxJw", false, true},
+		{models.CoinTypeLitecoin, "QXHFfTBKYXjaaTH1e7Rox8CcdNPGHVhM59", true, true},
+		{models.CoinTypeLitecoin, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", false, false},
+		{models.CoinTypeDogecoin, "DFpN6QqFfUm3gKNaxN6tNcab1FArL9cZLE", false, true},
+		{models.CoinTypeDogecoin, "A37YDYSwz3438rFtm1SLVcQHyD7JeueC9H", false, true},
+		{models.CoinTypeDogecoin, "nesRpRaAbTDmZHwmzBkLd2AtF7Z9L9z5S2", true, true},
+		{models.CoinTypeDogecoin, "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ", false, false},
+		{models.CoinTypeDashcoin, "XmN7PQYWKn5MJFna5fRYgP6mxT2F7xpekE", false, true},
+		{models.CoinTypeDashcoin, "7d5vJtfDixGnEFRNcVSRarmaCBZeScHACn", false, true},
+		{models.CoinTypeDashcoin, "yWziQMcwmKjRdzi7eWjwiQX8EjWcd6dSg6", true, true},
+		{models.CoinTypeDashcoin, "8q6jGDZ5rVfQgYqdgkSP3Eaw5hLUXD8Nyi", true, true},
+		{models.CoinTypeDashcoin, "XmN7PQYWKn5MJFna5fRYgP6mxT2F7xpekE", true, false},
+
+		// segwit, vectors of BIP-173 and BIP-350 with same witness program on each network
+		{models.CoinTypeBitcoin, "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", false, true},
+		{models.CoinTypeBitcoin, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false, true},
+		{models.CoinTypeBitcoin, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", false, false}, // wrong checksum
+		{models.CoinTypeBitcoin, "bc1qw508d6qejxtdg4y5r3zarvarY0c5xw7kv8f3t4", false, false}, // mixed case
+		{models.CoinTypeBitcoin, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", true, true},
+		{models.CoinTypeBitcoin, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", false, false},
+		{models.CoinTypeBitcoin, "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", true, true},
+		{models.CoinTypeBitcoin, "bc1p94c3vs4hy6cygqtz0j5lhtpj7hy9xra3jq7vfkczykr30ys6fzqs3kh3lj", false, true},
+		{models.CoinTypeBitcoin, "bc1p94c3vs4hy6cygqtz0j5lhtpj7hy9xra3jq7vfkczykr30ys6fzqsy28a6s", false, false}, // version 1 in bech32
+		{models.CoinTypeBitcoin, "bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du", false, false},                          // invalid program length
+		{models.CoinTypeLitecoin, "ltc1qw508d6qejxtdg4y5r3zarvary0c5xw7kgmn4n9", false, true},
+		{models.CoinTypeLitecoin, "tltc1qw508d6qejxtdg4y5r3zarvary0c5xw7klfsuq0", true, true},
+		{models.CoinTypeLitecoin, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false, false},
+		{models.CoinTypeDogecoin, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false, false},
+
+		{models.CoinTypeEthereum, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false, true},
+		{models.CoinTypeEthereum, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false, false},
+		{models.CoinTypeAlipay, "someone@example.com", false, false},
+		{models.CoinTypeBitcoin, "", false, false},
+	}
+
+	for _, v := range testdata {
+		if valid := Validate(v.coinType, v.address, v.testnet); valid != v.expected {
+			t.Errorf("validity of %v address %v on testnet %v should be %v but get %v", v.coinType, v.address, v.testnet, v.expected, valid)
+		}
+	}
+}
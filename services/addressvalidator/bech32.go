@@ -0,0 +1,136 @@
+package addressvalidator
+
+import (
+	"fmt"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// checksum constants of BIP-173 bech32 and BIP-350 bech32m
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := uint(0); i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	result := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]>>5)
+	}
+	result = append(result, 0)
+	for i := 0; i < len(hrp); i++ {
+		result = append(result, hrp[i]&31)
+	}
+	return result
+}
+
+// decodeBech32 returns human readable part, 5 bits data without checksum and checksum constant
+func decodeBech32(s string) (hrp string, data []byte, checksum uint32, err error) {
+	if len(s) > 90 {
+		return "", nil, 0, fmt.Errorf("bech32 string too long")
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, fmt.Errorf("bech32 string of mixed case")
+	}
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndex(s, "1")
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, 0, fmt.Errorf("invalid bech32 separator position")
+	}
+
+	hrp = s[:pos]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, fmt.Errorf("invalid bech32 human readable part")
+		}
+	}
+
+	for i := pos + 1; i < len(s); i++ {
+		d := strings.IndexByte(bech32Charset, s[i])
+		if d < 0 {
+			return "", nil, 0, fmt.Errorf("invalid bech32 character %q", s[i])
+		}
+		data = append(data, byte(d))
+	}
+
+	checksum = bech32Polymod(append(bech32HRPExpand(hrp), data...))
+	if checksum != bech32Const && checksum != bech32mConst {
+		return "", nil, 0, fmt.Errorf("invalid bech32 checksum")
+	}
+
+	return hrp, data[:len(data)-6], checksum, nil
+}
+
+// regroup bits of data, padding is not allowed when decoding
+func convertBits(data []byte, fromBits, toBits uint) ([]byte, error) {
+	acc, bits := uint32(0), uint(0)
+	maxv := uint32(1)<<toBits - 1
+	maxAcc := uint32(1)<<(fromBits+toBits-1) - 1
+	result := []byte{}
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data range")
+		}
+		acc = (acc<<fromBits | uint32(v)) & maxAcc
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxv))
+		}
+	}
+
+	if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return result, nil
+}
+
+// decodeSegwitAddress returns witness version and program of segwit address with human readable part given,
+// version 0 is encoded in bech32, later versions in bech32m
+func decodeSegwitAddress(hrp, address string) (version byte, program []byte, err error) {
+	decodedHRP, data, checksum, err := decodeBech32(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if decodedHRP != hrp {
+		return 0, nil, fmt.Errorf("human readable part %v does not match %v", decodedHRP, hrp)
+	}
+	if len(data) < 1 || data[0] > 16 {
+		return 0, nil, fmt.Errorf("invalid witness version")
+	}
+
+	version = data[0]
+	program, err = convertBits(data[1:], 5, 8)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	switch {
+	case len(program) < 2 || len(program) > 40:
+		return 0, nil, fmt.Errorf("invalid witness program length %v", len(program))
+	case version == 0 && len(program) != 20 && len(program) != 32:
+		return 0, nil, fmt.Errorf("invalid witness program length %v of version 0", len(program))
+	case version == 0 && checksum != bech32Const, version != 0 && checksum != bech32mConst:
+		return 0, nil, fmt.Errorf("invalid checksum encoding of witness version %v", version)
+	}
+
+	return version, program, nil
+}
@@ -0,0 +1,41 @@
+package addressvalidator
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/solefaucet/sole-server/utils"
+)
+
+// IsValidEthereumAddress checks if address is 0x prefixed 20 bytes hex,
+// mixed-case address must match its EIP-55 checksum
+func IsValidEthereumAddress(address string) bool {
+	if len(address) != 42 || !strings.HasPrefix(address, "0x") {
+		return false
+	}
+	if _, err := hex.DecodeString(address[2:]); err != nil {
+		return false
+	}
+
+	// all lower or all upper case address carries no checksum
+	hexPart := address[2:]
+	if hexPart == strings.ToLower(hexPart) || hexPart == strings.ToUpper(hexPart) {
+		return true
+	}
+	return address == ChecksumEthereumAddress(address)
+}
+
+// ChecksumEthereumAddress returns EIP-55 mixed-case checksum encoding of address,
+// a hex letter is uppercased if the corresponding nibble of keccak256(lowercase hex) >= 8
+func ChecksumEthereumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := hex.EncodeToString(utils.Keccak256([]byte(lower)))
+
+	result := []byte(lower)
+	for i, c := range result {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
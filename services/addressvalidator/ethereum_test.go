@@ -0,0 +1,36 @@
+package addressvalidator
+
+import "testing"
+
+func TestIsValidEthereumAddress(t *testing.T) {
+	// test vectors of EIP-55
+	checksummed := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	}
+	for _, address := range checksummed {
+		if !IsValidEthereumAddress(address) {
+			t.Errorf("%v should be valid", address)
+		}
+		if c := ChecksumEthereumAddress(address); c != address {
+			t.Errorf("checksum address should be %v but get %v", address, c)
+		}
+	}
+
+	testdata := map[string]bool{
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed":  true,
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED":  true,
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD":  false, // wrong checksum
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed":    false,
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea":    false,
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaeg":  false,
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed0": false,
+	}
+	for address, expected := range testdata {
+		if valid := IsValidEthereumAddress(address); valid != expected {
+			t.Errorf("validity of %v should be %v but get %v", address, expected, valid)
+		}
+	}
+}
@@ -8,11 +8,29 @@ import (
 
 // Cache defines interface that one should implement
 type Cache interface {
-	GetLatestTotalReward() models.TotalReward
-	IncrementTotalReward(time.Time, float64)
+	GetLatestTotalReward(currency string) models.TotalReward
+	IncrementTotalReward(currency string, t time.Time, delta float64)
 
-	GetRewardRatesByType(string) []models.RewardRate
+	GetRewardRatesByType(currency, rewardRateType string) []models.RewardRate
 	SetRewardRates(string, []models.RewardRate)
+	GetRewardRatesByCountry(currency, country, rewardRateType string) []models.RewardRate
+	SetScopedRewardRates([]models.RewardRate)
+	SetCountryGroups([]models.CountryGroup)
+
+	GetLevels() models.Levels
+	SetLevels(models.Levels)
+
+	GetStreakBonuses() models.StreakBonuses
+	SetStreakBonuses(models.StreakBonuses)
+
+	GetAchievements() models.Achievements
+	SetAchievements(models.Achievements)
+
+	GetRewardRules() models.RewardRules
+	SetRewardRules(models.RewardRules)
+
+	GetRolePermissions() models.RolePermissions
+	SetRolePermissions(models.RolePermissions)
 
 	GetLatestConfig() models.Config
 	SetLatestConfig(models.Config)
@@ -10,11 +10,28 @@ import (
 
 // Cache implements cache.Cache interface with memory
 type Cache struct {
-	totalReward      models.TotalReward
+	totalRewards     map[string]models.TotalReward // currency -> total reward
 	totalRewardMutex sync.RWMutex
 
-	rewardRatesMapping map[string][]models.RewardRate
-	rewardRatesMutex   sync.RWMutex
+	rewardRatesMapping       map[string]map[string][]models.RewardRate            // currency -> type -> rates
+	scopedRewardRatesMapping map[string]map[string]map[string][]models.RewardRate // currency -> scope -> type -> rates
+	countryGroups            map[string]string                                    // country -> group
+	rewardRatesMutex         sync.RWMutex
+
+	levels      models.Levels
+	levelsMutex sync.RWMutex
+
+	streakBonuses      models.StreakBonuses
+	streakBonusesMutex sync.RWMutex
+
+	achievements      models.Achievements
+	achievementsMutex sync.RWMutex
+
+	rewardRules      models.RewardRules
+	rewardRulesMutex sync.RWMutex
+
+	rolePermissions      models.RolePermissions
+	rolePermissionsMutex sync.RWMutex
 
 	config      models.Config
 	configMutex sync.RWMutex
@@ -30,42 +47,180 @@ type Cache struct {
 // number of cached incomes
 func New(numCachedIncomes int) *Cache {
 	return &Cache{
-		rewardRatesMapping: make(map[string][]models.RewardRate),
-		incomesRing:        ring.New(numCachedIncomes),
+		totalRewards:             make(map[string]models.TotalReward),
+		rewardRatesMapping:       make(map[string]map[string][]models.RewardRate),
+		scopedRewardRatesMapping: make(map[string]map[string]map[string][]models.RewardRate),
+		countryGroups:            make(map[string]string),
+		incomesRing:              ring.New(numCachedIncomes),
 	}
 }
 
-// GetLatestTotalReward returns total reward of today
-func (c *Cache) GetLatestTotalReward() models.TotalReward {
+// GetLatestTotalReward returns total reward of currency today
+func (c *Cache) GetLatestTotalReward(currency string) models.TotalReward {
 	c.totalRewardMutex.RLock()
 	defer c.totalRewardMutex.RUnlock()
-	return c.totalReward
+	return c.totalRewards[currency]
 }
 
-// IncrementTotalReward increment total reward today by delta if day matches
-func (c *Cache) IncrementTotalReward(t time.Time, delta float64) {
+// IncrementTotalReward increment total reward of currency today by delta if day matches
+func (c *Cache) IncrementTotalReward(currency string, t time.Time, delta float64) {
 	c.totalRewardMutex.Lock()
 	defer c.totalRewardMutex.Unlock()
 
-	if c.totalReward.IsSameDay(t) {
-		c.totalReward.Total += delta
+	totalReward := c.totalRewards[currency]
+	if totalReward.IsSameDay(t) {
+		totalReward.Total += delta
 	} else {
-		c.totalReward = models.TotalReward{CreatedAt: t.UTC(), Total: delta}
+		totalReward = models.TotalReward{Currency: currency, CreatedAt: t.UTC(), Total: delta}
 	}
+	c.totalRewards[currency] = totalReward
 }
 
-// GetRewardRatesByType returns reward rates by type
-func (c *Cache) GetRewardRatesByType(t string) []models.RewardRate {
+// GetRewardRatesByType returns reward rates of currency by type
+func (c *Cache) GetRewardRatesByType(currency, t string) []models.RewardRate {
 	c.rewardRatesMutex.RLock()
 	defer c.rewardRatesMutex.RUnlock()
-	return c.rewardRatesMapping[t]
+	return c.rewardRatesMapping[currency][t]
 }
 
-// SetRewardRates sets reward rates with type
+// SetRewardRates replaces reward rates with type of every currency
 func (c *Cache) SetRewardRates(t string, rates []models.RewardRate) {
+	mapping := make(map[string][]models.RewardRate)
+	for _, rate := range rates {
+		mapping[rate.Currency] = append(mapping[rate.Currency], rate)
+	}
+
+	c.rewardRatesMutex.Lock()
+	defer c.rewardRatesMutex.Unlock()
+	for currency := range c.rewardRatesMapping {
+		delete(c.rewardRatesMapping[currency], t)
+	}
+	for currency, rates := range mapping {
+		if c.rewardRatesMapping[currency] == nil {
+			c.rewardRatesMapping[currency] = make(map[string][]models.RewardRate)
+		}
+		c.rewardRatesMapping[currency][t] = rates
+	}
+}
+
+// GetRewardRatesByCountry returns reward rates of currency and country by type,
+// falls back to rates of country group and then global rates
+func (c *Cache) GetRewardRatesByCountry(currency, country, t string) []models.RewardRate {
+	c.rewardRatesMutex.RLock()
+	defer c.rewardRatesMutex.RUnlock()
+
+	scoped := c.scopedRewardRatesMapping[currency]
+	if rates := scoped[country][t]; len(rates) > 0 {
+		return rates
+	}
+
+	if group, ok := c.countryGroups[country]; ok {
+		if rates := scoped[group][t]; len(rates) > 0 {
+			return rates
+		}
+	}
+
+	return c.rewardRatesMapping[currency][t]
+}
+
+// SetScopedRewardRates replaces all reward rates scoped by country or country group
+func (c *Cache) SetScopedRewardRates(rates []models.RewardRate) {
+	mapping := make(map[string]map[string]map[string][]models.RewardRate)
+	for _, rate := range rates {
+		if mapping[rate.Currency] == nil {
+			mapping[rate.Currency] = make(map[string]map[string][]models.RewardRate)
+		}
+		if mapping[rate.Currency][rate.Scope] == nil {
+			mapping[rate.Currency][rate.Scope] = make(map[string][]models.RewardRate)
+		}
+		mapping[rate.Currency][rate.Scope][rate.Type] = append(mapping[rate.Currency][rate.Scope][rate.Type], rate)
+	}
+
+	c.rewardRatesMutex.Lock()
+	defer c.rewardRatesMutex.Unlock()
+	c.scopedRewardRatesMapping = mapping
+}
+
+// SetCountryGroups replaces all country groups
+func (c *Cache) SetCountryGroups(groups []models.CountryGroup) {
+	mapping := make(map[string]string)
+	for _, group := range groups {
+		mapping[group.Country] = group.Name
+	}
+
 	c.rewardRatesMutex.Lock()
 	defer c.rewardRatesMutex.Unlock()
-	c.rewardRatesMapping[t] = rates
+	c.countryGroups = mapping
+}
+
+// GetLevels returns levels
+func (c *Cache) GetLevels() models.Levels {
+	c.levelsMutex.RLock()
+	defer c.levelsMutex.RUnlock()
+	return c.levels
+}
+
+// SetLevels sets levels in cache
+func (c *Cache) SetLevels(levels models.Levels) {
+	c.levelsMutex.Lock()
+	defer c.levelsMutex.Unlock()
+	c.levels = levels
+}
+
+// GetStreakBonuses returns streak bonuses
+func (c *Cache) GetStreakBonuses() models.StreakBonuses {
+	c.streakBonusesMutex.RLock()
+	defer c.streakBonusesMutex.RUnlock()
+	return c.streakBonuses
+}
+
+// SetStreakBonuses sets streak bonuses in cache
+func (c *Cache) SetStreakBonuses(bonuses models.StreakBonuses) {
+	c.streakBonusesMutex.Lock()
+	defer c.streakBonusesMutex.Unlock()
+	c.streakBonuses = bonuses
+}
+
+// GetAchievements returns achievements
+func (c *Cache) GetAchievements() models.Achievements {
+	c.achievementsMutex.RLock()
+	defer c.achievementsMutex.RUnlock()
+	return c.achievements
+}
+
+// SetAchievements sets achievements in cache
+func (c *Cache) SetAchievements(achievements models.Achievements) {
+	c.achievementsMutex.Lock()
+	defer c.achievementsMutex.Unlock()
+	c.achievements = achievements
+}
+
+// GetRewardRules returns reward rules
+func (c *Cache) GetRewardRules() models.RewardRules {
+	c.rewardRulesMutex.RLock()
+	defer c.rewardRulesMutex.RUnlock()
+	return c.rewardRules
+}
+
+// SetRewardRules sets reward rules in cache
+func (c *Cache) SetRewardRules(rules models.RewardRules) {
+	c.rewardRulesMutex.Lock()
+	defer c.rewardRulesMutex.Unlock()
+	c.rewardRules = rules
+}
+
+// GetRolePermissions returns permissions granted to every role
+func (c *Cache) GetRolePermissions() models.RolePermissions {
+	c.rolePermissionsMutex.RLock()
+	defer c.rolePermissionsMutex.RUnlock()
+	return c.rolePermissions
+}
+
+// SetRolePermissions sets role permissions in cache
+func (c *Cache) SetRolePermissions(permissions models.RolePermissions) {
+	c.rolePermissionsMutex.Lock()
+	defer c.rolePermissionsMutex.Unlock()
+	c.rolePermissions = permissions
 }
 
 // GetLatestConfig returns latest system config
@@ -13,22 +13,77 @@ func TestMemory(t *testing.T) {
 
 	now := time.Now()
 
-	c.IncrementTotalReward(now, 1)
-	if v := c.GetLatestTotalReward(); v.Total != 1 {
+	c.IncrementTotalReward("btc", now, 1)
+	if v := c.GetLatestTotalReward("btc"); v.Total != 1 {
 		t.Errorf("total reward should be 1 but get %v", v)
 	}
 
-	c.IncrementTotalReward(now, 1)
-	if v := c.GetLatestTotalReward(); v.Total != 2 {
+	c.IncrementTotalReward("btc", now, 1)
+	if v := c.GetLatestTotalReward("btc"); v.Total != 2 {
 		t.Errorf("total reward should be 2 but get %v", v)
 	}
 
-	c.SetRewardRates(models.RewardRateTypeLess, []models.RewardRate{models.RewardRate{}})
-	rates := c.GetRewardRatesByType(models.RewardRateTypeLess)
+	if v := c.GetLatestTotalReward("doge"); v.Total != 0 {
+		t.Errorf("total reward of another currency should be 0 but get %v", v)
+	}
+
+	c.SetRewardRates(models.RewardRateTypeLess, []models.RewardRate{{Currency: "btc"}, {Currency: "doge"}, {Currency: "doge"}})
+	rates := c.GetRewardRatesByType("btc", models.RewardRateTypeLess)
 	if len(rates) != 1 {
 		t.Errorf("expected length of rates should be 1 but get %v", len(rates))
 	}
 
+	c.SetScopedRewardRates([]models.RewardRate{
+		{Currency: "btc", Type: models.RewardRateTypeLess, Scope: "US"},
+		{Currency: "btc", Type: models.RewardRateTypeLess, Scope: "US"},
+		{Currency: "btc", Type: models.RewardRateTypeLess, Scope: "tier1"},
+		{Currency: "btc", Type: models.RewardRateTypeLess, Scope: "tier1"},
+		{Currency: "btc", Type: models.RewardRateTypeLess, Scope: "tier1"},
+		{Currency: "doge", Type: models.RewardRateTypeLess, Scope: "CN"},
+	})
+	c.SetCountryGroups([]models.CountryGroup{{Name: "tier1", Country: "US"}, {Name: "tier1", Country: "GB"}})
+	for country, expected := range map[string]int{"US": 2, "GB": 3, "CN": 1, "": 1} {
+		if rates := c.GetRewardRatesByCountry("btc", country, models.RewardRateTypeLess); len(rates) != expected {
+			t.Errorf("expected length of rates of country %q should be %v but get %v", country, expected, len(rates))
+		}
+	}
+	if rates := c.GetRewardRatesByCountry("btc", "US", models.RewardRateTypeMore); len(rates) != 0 {
+		t.Errorf("expected length of rates should be 0 but get %v", len(rates))
+	}
+	if rates := c.GetRewardRatesByCountry("doge", "US", models.RewardRateTypeLess); len(rates) != 2 {
+		t.Errorf("expected length of rates of another currency should be 2 but get %v", len(rates))
+	}
+
+	c.SetRewardRates(models.RewardRateTypeLess, []models.RewardRate{{Currency: "btc"}})
+	if rates := c.GetRewardRatesByType("doge", models.RewardRateTypeLess); len(rates) != 0 {
+		t.Errorf("expected rates of currency no longer set should be removed but get %v", len(rates))
+	}
+
+	c.SetLevels(models.Levels{{Level: 1}, {Level: 2}})
+	if levels := c.GetLevels(); len(levels) != 2 {
+		t.Errorf("expected length of levels should be 2 but get %v", len(levels))
+	}
+
+	c.SetStreakBonuses(models.StreakBonuses{{Days: 2, Multiplier: 1.1}})
+	if bonuses := c.GetStreakBonuses(); len(bonuses) != 1 {
+		t.Errorf("expected length of streak bonuses should be 1 but get %v", len(bonuses))
+	}
+
+	c.SetAchievements(models.Achievements{{Name: "first_reward"}})
+	if achievements := c.GetAchievements(); len(achievements) != 1 {
+		t.Errorf("expected length of achievements should be 1 but get %v", len(achievements))
+	}
+
+	c.SetRewardRules(models.RewardRules{{Name: "happy hour"}})
+	if rules := c.GetRewardRules(); len(rules) != 1 {
+		t.Errorf("expected length of reward rules should be 1 but get %v", len(rules))
+	}
+
+	c.SetRolePermissions(models.RolePermissions{"support": {models.PermissionUsersRead}})
+	if !c.GetRolePermissions().Has("support", models.PermissionUsersRead) {
+		t.Error("expected support should have permission to read users")
+	}
+
 	c.SetLatestConfig(models.Config{TotalRewardThreshold: 1000})
 	config := c.GetLatestConfig()
 	if config.TotalRewardThreshold != 1000 {
@@ -0,0 +1,236 @@
+package bitcoind
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcrpcclient"
+	"github.com/solefaucet/sole-server/services/payout"
+)
+
+// number of wallet transactions looked up per page for batch
+const numTransactionsPerPage = 100
+
+// transactions are looked up from a while before batch is created in case clocks of database and wallet differ
+const findBatchTimeMargin = time.Hour
+
+// blocks fee is estimated for if conf target is not configured
+const defaultConfTarget = 6
+
+// size in bytes a withdrawal adds to batch transaction,
+// an output of 34 bytes and conservatively an input of 148 bytes to fund it
+const bytesPerOutput = 34 + 148
+
+// Payout implements payout.Payout interface with bitcoind compatible json-rpc,
+// works for forks like dogecoind, litecoind and dashd as well as long as conf target is not configured,
+// since conf_target of sendmany and estimatesmartfee are only available since bitcoin core 0.15
+type Payout struct {
+	client     *btcrpcclient.Client
+	confTarget int64
+}
+
+var _ payout.Payout = Payout{}
+
+// New returns a Payout connecting to wallet at host with credentials given,
+// fee is estimated and paid for transactions to be confirmed within confTarget blocks,
+// 0 means fee configured in wallet is paid
+func New(host, user, pass string, confTarget int64) (Payout, error) {
+	config := &btcrpcclient.ConnConfig{
+		Host:         host,
+		User:         user,
+		Pass:         pass,
+		HTTPPostMode: true, // Bitcoin core only supports HTTP POST mode
+		DisableTLS:   true, // Bitcoin core does not provide TLS by default
+	}
+	client, err := btcrpcclient.New(config, nil)
+	return Payout{client: client, confTarget: confTarget}, err
+}
+
+// ValidateAddress checks if address is valid
+func (p Payout) ValidateAddress(address string) (bool, error) {
+	result, err := p.client.ValidateAddress(address)
+	if err != nil {
+		return false, err
+	}
+
+	return result.IsValid, nil
+}
+
+// GetBalance returns balance of default account
+func (p Payout) GetBalance() (float64, error) {
+	balance, err := p.client.GetBalance("")
+	if err != nil {
+		return 0, err
+	}
+
+	return balance.ToBTC(), nil
+}
+
+// EstimateFeePerOutput estimates fee with fee rate from estimatesmartfee,
+// or estimatefee if node does not support the former
+func (p Payout) EstimateFeePerOutput() (float64, error) {
+	confTarget := p.confTarget
+	if confTarget == 0 {
+		confTarget = defaultConfTarget
+	}
+	rawConfTarget, _ := json.Marshal(confTarget)
+
+	raw, err := p.client.RawRequest("estimatesmartfee", []json.RawMessage{rawConfTarget})
+	if e, ok := rpcErrorOf(err); ok && e.Code == btcjson.ErrRPCMethodNotFound.Code {
+		return p.estimateFeePerOutputWithEstimateFee(rawConfTarget)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// fee rate is in coin per kB, it is absent if node does not have enough data
+	result := struct {
+		FeeRate *float64 `json:"feerate"`
+		Errors  []string `json:"errors"`
+	}{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return 0, err
+	}
+	if result.FeeRate == nil || *result.FeeRate <= 0 {
+		return 0, fmt.Errorf("no fee estimate: %v", result.Errors)
+	}
+
+	return *result.FeeRate * bytesPerOutput / 1000, nil
+}
+
+// fee rate from estimatefee is in coin per kB, -1 if node does not have enough data
+func (p Payout) estimateFeePerOutputWithEstimateFee(confTarget json.RawMessage) (float64, error) {
+	raw, err := p.client.RawRequest("estimatefee", []json.RawMessage{confTarget})
+	if err != nil {
+		return 0, err
+	}
+
+	var feeRate float64
+	if err := json.Unmarshal(raw, &feeRate); err != nil {
+		return 0, err
+	}
+	if feeRate <= 0 {
+		return 0, fmt.Errorf("no fee estimate")
+	}
+
+	return feeRate * bytesPerOutput / 1000, nil
+}
+
+// GetReceiveAddress returns address of default account to top up wallet
+func (p Payout) GetReceiveAddress() (string, error) {
+	return p.client.GetAccountAddress("")
+}
+
+// SendBatch sends amounts to addresses in one transaction,
+// wallet pays fee rate estimated for confTarget as EstimateFeePerOutput does if it is configured
+func (p Payout) SendBatch(amounts map[string]float64, comment string, sent payout.SentFunc) error {
+	// sendmany "" amounts minconf comment [subtractfeefrom replaceable conf_target]
+	params := []interface{}{"", amounts, 1, comment}
+	if p.confTarget > 0 {
+		params = append(params, []string{}, false, p.confTarget)
+	}
+	rawParams := make([]json.RawMessage, len(params))
+	for i, v := range params {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		rawParams[i] = raw
+	}
+
+	addresses := make([]string, 0, len(amounts))
+	for address := range amounts {
+		addresses = append(addresses, address)
+	}
+
+	// nothing is sent if wallet replies with an error, e.g. invalid address or insufficient funds,
+	// outcome of transport errors is unknown
+	raw, err := p.client.RawRequest("sendmany", rawParams)
+	if e, ok := rpcErrorOf(err); ok {
+		return &payout.UnsentError{Addresses: addresses, InvalidAddress: e.Code == btcjson.ErrRPCInvalidAddressOrKey, Err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	var hash string
+	if err := json.Unmarshal(raw, &hash); err != nil {
+		return err
+	}
+	return sent(hash, addresses)
+}
+
+// GetTransactionStatus returns confirmations, block time and fee of transaction sent by wallet
+func (p Payout) GetTransactionStatus(transactionID string) (payout.TransactionStatus, error) {
+	hash, err := wire.NewShaHashFromStr(transactionID)
+	if err != nil {
+		return payout.TransactionStatus{}, err
+	}
+
+	result, err := p.client.GetTransaction(hash)
+	if err != nil {
+		return payout.TransactionStatus{}, err
+	}
+
+	status := payout.TransactionStatus{
+		TransactionID: result.TxID,
+		Confirmations: result.Confirmations,
+		Fee:           math.Abs(result.Fee), // fee of sent transaction is negative
+	}
+	if result.BlockTime > 0 {
+		blockTime := time.Unix(result.BlockTime, 0)
+		status.BlockTime = &blockTime
+	}
+	return status, nil
+}
+
+// FindBatch pages through transactions of wallet from the latest back to since
+// for those sent with comment containing batchID
+func (p Payout) FindBatch(batchID string, since time.Time) (map[string]string, error) {
+	since = since.Add(-findBatchTimeMargin)
+
+	transactionIDs := map[string]string{}
+	for from := 0; ; from += numTransactionsPerPage {
+		transactions, err := p.client.ListTransactionsCountFrom("*", numTransactionsPerPage, from)
+		if err != nil {
+			return nil, err
+		}
+
+		reachedSince := false
+		for _, v := range transactions {
+			if v.Category == "send" && strings.Contains(v.Comment, batchID) {
+				transactionIDs[v.Address] = v.TxID
+			}
+			reachedSince = reachedSince || time.Unix(v.Time, 0).Before(since)
+		}
+
+		// page is the oldest of wallet or contains transactions before since
+		if len(transactions) < numTransactionsPerPage || reachedSince {
+			return transactionIDs, nil
+		}
+	}
+}
+
+// json-rpc error replied by wallet, client reports it as plain error with reply as message
+// if wallet replies with http status other than 2xx as bitcoind does
+func rpcErrorOf(err error) (*btcjson.RPCError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if e, ok := err.(*btcjson.RPCError); ok {
+		return e, true
+	}
+
+	reply := struct {
+		Error *btcjson.RPCError `json:"error"`
+	}{}
+	if json.Unmarshal([]byte(err.Error()), &reply) != nil || reply.Error == nil {
+		return nil, false
+	}
+	return reply.Error, true
+}
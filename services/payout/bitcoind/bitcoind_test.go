@@ -0,0 +1,147 @@
+package bitcoind
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/solefaucet/sole-server/services/payout"
+)
+
+// stand-in of bitcoind serving json-rpc methods used by Payout,
+// errors are replied with http status 500 as bitcoind does
+type mockNode struct {
+	params map[string][]json.RawMessage
+	errors map[string]*btcjson.RPCError
+}
+
+func (n *mockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := struct {
+		ID     int64             `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}{}
+	json.NewDecoder(r.Body).Decode(&req)
+	n.params[req.Method] = req.Params
+
+	var result interface{}
+	switch req.Method {
+	case "sendmany":
+		result = "txid"
+	case "estimatesmartfee":
+		result = map[string]float64{"feerate": 0.001}
+	case "estimatefee":
+		result = 0.002
+	}
+
+	resp := map[string]interface{}{"id": req.ID, "result": result, "error": nil}
+	if e, ok := n.errors[req.Method]; ok {
+		resp["result"], resp["error"] = nil, e
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newTestPayout(t *testing.T, node *mockNode, confTarget int64) (Payout, func()) {
+	node.params = map[string][]json.RawMessage{}
+	server := httptest.NewServer(node)
+	p, err := New(strings.TrimPrefix(server.URL, "http://"), "user", "pass", confTarget)
+	if err != nil {
+		t.Fatalf("new payout error: %v", err)
+	}
+	return p, server.Close
+}
+
+func ignoreSent(string, []string) error { return nil }
+
+func TestSendBatch(t *testing.T) {
+	p, done := newTestPayout(t, &mockNode{}, 6)
+	defer done()
+
+	var sentID string
+	err := p.SendBatch(map[string]float64{"a": 1}, "comment", func(transactionID string, addresses []string) error {
+		sentID = transactionID
+		return nil
+	})
+	if err != nil || sentID != "txid" {
+		t.Errorf("batch should be sent as txid but get %v, %v", sentID, err)
+	}
+}
+
+func TestSendBatchRejected(t *testing.T) {
+	testdata := []struct {
+		when           string
+		err            *btcjson.RPCError
+		invalidAddress bool
+	}{
+		{"insufficient funds", &btcjson.RPCError{Code: btcjson.ErrRPCWalletInsufficientFunds, Message: "Insufficient funds"}, false},
+		{"wallet locked", &btcjson.RPCError{Code: btcjson.ErrRPCWalletUnlockNeeded, Message: "Please enter the wallet passphrase"}, false},
+		{"invalid address", &btcjson.RPCError{Code: btcjson.ErrRPCInvalidAddressOrKey, Message: "Invalid address"}, true},
+	}
+
+	for _, v := range testdata {
+		p, done := newTestPayout(t, &mockNode{errors: map[string]*btcjson.RPCError{"sendmany": v.err}}, 6)
+
+		err := p.SendBatch(map[string]float64{"a": 1, "b": 2}, "comment", ignoreSent)
+		e, ok := err.(*payout.UnsentError)
+		if !ok {
+			t.Errorf("%v: error should be unsent error but get %v", v.when, err)
+		} else if len(e.Addresses) != 2 || e.InvalidAddress != v.invalidAddress {
+			t.Errorf("%v: unsent error should carry 2 addresses and invalid address %v but get %+v", v.when, v.invalidAddress, e)
+		}
+
+		done()
+	}
+}
+
+func TestSendBatchUnknownOutcome(t *testing.T) {
+	p, done := newTestPayout(t, &mockNode{}, 6)
+	done()
+
+	err := p.SendBatch(map[string]float64{"a": 1}, "comment", ignoreSent)
+	if _, ok := err.(*payout.UnsentError); ok || err == nil {
+		t.Errorf("error of unreachable wallet should be of unknown outcome but get %v", err)
+	}
+}
+
+func TestSendBatchConfTarget(t *testing.T) {
+	for confTarget, numParams := range map[int64]int{0: 4, 6: 7} {
+		node := &mockNode{}
+		p, done := newTestPayout(t, node, confTarget)
+
+		p.SendBatch(map[string]float64{"a": 1}, "comment", ignoreSent)
+		if len(node.params["sendmany"]) != numParams {
+			t.Errorf("sendmany should be called with %v params with conf target %v but get %s", numParams, confTarget, node.params["sendmany"])
+		}
+
+		done()
+	}
+}
+
+func TestEstimateFeePerOutput(t *testing.T) {
+	p, done := newTestPayout(t, &mockNode{}, 6)
+	defer done()
+
+	// 182 bytes at 0.001 per kB
+	if fee, err := p.EstimateFeePerOutput(); err != nil || fee != 0.000182 {
+		t.Errorf("fee should be 0.000182 but get %v, %v", fee, err)
+	}
+}
+
+func TestEstimateFeePerOutputWithEstimateFee(t *testing.T) {
+	node := &mockNode{errors: map[string]*btcjson.RPCError{"estimatesmartfee": btcjson.ErrRPCMethodNotFound}}
+	p, done := newTestPayout(t, node, 0)
+	defer done()
+
+	// 182 bytes at 0.002 per kB for default conf target
+	fee, err := p.EstimateFeePerOutput()
+	if err != nil || fee != 0.000364 {
+		t.Errorf("fee should be 0.000364 but get %v, %v", fee, err)
+	}
+	if string(node.params["estimatefee"][0]) != "6" {
+		t.Errorf("fee should be estimated for 6 blocks but get %s", node.params["estimatefee"])
+	}
+}
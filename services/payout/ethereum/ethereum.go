@@ -0,0 +1,245 @@
+package ethereum
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/services/addressvalidator"
+	"github.com/solefaucet/sole-server/services/payout"
+)
+
+const gasLimit = 21000 // gas of plain ether transfer
+
+var (
+	weiPerEther = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	weiPerGwei  = big.NewInt(1e9)
+)
+
+// Payout implements payout.Payout interface with ethereum json-rpc,
+// transactions are signed by node with from account unlocked,
+// one transaction is sent per address since there is no sendmany in ethereum
+type Payout struct {
+	rpc      *rpcClient
+	from     string
+	gasPrice *big.Int // nil means gas price suggested by node
+
+	// nonces are assigned locally within a batch, serialize batches to avoid reusing them
+	nonceMutex sync.Mutex
+}
+
+var _ payout.Payout = &Payout{}
+
+// New returns a Payout sending ether from account via node at url,
+// gas price is in gwei, 0 means using gas price suggested by node
+func New(url, from string, gasPrice int64) (*Payout, error) {
+	if !addressvalidator.IsValidEthereumAddress(from) {
+		return nil, fmt.Errorf("invalid ethereum address %v", from)
+	}
+
+	p := &Payout{
+		rpc:  &rpcClient{url: url, client: &http.Client{Timeout: 30 * time.Second}},
+		from: strings.ToLower(from),
+	}
+	if gasPrice > 0 {
+		p.gasPrice = new(big.Int).Mul(big.NewInt(gasPrice), weiPerGwei)
+	}
+	return p, nil
+}
+
+// ValidateAddress checks format and EIP-55 checksum of address offline
+func (p *Payout) ValidateAddress(address string) (bool, error) {
+	return addressvalidator.IsValidEthereumAddress(address), nil
+}
+
+// GetBalance returns balance of from account in ether
+func (p *Payout) GetBalance() (float64, error) {
+	wei, err := p.quantity("eth_getBalance", p.from, "latest")
+	if err != nil {
+		return 0, err
+	}
+
+	return weiToEther(wei), nil
+}
+
+// EstimateFeePerOutput returns fee of a plain transfer with gas price configured or suggested
+func (p *Payout) EstimateFeePerOutput() (float64, error) {
+	gasPrice, err := p.getGasPrice()
+	if err != nil {
+		return 0, err
+	}
+
+	return weiToEther(new(big.Int).Mul(gasPrice, big.NewInt(gasLimit))), nil
+}
+
+// GetReceiveAddress returns from account to top up
+func (p *Payout) GetReceiveAddress() (string, error) {
+	return addressvalidator.ChecksumEthereumAddress(p.from), nil
+}
+
+// SendBatch sends one transaction per address with consecutive nonces,
+// comment is ignored since data of transaction costs gas
+func (p *Payout) SendBatch(amounts map[string]float64, comment string, sent payout.SentFunc) error {
+	p.nonceMutex.Lock()
+	defer p.nonceMutex.Unlock()
+
+	// send in order of address so that nonces are assigned deterministically
+	addresses := make([]string, 0, len(amounts))
+	for address := range amounts {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	nonce, err := p.quantity("eth_getTransactionCount", p.from, "pending")
+	if err != nil {
+		return &payout.UnsentError{Addresses: addresses, Err: err}
+	}
+
+	gasPrice, err := p.getGasPrice()
+	if err != nil {
+		return &payout.UnsentError{Addresses: addresses, Err: err}
+	}
+
+	for i, address := range addresses {
+		tx := map[string]string{
+			"from":     p.from,
+			"to":       address,
+			"value":    encodeQuantity(etherToWei(amounts[address])),
+			"gas":      encodeQuantity(big.NewInt(gasLimit)),
+			"gasPrice": encodeQuantity(gasPrice),
+			"nonce":    encodeQuantity(nonce),
+		}
+
+		var hash string
+		if err := p.rpc.call(&hash, "eth_sendTransaction", tx); err != nil {
+			// transaction rejected by node is not sent, it may be sent if node does not respond
+			unsent := addresses[i+1:]
+			if _, ok := err.(*rpcError); ok {
+				unsent = addresses[i:]
+			}
+			return &payout.UnsentError{
+				Addresses: unsent,
+				Err:       fmt.Errorf("send %v to %v error: %v", amounts[address], address, err),
+			}
+		}
+
+		if err := sent(hash, []string{address}); err != nil {
+			return &payout.UnsentError{Addresses: addresses[i+1:], Err: err}
+		}
+		nonce = new(big.Int).Add(nonce, big.NewInt(1))
+	}
+
+	return nil
+}
+
+// GetTransactionStatus returns confirmations, block time and fee of transaction,
+// confirmations is 0 until transaction is mined
+func (p *Payout) GetTransactionStatus(transactionID string) (payout.TransactionStatus, error) {
+	status := payout.TransactionStatus{TransactionID: transactionID}
+
+	var receipt *struct {
+		BlockNumber string `json:"blockNumber"`
+		GasUsed     string `json:"gasUsed"`
+	}
+	if err := p.rpc.call(&receipt, "eth_getTransactionReceipt", transactionID); err != nil {
+		return status, err
+	}
+	if receipt == nil {
+		return status, nil
+	}
+
+	var tx struct {
+		GasPrice string `json:"gasPrice"`
+	}
+	if err := p.rpc.call(&tx, "eth_getTransactionByHash", transactionID); err != nil {
+		return status, err
+	}
+
+	latest, err := p.quantity("eth_blockNumber")
+	if err != nil {
+		return status, err
+	}
+
+	blockNumber, err := decodeQuantity(receipt.BlockNumber)
+	if err != nil {
+		return status, err
+	}
+	gasUsed, err := decodeQuantity(receipt.GasUsed)
+	if err != nil {
+		return status, err
+	}
+	gasPrice, err := decodeQuantity(tx.GasPrice)
+	if err != nil {
+		return status, err
+	}
+
+	var block struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := p.rpc.call(&block, "eth_getBlockByNumber", receipt.BlockNumber, false); err != nil {
+		return status, err
+	}
+	timestamp, err := decodeQuantity(block.Timestamp)
+	if err != nil {
+		return status, err
+	}
+
+	blockTime := time.Unix(timestamp.Int64(), 0)
+	status.BlockTime = &blockTime
+	status.Confirmations = new(big.Int).Sub(latest, blockNumber).Int64() + 1
+	status.Fee = weiToEther(new(big.Int).Mul(gasUsed, gasPrice))
+	return status, nil
+}
+
+// FindBatch is not supported since comment is not attached to transactions,
+// unfinished batches need to be checked against transactions of from account manually
+func (p *Payout) FindBatch(batchID string, since time.Time) (map[string]string, error) {
+	return nil, errors.ErrFindBatchNotSupported
+}
+
+func (p *Payout) getGasPrice() (*big.Int, error) {
+	if p.gasPrice != nil {
+		return p.gasPrice, nil
+	}
+	return p.quantity("eth_gasPrice")
+}
+
+// call method returning a quantity
+func (p *Payout) quantity(method string, params ...interface{}) (*big.Int, error) {
+	var result string
+	if err := p.rpc.call(&result, method, params...); err != nil {
+		return nil, err
+	}
+
+	return decodeQuantity(result)
+}
+
+// quantities are hex encoded with 0x prefix in ethereum json-rpc
+func decodeQuantity(s string) (*big.Int, error) {
+	i, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok || !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("invalid quantity %q", s)
+	}
+	return i, nil
+}
+
+func encodeQuantity(i *big.Int) string {
+	return "0x" + i.Text(16)
+}
+
+// amount is rounded to 8 decimals as balances are stored in DECIMAL(19, 8)
+func etherToWei(ether float64) *big.Int {
+	units := big.NewInt(int64(math.Floor(ether*1e8 + 0.5)))
+	return units.Mul(units, big.NewInt(1e10))
+}
+
+func weiToEther(wei *big.Int) float64 {
+	ether, _ := new(big.Rat).SetFrac(wei, weiPerEther).Float64()
+	return ether
+}
@@ -0,0 +1,204 @@
+package ethereum
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/services/payout"
+)
+
+const testFrom = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+// stand-in of ethereum node serving json-rpc methods used by Payout
+type mockNode struct {
+	nonce   int64
+	blocks  int64
+	sent    []map[string]string
+	failOn  map[string]bool
+	pending bool
+}
+
+func (n *mockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := struct {
+		ID     int64             `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}{}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	var result interface{}
+	switch req.Method {
+	case "eth_getBalance":
+		result = "0x1bc16d674ec80000" // 2 ether
+	case "eth_getTransactionCount":
+		result = fmt.Sprintf("0x%x", n.nonce)
+	case "eth_gasPrice":
+		result = "0x4a817c800" // 20 gwei
+	case "eth_blockNumber":
+		result = fmt.Sprintf("0x%x", n.blocks)
+	case "eth_sendTransaction":
+		tx := map[string]string{}
+		json.Unmarshal(req.Params[0], &tx)
+		if n.failOn[tx["to"]] {
+			break
+		}
+		n.sent = append(n.sent, tx)
+		n.nonce++
+		result = fmt.Sprintf("0xhash%d", len(n.sent))
+	case "eth_getTransactionReceipt":
+		if !n.pending {
+			result = map[string]string{"blockNumber": "0xa", "gasUsed": "0x5208"}
+		}
+	case "eth_getBlockByNumber":
+		result = map[string]string{"timestamp": "0x58203680"}
+	case "eth_getTransactionByHash":
+		result = map[string]string{"gasPrice": "0x4a817c800"}
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result}
+	if n.failOn[req.Method] || (req.Method == "eth_sendTransaction" && result == nil) {
+		resp["error"] = map[string]interface{}{"code": -32000, "message": "failed"}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newTestPayout(t *testing.T, node *mockNode, gasPrice int64) (*Payout, func()) {
+	server := httptest.NewServer(node)
+	p, err := New(server.URL, testFrom, gasPrice)
+	if err != nil {
+		t.Fatalf("new payout error: %v", err)
+	}
+	return p, server.Close
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("", "0x123", 0); err == nil {
+		t.Error("error should not be nil with invalid from address")
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	p, done := newTestPayout(t, &mockNode{}, 0)
+	defer done()
+
+	if balance, err := p.GetBalance(); err != nil || balance != 2 {
+		t.Errorf("balance should be 2 but get %v, %v", balance, err)
+	}
+
+	if address, _ := p.GetReceiveAddress(); address != testFrom {
+		t.Errorf("receive address should be %v but get %v", testFrom, address)
+	}
+}
+
+func TestEstimateFeePerOutput(t *testing.T) {
+	p, done := newTestPayout(t, &mockNode{}, 0)
+	defer done()
+
+	// 21000 gas at 20 gwei suggested by node
+	if fee, err := p.EstimateFeePerOutput(); err != nil || fee != 0.00042 {
+		t.Errorf("fee should be 0.00042 but get %v, %v", fee, err)
+	}
+}
+
+// records transaction id of each address reported sent
+func recordSent(txIDs map[string]string) payout.SentFunc {
+	return func(transactionID string, addresses []string) error {
+		for _, address := range addresses {
+			txIDs[address] = transactionID
+		}
+		return nil
+	}
+}
+
+func TestSendBatch(t *testing.T) {
+	node := &mockNode{nonce: 7}
+	p, done := newTestPayout(t, node, 0)
+	defer done()
+
+	txIDs := map[string]string{}
+	err := p.SendBatch(map[string]float64{"0xbb": 0.5, "0xaa": 0.00000001}, "", recordSent(txIDs))
+	if err != nil {
+		t.Errorf("error should be nil but get %v", err)
+	}
+	if !reflect.DeepEqual(txIDs, map[string]string{"0xaa": "0xhash1", "0xbb": "0xhash2"}) {
+		t.Errorf("one transaction should be sent per address but get %v", txIDs)
+	}
+
+	expected := []map[string]string{
+		{"from": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "to": "0xaa", "value": "0x2540be400", "gas": "0x5208", "gasPrice": "0x4a817c800", "nonce": "0x7"},
+		{"from": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "to": "0xbb", "value": "0x6f05b59d3b20000", "gas": "0x5208", "gasPrice": "0x4a817c800", "nonce": "0x8"},
+	}
+	if !reflect.DeepEqual(node.sent, expected) {
+		t.Errorf("transactions should be %v but get %v", expected, node.sent)
+	}
+}
+
+func TestSendBatchWithConfiguredGasPrice(t *testing.T) {
+	node := &mockNode{failOn: map[string]bool{"eth_gasPrice": true}}
+	p, done := newTestPayout(t, node, 1)
+	defer done()
+
+	if err := p.SendBatch(map[string]float64{"0xaa": 1}, "", recordSent(map[string]string{})); err != nil {
+		t.Errorf("gas price should not be queried but get %v", err)
+	}
+	if len(node.sent) != 1 || node.sent[0]["gasPrice"] != "0x3b9aca00" {
+		t.Errorf("gas price should be 1 gwei but get %v", node.sent)
+	}
+}
+
+func TestSendBatchPartially(t *testing.T) {
+	node := &mockNode{failOn: map[string]bool{"0xbb": true}}
+	p, done := newTestPayout(t, node, 0)
+	defer done()
+
+	txIDs := map[string]string{}
+	err := p.SendBatch(map[string]float64{"0xaa": 1, "0xbb": 1, "0xcc": 1}, "", recordSent(txIDs))
+	if !reflect.DeepEqual(txIDs, map[string]string{"0xaa": "0xhash1"}) {
+		t.Errorf("transactions sent before error should be recorded but get %v", txIDs)
+	}
+
+	// 0xbb is rejected by node, neither it nor 0xcc after it is sent
+	e, ok := err.(*payout.UnsentError)
+	if !ok || !reflect.DeepEqual(e.Addresses, []string{"0xbb", "0xcc"}) {
+		t.Errorf("0xbb, 0xcc should be unsent but get %v", err)
+	}
+}
+
+func TestGetTransactionStatus(t *testing.T) {
+	node := &mockNode{blocks: 12}
+	p, done := newTestPayout(t, node, 0)
+	defer done()
+
+	status, err := p.GetTransactionStatus("0xhash")
+	if err != nil || status.Confirmations != 3 || status.Fee != 0.00042 {
+		t.Errorf("status should have 3 confirmations and 0.00042 fee but get %v, %v", status, err)
+	}
+	if status.BlockTime == nil || status.BlockTime.Unix() != 0x58203680 {
+		t.Errorf("block time should be %v but get %v", time.Unix(0x58203680, 0), status.BlockTime)
+	}
+
+	node.pending = true
+	if status, err := p.GetTransactionStatus("0xhash"); err != nil || status.Confirmations != 0 || status.BlockTime != nil {
+		t.Errorf("pending transaction should have no confirmations but get %v, %v", status, err)
+	}
+
+	node.failOn = map[string]bool{"eth_getTransactionReceipt": true}
+	if _, err := p.GetTransactionStatus("0xhash"); err == nil {
+		t.Error("error should not be nil")
+	}
+}
+
+func TestFindBatch(t *testing.T) {
+	p, done := newTestPayout(t, &mockNode{}, 0)
+	defer done()
+
+	if _, err := p.FindBatch("batch", time.Now()); err != errors.ErrFindBatchNotSupported {
+		t.Errorf("error should be ErrFindBatchNotSupported as comment is not attached to transactions but get %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/services/payout"
+)
+
+// Payout implements payout.Payout interface in memory, for testing only
+type Payout struct {
+	address      string
+	balance      float64
+	feePerOutput float64
+	batches      []map[string]float64
+	comments     []string
+	status       map[string]payout.TransactionStatus
+	err          error
+	mutex        sync.RWMutex
+}
+
+var _ payout.Payout = &Payout{}
+
+// New creates a fake wallet with balance and receive address
+func New(balance float64, address string) *Payout {
+	return &Payout{
+		address: address,
+		balance: balance,
+		status:  make(map[string]payout.TransactionStatus),
+	}
+}
+
+// SetError makes all following calls fail with err, nil recovers them
+func (p *Payout) SetError(err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.err = err
+}
+
+// SetBalance sets balance as if wallet is topped up or drained
+func (p *Payout) SetBalance(balance float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.balance = balance
+}
+
+// SetFeePerOutput sets fee estimated and charged per address of batch
+func (p *Payout) SetFeePerOutput(fee float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.feePerOutput = fee
+}
+
+// SetConfirmations sets confirmations of sent transaction, and block time if it is mined
+func (p *Payout) SetConfirmations(transactionID string, confirmations int64, blockTime *time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	status := p.status[transactionID]
+	status.TransactionID = transactionID
+	status.Confirmations = confirmations
+	status.BlockTime = blockTime
+	p.status[transactionID] = status
+}
+
+// Batches returns amounts of batches sent so far
+func (p *Payout) Batches() []map[string]float64 {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.batches
+}
+
+// ValidateAddress treats any non-empty address without whitespace as valid
+func (p *Payout) ValidateAddress(address string) (bool, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return address != "" && !strings.ContainsAny(address, " \t\r\n"), p.err
+}
+
+// GetBalance returns current balance
+func (p *Payout) GetBalance() (float64, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.balance, p.err
+}
+
+// EstimateFeePerOutput returns fee set by SetFeePerOutput
+func (p *Payout) EstimateFeePerOutput() (float64, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.feePerOutput, p.err
+}
+
+// GetReceiveAddress returns address given on creation
+func (p *Payout) GetReceiveAddress() (string, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.address, p.err
+}
+
+// SendBatch deducts total of amounts and fees from balance and records the batch as one transaction
+func (p *Payout) SendBatch(amounts map[string]float64, comment string, sent payout.SentFunc) error {
+	transactionID, err := p.sendBatch(amounts, comment)
+	if err != nil {
+		return err
+	}
+
+	addresses := make([]string, 0, len(amounts))
+	for address := range amounts {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return sent(transactionID, addresses)
+}
+
+func (p *Payout) sendBatch(amounts map[string]float64, comment string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.err != nil {
+		return "", p.err
+	}
+
+	total := p.feePerOutput * float64(len(amounts))
+	for _, amount := range amounts {
+		total += amount
+	}
+	if total > p.balance {
+		return "", errors.ErrInsufficientBalance
+	}
+
+	p.balance -= total
+	p.batches = append(p.batches, amounts)
+	p.comments = append(p.comments, comment)
+	transactionID := fmt.Sprintf("tx%d", len(p.batches))
+	p.status[transactionID] = payout.TransactionStatus{
+		TransactionID: transactionID,
+		Fee:           p.feePerOutput * float64(len(amounts)),
+	}
+	return transactionID, nil
+}
+
+// GetTransactionStatus returns status of transaction sent by SendBatch
+func (p *Payout) GetTransactionStatus(transactionID string) (payout.TransactionStatus, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.err != nil {
+		return payout.TransactionStatus{}, p.err
+	}
+
+	status, ok := p.status[transactionID]
+	if !ok {
+		return status, errors.ErrNotFound
+	}
+	return status, nil
+}
+
+// FindBatch returns transaction ids of batches sent with comment containing batchID, since is ignored
+func (p *Payout) FindBatch(batchID string, since time.Time) (map[string]string, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	transactionIDs := map[string]string{}
+	for i, comment := range p.comments {
+		if !strings.Contains(comment, batchID) {
+			continue
+		}
+		for address := range p.batches[i] {
+			transactionIDs[address] = fmt.Sprintf("tx%d", i+1)
+		}
+	}
+	return transactionIDs, nil
+}
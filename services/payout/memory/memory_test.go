@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPayout(t *testing.T) {
+	p := New(10, "wallet")
+
+	if address, _ := p.GetReceiveAddress(); address != "wallet" {
+		t.Errorf("receive address should be wallet but get %v", address)
+	}
+
+	if valid, _ := p.ValidateAddress("a b"); valid {
+		t.Error("address with whitespace should be invalid")
+	}
+
+	txIDs := map[string]string{}
+	sent := func(transactionID string, addresses []string) error {
+		for _, address := range addresses {
+			txIDs[address] = transactionID
+		}
+		return nil
+	}
+
+	if err := p.SendBatch(map[string]float64{"a": 11}, "", sent); err == nil || len(txIDs) != 0 {
+		t.Error("sending more than balance should fail")
+	}
+
+	err := p.SendBatch(map[string]float64{"a": 3, "b": 4}, "", sent)
+	if err != nil || len(txIDs) != 2 || txIDs["a"] != txIDs["b"] {
+		t.Errorf("batch should be sent in one transaction but get %v, %v", txIDs, err)
+	}
+	txID := txIDs["a"]
+	if balance, _ := p.GetBalance(); balance != 3 {
+		t.Errorf("balance should be 3 but get %v", balance)
+	}
+
+	blockTime := time.Unix(1478505600, 0)
+	p.SetConfirmations(txID, 6, &blockTime)
+	if status, _ := p.GetTransactionStatus(txID); status.Confirmations != 6 || !status.BlockTime.Equal(blockTime) {
+		t.Errorf("confirmations should be 6 mined at %v but get %v", blockTime, status)
+	}
+	if _, err := p.GetTransactionStatus("unknown"); err == nil {
+		t.Error("status of unknown transaction should be not found")
+	}
+
+	p.SendBatch(map[string]float64{"c": 1}, "payout batch1", sent)
+	if found, err := p.FindBatch("batch1", time.Now()); err != nil || found["c"] != txIDs["c"] || len(found) != 1 {
+		t.Errorf("batch1 should be found in %v but get %v, %v", txIDs, found, err)
+	}
+
+	p.SetError(errors.New("down"))
+	if _, err := p.GetBalance(); err == nil {
+		t.Error("error should be returned after SetError")
+	}
+}
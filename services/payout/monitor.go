@@ -0,0 +1,182 @@
+package payout
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// daily payout is averaged over withdrawals sent within the window
+const payoutWindow = 7 * 24 * time.Hour
+
+// dependencies of monitor
+type (
+	dependencyGetLiabilities     func() (float64, error)
+	dependencyGetWithdrawalStats func() ([]models.WithdrawalStats, error)
+	dependencyGetPaidOutAmount   func(since time.Time) (float64, error)
+	dependencySendEmail          func(recipients []string, subject string, html string) error
+)
+
+// Monitor compares hot wallet balance with coins owed to users,
+// and alerts operators by email when balance is running out
+type Monitor struct {
+	payout             Payout
+	minRunwayDays      float64
+	alertInterval      time.Duration
+	recipients         []string
+	tmpl               *template.Template
+	appname            string
+	currency           string
+	getLiabilities     dependencyGetLiabilities
+	getWithdrawalStats dependencyGetWithdrawalStats
+	getPaidOutAmount   dependencyGetPaidOutAmount
+	sendEmail          dependencySendEmail
+
+	mutex     sync.Mutex
+	alertedAt time.Time
+}
+
+// NewMonitor creates a monitor alerting recipients at most once per alertInterval
+// while balance lasts less than minRunwayDays
+func NewMonitor(
+	payout Payout,
+	minRunwayDays float64,
+	alertInterval time.Duration,
+	recipients []string,
+	tmpl *template.Template,
+	appname string,
+	currency string,
+	getLiabilities dependencyGetLiabilities,
+	getWithdrawalStats dependencyGetWithdrawalStats,
+	getPaidOutAmount dependencyGetPaidOutAmount,
+	sendEmail dependencySendEmail,
+) *Monitor {
+	return &Monitor{
+		payout:             payout,
+		minRunwayDays:      minRunwayDays,
+		alertInterval:      alertInterval,
+		recipients:         recipients,
+		tmpl:               tmpl,
+		appname:            appname,
+		currency:           currency,
+		getLiabilities:     getLiabilities,
+		getWithdrawalStats: getWithdrawalStats,
+		getPaidOutAmount:   getPaidOutAmount,
+		sendEmail:          sendEmail,
+	}
+}
+
+// Runway estimates how long wallet balance lasts
+func (m *Monitor) Runway() (models.WalletRunway, error) {
+	now := time.Now()
+
+	balance, err := m.payout.GetBalance()
+	if err != nil {
+		return models.WalletRunway{}, err
+	}
+
+	liabilities, err := m.getLiabilities()
+	if err != nil {
+		return models.WalletRunway{}, err
+	}
+
+	withdrawalStats, err := m.getWithdrawalStats()
+	if err != nil {
+		return models.WalletRunway{}, err
+	}
+
+	// coins of withdrawals not sent yet are still owed
+	pendingWithdrawals := 0.0
+	for _, v := range withdrawalStats {
+		switch v.Status {
+		case models.WithdrawalStatusPending, models.WithdrawalStatusProcessing, models.WithdrawalStatusReview:
+			pendingWithdrawals += v.Amount
+		}
+	}
+
+	paidOut, err := m.getPaidOutAmount(now.Add(-payoutWindow))
+	if err != nil {
+		return models.WalletRunway{}, err
+	}
+	dailyPayout := paidOut / payoutWindow.Hours() * 24
+
+	return models.NewWalletRunway(balance, pendingWithdrawals, liabilities, dailyPayout), nil
+}
+
+// Check logs runway of wallet and alerts operators if it is below threshold
+func (m *Monitor) Check() {
+	runway, err := m.Runway()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":    models.EventMonitorWallet,
+			"currency": m.currency,
+			"error":    err.Error(),
+		}).Error("failed to estimate wallet runway")
+		return
+	}
+
+	fields := logrus.Fields{
+		"event":               models.EventMonitorWallet,
+		"currency":            m.currency,
+		"balance":             runway.Balance,
+		"pending_withdrawals": runway.PendingWithdrawals,
+		"liabilities":         runway.Liabilities,
+		"daily_payout":        runway.DailyPayout,
+		"runway_days":         runway.RunwayDays,
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !runway.Below(m.minRunwayDays) {
+		// alert right away next time balance is running out
+		m.alertedAt = time.Time{}
+		logrus.WithFields(fields).Info("wallet runway")
+		return
+	}
+
+	logrus.WithFields(fields).Warn("wallet is running out of coins")
+	if len(m.recipients) == 0 || time.Since(m.alertedAt) < m.alertInterval {
+		return
+	}
+
+	if err := m.alert(runway); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":      models.EventMonitorWallet,
+			"currency":   m.currency,
+			"recipients": m.recipients,
+			"error":      err.Error(),
+		}).Error("failed to send wallet alert")
+		return
+	}
+	m.alertedAt = time.Now()
+}
+
+func (m *Monitor) alert(runway models.WalletRunway) error {
+	address, err := m.payout.GetReceiveAddress()
+	if err != nil {
+		return err
+	}
+
+	w := bytes.NewBufferString("")
+	if err := m.tmpl.Execute(w, map[string]interface{}{
+		"appname":             m.appname,
+		"currency":            m.currency,
+		"address":             address,
+		"balance":             runway.Balance,
+		"pending_withdrawals": runway.PendingWithdrawals,
+		"liabilities":         runway.Liabilities,
+		"shortfall":           runway.Shortfall,
+		"daily_payout":        runway.DailyPayout,
+		"runway_days":         fmt.Sprintf("%.1f", *runway.RunwayDays),
+	}); err != nil {
+		return err
+	}
+
+	return m.sendEmail(m.recipients, fmt.Sprintf("%s --- Hot %s wallet is running out of coins", m.appname, m.currency), w.String())
+}
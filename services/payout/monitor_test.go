@@ -0,0 +1,110 @@
+package payout_test
+
+import (
+	"errors"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/services/payout"
+	"github.com/solefaucet/sole-server/services/payout/memory"
+)
+
+type mockMonitorStorage struct {
+	userBalances float64
+	withdrawals  []models.WithdrawalStats
+	paidOut      float64
+	err          error
+	emails       []string
+}
+
+func (m *mockMonitorStorage) monitor(p payout.Payout, recipients []string) *payout.Monitor {
+	return payout.NewMonitor(
+		p,
+		3,
+		time.Hour,
+		recipients,
+		template.Must(template.New("").Parse("{{.address}} lasts {{.runway_days}} days")),
+		"sole",
+		"btc",
+		func() (float64, error) { return m.userBalances, m.err },
+		func() ([]models.WithdrawalStats, error) { return m.withdrawals, nil },
+		func(time.Time) (float64, error) { return m.paidOut, nil },
+		func(recipients []string, subject string, html string) error {
+			m.emails = append(m.emails, html)
+			return nil
+		},
+	)
+}
+
+func TestRunway(t *testing.T) {
+	s := &mockMonitorStorage{
+		userBalances: 5,
+		withdrawals: []models.WithdrawalStats{
+			{Status: models.WithdrawalStatusPending, Amount: 1},
+			{Status: models.WithdrawalStatusProcessed, Amount: 100},
+			{Status: models.WithdrawalStatusReview, Amount: 2},
+		},
+		paidOut: 14,
+	}
+
+	runway, err := s.monitor(memory.New(10, "wallet"), nil).Runway()
+	if err != nil {
+		t.Fatalf("error should be nil but get %v", err)
+	}
+	if runway.PendingWithdrawals != 3 || runway.Liabilities != 8 || runway.Shortfall != 0 {
+		t.Errorf("liabilities should be 8 of which 3 pending but get %v", runway)
+	}
+	if runway.DailyPayout != 2 || *runway.RunwayDays != 3.5 {
+		t.Errorf("balance should last 3.5 days at 2 per day but get %v", runway)
+	}
+
+	runway, _ = s.monitor(memory.New(2, "wallet"), nil).Runway()
+	if runway.Shortfall != 6 || *runway.RunwayDays != 0 {
+		t.Errorf("balance should not cover pending withdrawals but get %v", runway)
+	}
+
+	s.paidOut = 0
+	runway, _ = s.monitor(memory.New(10, "wallet"), nil).Runway()
+	if runway.RunwayDays != nil {
+		t.Errorf("runway should be unknown without recent payout but get %v", *runway.RunwayDays)
+	}
+
+	s.err = errors.New("db down")
+	if _, err := s.monitor(memory.New(10, "wallet"), nil).Runway(); err == nil {
+		t.Error("error should not be nil")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := &mockMonitorStorage{
+		withdrawals: []models.WithdrawalStats{{Status: models.WithdrawalStatusPending, Amount: 1}},
+		paidOut:     14,
+	}
+	p := memory.New(5, "wallet")
+	m := s.monitor(p, []string{"ops@example.com"})
+
+	// 2 days left, alert once within interval
+	m.Check()
+	m.Check()
+	if len(s.emails) != 1 || s.emails[0] != "wallet lasts 2.0 days" {
+		t.Errorf("one alert should be sent but get %v", s.emails)
+	}
+
+	// alert again as soon as balance runs out again after top up
+	p.SetBalance(100)
+	m.Check()
+	p.SetBalance(5)
+	m.Check()
+	if len(s.emails) != 2 {
+		t.Errorf("alert should be sent after recovery but get %v", s.emails)
+	}
+
+	// no recipients
+	s.emails = nil
+	s.monitor(memory.New(5, "wallet"), nil).Check()
+	if len(s.emails) != 0 {
+		t.Errorf("no alert should be sent without recipients but get %v", s.emails)
+	}
+}
@@ -0,0 +1,44 @@
+package payout
+
+import "time"
+
+// Payout defines interface that one should implement to pay withdrawals out,
+// EstimateFeePerOutput returns network fee a withdrawal adds to batch transaction,
+// SendBatch calls sent with each transaction as soon as it is sent, and stops sending if sent returns error,
+// coins to some of addresses may be sent before error is returned if they are not sent in one transaction,
+// FindBatch returns transaction id of each address paid by transactions sent since with comment containing batchID,
+// it returns errors.ErrFindBatchNotSupported if transactions can not be looked up by batchID
+type Payout interface {
+	ValidateAddress(address string) (bool, error)
+	GetBalance() (float64, error)
+	EstimateFeePerOutput() (float64, error)
+	GetReceiveAddress() (string, error)
+	SendBatch(amounts map[string]float64, comment string, sent SentFunc) error
+	GetTransactionStatus(transactionID string) (TransactionStatus, error)
+	FindBatch(batchID string, since time.Time) (transactionIDs map[string]string, err error)
+}
+
+// SentFunc records transaction paying addresses once it is sent
+type SentFunc func(transactionID string, addresses []string) error
+
+// UnsentError is returned by SendBatch with addresses coins are known not sent to,
+// coins to addresses neither reported sent nor listed here may or may not be sent,
+// InvalidAddress is true if wallet rejects transaction for an invalid address among them
+type UnsentError struct {
+	Addresses      []string
+	InvalidAddress bool
+	Err            error
+}
+
+func (e *UnsentError) Error() string {
+	return e.Err.Error()
+}
+
+// TransactionStatus of a sent transaction, confirmations is 0 until transaction is mined,
+// and negative if it conflicts with a mined transaction, BlockTime is nil until mined
+type TransactionStatus struct {
+	TransactionID string
+	Confirmations int64
+	BlockTime     *time.Time
+	Fee           float64
+}
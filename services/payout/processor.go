@@ -0,0 +1,466 @@
+package payout
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/utils"
+)
+
+// dependencies of processor on storage
+type (
+	dependencyGetPendingWithdrawals             func() ([]models.Withdrawal, error)
+	dependencyCreatePayoutBatch                 func(batchID string, withdrawalIDs []int64) error
+	dependencyUpdatePayoutBatchStatus           func(batchID string, status int64) error
+	dependencyUpdateWithdrawalStatusToProcessed func(ids []int64, transactionID string) error
+	dependencyUpdateWithdrawalStatusToPending   func(ids []int64) error
+	dependencyQuarantineWithdrawals             func(ids []int64, reason string) error
+)
+
+// Processor pays pending withdrawals out in batches
+type Processor struct {
+	payout                            Payout
+	comment                           string
+	maxFeePerOutput                   float64
+	maxOutputs                        int
+	getUnfinishedPayoutBatches        dependencyGetUnfinishedPayoutBatches
+	getPendingWithdrawals             dependencyGetPendingWithdrawals
+	createPayoutBatch                 dependencyCreatePayoutBatch
+	updatePayoutBatchStatus           dependencyUpdatePayoutBatchStatus
+	updateWithdrawalStatusToProcessed dependencyUpdateWithdrawalStatusToProcessed
+	updateWithdrawalStatusToPending   dependencyUpdateWithdrawalStatusToPending
+	quarantineWithdrawals             dependencyQuarantineWithdrawals
+}
+
+// NewProcessor creates a processor sending coins with payout, comment is attached to transactions,
+// payout is postponed while estimated fee per withdrawal exceeds maxFeePerOutput
+// and stops once fee paid by a sent transaction exceeds it,
+// a transaction pays at most maxOutputs addresses, 0 means no limit for both,
+// every transaction is journaled as payout batch before it is sent,
+// nothing is sent while a batch of unknown outcome is left unfinished
+func NewProcessor(
+	payout Payout,
+	comment string,
+	maxFeePerOutput float64,
+	maxOutputs int,
+	getUnfinishedPayoutBatches dependencyGetUnfinishedPayoutBatches,
+	getPendingWithdrawals dependencyGetPendingWithdrawals,
+	createPayoutBatch dependencyCreatePayoutBatch,
+	updatePayoutBatchStatus dependencyUpdatePayoutBatchStatus,
+	updateWithdrawalStatusToProcessed dependencyUpdateWithdrawalStatusToProcessed,
+	updateWithdrawalStatusToPending dependencyUpdateWithdrawalStatusToPending,
+	quarantineWithdrawals dependencyQuarantineWithdrawals,
+) Processor {
+	return Processor{
+		payout:                            payout,
+		comment:                           comment,
+		maxFeePerOutput:                   maxFeePerOutput,
+		maxOutputs:                        maxOutputs,
+		getUnfinishedPayoutBatches:        getUnfinishedPayoutBatches,
+		getPendingWithdrawals:             getPendingWithdrawals,
+		createPayoutBatch:                 createPayoutBatch,
+		updatePayoutBatchStatus:           updatePayoutBatchStatus,
+		updateWithdrawalStatusToProcessed: updateWithdrawalStatusToProcessed,
+		updateWithdrawalStatusToPending:   updateWithdrawalStatusToPending,
+		quarantineWithdrawals:             quarantineWithdrawals,
+	}
+}
+
+// Process sends pending withdrawals oldest first as long as wallet balance affords
+// amounts and estimated network fees, in transactions of at most maxOutputs addresses
+func (p Processor) Process() {
+	start := time.Now()
+
+	// coins to withdrawals of unfinished batch may or may not be sent, wait until it is reconciled
+	batches, err := p.getUnfinishedPayoutBatches()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event": models.EventProcessWithdrawals,
+			"error": err.Error(),
+		}).Error("failed to get unfinished payout batches")
+		return
+	}
+	if len(batches) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"event":    models.EventProcessWithdrawals,
+			"batch_id": batches[0].BatchID,
+		}).Warn("payout batch is unfinished, postpone withdrawals until it is reconciled")
+		return
+	}
+
+	withdrawals, err := p.getPendingWithdrawals()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event": models.EventProcessWithdrawals,
+			"error": err.Error(),
+		}).Error("failed to get pending withdrawals")
+		return
+	}
+
+	// do nothing if there is nothing to withdraw
+	if len(withdrawals) == 0 {
+		return
+	}
+
+	balance, err := p.payout.GetBalance()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event": models.EventProcessWithdrawals,
+			"error": err.Error(),
+		}).Error("failed to get balance")
+		return
+	}
+
+	feePerOutput, err := p.payout.EstimateFeePerOutput()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event": models.EventProcessWithdrawals,
+			"error": err.Error(),
+		}).Error("failed to estimate fee")
+		return
+	}
+
+	if p.maxFeePerOutput > 0 && feePerOutput > p.maxFeePerOutput {
+		logrus.WithFields(logrus.Fields{
+			"event":                 models.EventProcessWithdrawals,
+			"fee_per_output":        feePerOutput,
+			"max_fee_per_output":    p.maxFeePerOutput,
+			"number_of_withdrawals": len(withdrawals),
+		}).Warn("estimated fee exceeds ceiling, postpone withdrawals")
+		return
+	}
+
+	totalWithdrawal, batch := batchOf(withdrawals, balance, feePerOutput)
+
+	// nothing to withdraw
+	if len(batch) <= 0 {
+		address, _ := p.payout.GetReceiveAddress()
+		logrus.WithFields(logrus.Fields{
+			"event":                   models.EventProcessWithdrawals,
+			"address_to_receive_coin": address,
+			"current_balance":         balance,
+			"fee_per_output":          feePerOutput,
+			"oldest_amount":           withdrawals[0].AmountToSend(),
+		}).Warn("need more coins to process withdrawal request")
+		return
+	}
+
+	// each sub batch goes from pending to processing right before it is sent,
+	// so sub batches not sent yet stay pending if processor stops halfway
+	sent := 0
+	for _, subBatch := range splitBatch(batch, p.maxOutputs) {
+		n, ok := p.send(subBatch)
+		sent += n
+		if !ok {
+			break
+		}
+	}
+
+	remaining, _ := p.payout.GetBalance()
+	address, _ := p.payout.GetReceiveAddress()
+	logrus.WithFields(logrus.Fields{
+		"event":                   models.EventProcessWithdrawals,
+		"duration":                float64(time.Since(start).Nanoseconds()) / 1e6,
+		"total":                   totalWithdrawal,
+		"remaining_balance":       remaining,
+		"address_to_receive_coin": address,
+		"number_of_withdrawals":   len(batch),
+		"number_of_sent":          sent,
+	}).Info("succeed to process withdraw requests")
+}
+
+// journal withdrawals as processing batch, send and mark them processed as soon as each transaction is sent,
+// withdrawals known not sent are put back to pending, batch rejected for invalid address is split in halves
+// and sent again until the address failing it is found and quarantined,
+// returns number of withdrawals sent and false if sending should stop,
+// i.e. fee paid exceeds ceiling or outcome of batch is unknown
+func (p Processor) send(batch []models.Withdrawal) (int, bool) {
+	batchID := batchIDOf(batch)
+	if err := p.createPayoutBatch(batchID, idsOf(batch)); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventProcessWithdrawals,
+			"batch_id":       batchID,
+			"withdrawal_ids": idsOf(batch),
+			"error":          err.Error(),
+		}).Error("fail to create payout batch")
+		return 0, true
+	}
+
+	withdrawalsOf := map[string][]models.Withdrawal{}
+	for _, v := range batch {
+		address := strings.TrimSpace(v.Address)
+		withdrawalsOf[address] = append(withdrawalsOf[address], v)
+	}
+
+	// update withdrawal status to processed in db right after transaction is sent,
+	// so that it is recorded even if a later transaction of batch fails
+	amounts := amountsOf(batch)
+	sent, withinCeiling := 0, true
+	sendErr := p.payout.SendBatch(amounts, fmt.Sprintf("%s #%s", p.comment, batchID), func(transactionID string, addresses []string) error {
+		paid := map[string]bool{}
+		for _, address := range addresses {
+			paid[address] = true
+		}
+
+		ids := []int64{}
+		for _, v := range batch {
+			address := strings.TrimSpace(v.Address)
+			if _, ok := withdrawalsOf[address]; ok && paid[address] {
+				ids = append(ids, v.ID)
+			}
+		}
+		for address := range paid {
+			delete(withdrawalsOf, address)
+		}
+
+		if err := p.updateWithdrawalStatusToProcessed(ids, transactionID); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"event":          models.EventProcessWithdrawals,
+				"id":             ids,
+				"transaction_id": transactionID,
+				"error":          err.Error(),
+			}).Panic("failed to update withdrawal status to processed")
+			return err
+		}
+
+		sent += len(ids)
+		withinCeiling = withinCeiling && p.checkFee(transactionID, len(addresses))
+		return nil
+	})
+
+	if sendErr == nil {
+		p.finishPayoutBatch(batchID, models.PayoutBatchStatusSent)
+		return sent, withinCeiling
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":    models.EventProcessWithdrawals,
+		"amounts":  amounts,
+		"batch_id": batchID,
+		"error":    sendErr.Error(),
+	}).Error("fail to send coin")
+
+	// only withdrawals known not sent go back to pending, the rest may have been paid
+	e, ok := sendErr.(*UnsentError)
+	invalidAddress := ok && e.InvalidAddress
+	if ok && !invalidAddress {
+		unsent := []models.Withdrawal{}
+		for _, address := range e.Addresses {
+			unsent = append(unsent, withdrawalsOf[address]...)
+			delete(withdrawalsOf, address)
+		}
+		p.putBackToPending(unsent)
+	}
+
+	// outcome of the rest is unknown, batch is left sending with them processing for reconciler
+	// and nothing more is sent until it is reconciled
+	if !invalidAddress && len(withdrawalsOf) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventProcessWithdrawals,
+			"batch_id":       batchID,
+			"withdrawal_ids": idsOf(batch),
+		}).Error("payout batch is left sending for reconciliation, stop sending")
+		return sent, false
+	}
+
+	p.finishPayoutBatch(batchID, models.PayoutBatchStatusFailed)
+
+	// only invalid address is worth splitting batch for, nothing of batch is sent
+	if !invalidAddress || !withinCeiling {
+		return sent, withinCeiling
+	}
+
+	left, right := bisect(batch)
+	if len(right) == 0 {
+		p.quarantine(batch, sendErr)
+		return sent, true
+	}
+
+	sentLeft, ok := p.send(left)
+	if !ok {
+		return sent + sentLeft, false
+	}
+	sentRight, ok := p.send(right)
+	return sent + sentLeft + sentRight, ok
+}
+
+// journal outcome of payout batch
+func (p Processor) finishPayoutBatch(batchID string, status int64) {
+	if err := p.updatePayoutBatchStatus(batchID, status); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":    models.EventProcessWithdrawals,
+			"batch_id": batchID,
+			"status":   status,
+			"error":    err.Error(),
+		}).Error("fail to update payout batch status")
+	}
+}
+
+// wallet may pay more fee than estimated, so fee actually paid by sent transaction is checked against ceiling,
+// returns false if it exceeds ceiling or can not be checked
+func (p Processor) checkFee(transactionID string, outputs int) bool {
+	if p.maxFeePerOutput <= 0 || outputs == 0 {
+		return true
+	}
+
+	status, err := p.payout.GetTransactionStatus(transactionID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventProcessWithdrawals,
+			"transaction_id": transactionID,
+			"error":          err.Error(),
+		}).Error("failed to get fee paid by transaction, stop sending")
+		return false
+	}
+
+	if feePerOutput := status.Fee / float64(outputs); feePerOutput > p.maxFeePerOutput {
+		logrus.WithFields(logrus.Fields{
+			"event":              models.EventProcessWithdrawals,
+			"transaction_id":     transactionID,
+			"fee":                status.Fee,
+			"fee_per_output":     feePerOutput,
+			"max_fee_per_output": p.maxFeePerOutput,
+		}).Error("fee paid exceeds ceiling, stop sending")
+		return false
+	}
+
+	return true
+}
+
+// withdrawals known not sent are paid out again by next run
+func (p Processor) putBackToPending(withdrawals []models.Withdrawal) {
+	if len(withdrawals) == 0 {
+		return
+	}
+
+	ids := idsOf(withdrawals)
+	if err := p.updateWithdrawalStatusToPending(ids); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventProcessWithdrawals,
+			"withdrawal_ids": ids,
+			"error":          err.Error(),
+		}).Error("failed to put unsent withdrawals back to pending")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":          models.EventProcessWithdrawals,
+		"withdrawal_ids": ids,
+	}).Warn("withdrawals not sent are put back to pending")
+}
+
+// withdrawals to address failing transaction are put back to review
+func (p Processor) quarantine(withdrawals []models.Withdrawal, sendErr error) {
+	ids := idsOf(withdrawals)
+	if err := p.quarantineWithdrawals(ids, "payout failed: "+sendErr.Error()); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventProcessWithdrawals,
+			"withdrawal_ids": ids,
+			"error":          err.Error(),
+		}).Error("failed to quarantine withdrawals")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":          models.EventProcessWithdrawals,
+		"withdrawal_ids": ids,
+		"address":        strings.TrimSpace(withdrawals[0].Address),
+		"error":          sendErr.Error(),
+	}).Warn("quarantine withdrawals failing transaction")
+}
+
+// build batch of withdrawals oldest first until balance can not afford the next one,
+// withdrawals to same address share one output, total includes estimated fees
+func batchOf(withdrawals []models.Withdrawal, balance, feePerOutput float64) (total float64, batch []models.Withdrawal) {
+	addresses := map[string]bool{}
+	for _, v := range withdrawals {
+		address := strings.TrimSpace(v.Address)
+		cost := v.AmountToSend()
+		if !addresses[address] {
+			cost += feePerOutput
+		}
+
+		if total+cost > balance {
+			break
+		}
+
+		total += cost
+		addresses[address] = true
+		batch = append(batch, v)
+	}
+	return
+}
+
+// merge amounts of withdrawals to same address into one output
+func amountsOf(withdrawals []models.Withdrawal) map[string]float64 {
+	amounts := map[string]float64{}
+	for _, v := range withdrawals {
+		address := strings.TrimSpace(v.Address)
+		amounts[address] = utils.ToFixed(amounts[address]+v.AmountToSend(), 8)
+	}
+	return amounts
+}
+
+// batch id is derived from withdrawal ids, same withdrawals always make same batch
+func batchIDOf(withdrawals []models.Withdrawal) string {
+	ids := idsOf(withdrawals)
+	sort.Sort(int64Slice(ids))
+
+	h := sha256.New()
+	for _, id := range ids {
+		fmt.Fprintf(h, "%d,", id)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+type int64Slice []int64
+
+func (s int64Slice) Len() int           { return len(s) }
+func (s int64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func idsOf(withdrawals []models.Withdrawal) []int64 {
+	ids := make([]int64, len(withdrawals))
+	for i := range withdrawals {
+		ids[i] = withdrawals[i].ID
+	}
+	return ids
+}
+
+// split withdrawals into batches of at most maxOutputs addresses in order of first appearance,
+// withdrawals to same address stay in same batch, 0 means no limit
+func splitBatch(withdrawals []models.Withdrawal, maxOutputs int) [][]models.Withdrawal {
+	batchOfAddress := map[string]int{}
+	batches := [][]models.Withdrawal{}
+	outputs := 0
+	for _, v := range withdrawals {
+		address := strings.TrimSpace(v.Address)
+		i, ok := batchOfAddress[address]
+		if !ok {
+			if len(batches) == 0 || maxOutputs > 0 && outputs >= maxOutputs {
+				batches = append(batches, nil)
+				outputs = 0
+			}
+			i = len(batches) - 1
+			batchOfAddress[address] = i
+			outputs++
+		}
+		batches[i] = append(batches[i], v)
+	}
+	return batches
+}
+
+// split withdrawals into halves by address, right is empty if there is only one address
+func bisect(withdrawals []models.Withdrawal) (left, right []models.Withdrawal) {
+	batches := splitBatch(withdrawals, (len(amountsOf(withdrawals))+1)/2)
+	if len(batches) < 2 {
+		return withdrawals, nil
+	}
+	return batches[0], batches[1]
+}
@@ -0,0 +1,347 @@
+package payout_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/services/payout"
+	"github.com/solefaucet/sole-server/services/payout/memory"
+)
+
+type mockStorage struct {
+	maxFee           float64
+	maxOutputs       int
+	withdrawals      []models.Withdrawal
+	err              error
+	processingErr    error
+	processedErr     error
+	processingIDs    []int64
+	processedIDs     []int64
+	processedTxID    string
+	pendingIDs       []int64
+	processedCalled  bool
+	quarantinedIDs   []int64
+	quarantineReason string
+	batchStatus      map[string]int64
+}
+
+func (m *mockStorage) processor(p payout.Payout) payout.Processor {
+	return payout.NewProcessor(
+		p,
+		"comment",
+		m.maxFee,
+		m.maxOutputs,
+		func() ([]models.PayoutBatch, error) {
+			batches := []models.PayoutBatch{}
+			for batchID, status := range m.batchStatus {
+				if status == models.PayoutBatchStatusSending {
+					batches = append(batches, models.PayoutBatch{BatchID: batchID, Status: status})
+				}
+			}
+			return batches, nil
+		},
+		func() ([]models.Withdrawal, error) { return m.withdrawals, m.err },
+		func(batchID string, ids []int64) error {
+			if m.processingErr != nil {
+				return m.processingErr
+			}
+			if m.batchStatus == nil {
+				m.batchStatus = map[string]int64{}
+			}
+			m.batchStatus[batchID] = models.PayoutBatchStatusSending
+			m.processingIDs = append(m.processingIDs, ids...)
+			return nil
+		},
+		func(batchID string, status int64) error {
+			m.batchStatus[batchID] = status
+			return nil
+		},
+		func(ids []int64, transactionID string) error {
+			m.processedCalled = true
+			m.processedIDs, m.processedTxID = append(m.processedIDs, ids...), transactionID
+			return m.processedErr
+		},
+		func(ids []int64) error {
+			m.pendingIDs = append(m.pendingIDs, ids...)
+			return nil
+		},
+		func(ids []int64, reason string) error {
+			m.quarantinedIDs, m.quarantineReason = append(m.quarantinedIDs, ids...), reason
+			return nil
+		},
+	)
+}
+
+// sent callback for tests sending coins directly with payout
+func ignoreSent(string, []string) error { return nil }
+
+func TestProcess(t *testing.T) {
+	withdrawals := []models.Withdrawal{
+		{ID: 1, Address: "a1", Amount: 1},
+		{ID: 2, Address: " a1 ", Amount: 2},
+		{ID: 3, Address: "a2", Amount: 5},
+	}
+
+	// balance affords first two withdrawals with fee
+	p := memory.New(4, "wallet")
+	s := &mockStorage{withdrawals: withdrawals}
+	s.processor(p).Process()
+
+	if !reflect.DeepEqual(s.processingIDs, []int64{1, 2}) {
+		t.Errorf("withdrawals 1, 2 should be processing but get %v", s.processingIDs)
+	}
+	if !reflect.DeepEqual(s.processedIDs, []int64{1, 2}) || s.processedTxID != "tx1" {
+		t.Errorf("withdrawals 1, 2 should be processed with tx1 but get %v %v", s.processedIDs, s.processedTxID)
+	}
+	if batches := p.Batches(); len(batches) != 1 || !reflect.DeepEqual(batches[0], map[string]float64{"a1": 3}) {
+		t.Errorf("amounts of same address should be merged but get %v", batches)
+	}
+	if balance, _ := p.GetBalance(); balance != 1 {
+		t.Errorf("remaining balance should be 1 but get %v", balance)
+	}
+}
+
+func TestProcessJournal(t *testing.T) {
+	p := memory.New(10, "")
+	s := &mockStorage{withdrawals: []models.Withdrawal{{ID: 1, Address: "a1", Amount: 1}}}
+	s.processor(p).Process()
+
+	if len(s.batchStatus) != 1 {
+		t.Fatalf("one payout batch should be journaled but get %v", s.batchStatus)
+	}
+	for batchID, status := range s.batchStatus {
+		if status != models.PayoutBatchStatusSent {
+			t.Errorf("payout batch should be sent but get %v", status)
+		}
+		if txIDs, _ := p.FindBatch(batchID, time.Now()); txIDs["a1"] != "tx1" {
+			t.Errorf("batch id should be attached to transaction comment but get %v", txIDs)
+		}
+	}
+}
+
+func TestProcessNothingToSend(t *testing.T) {
+	testdata := []struct {
+		when    string
+		payout  *memory.Payout
+		storage *mockStorage
+	}{
+		{"errored getPendingWithdrawals", memory.New(10, ""), &mockStorage{err: errors.New("")}},
+		{"no pending withdrawals", memory.New(10, ""), &mockStorage{}},
+		{"insufficient balance", memory.New(0.5, ""), &mockStorage{withdrawals: []models.Withdrawal{{ID: 1, Amount: 1}}}},
+		{"oldest withdrawal unaffordable", memory.New(2, ""), &mockStorage{withdrawals: []models.Withdrawal{{ID: 1, Amount: 3}, {ID: 2, Amount: 1}}}},
+		{"errored createPayoutBatch", memory.New(10, ""), &mockStorage{withdrawals: []models.Withdrawal{{ID: 1, Amount: 1}}, processingErr: errors.New("")}},
+	}
+
+	for _, v := range testdata {
+		v.storage.processor(v.payout).Process()
+		if len(v.payout.Batches()) != 0 || v.storage.processedCalled {
+			t.Errorf("nothing should be sent when %s", v.when)
+		}
+	}
+}
+
+func TestProcessWithFee(t *testing.T) {
+	withdrawals := []models.Withdrawal{
+		{ID: 1, Address: "a1", Amount: 1, Fee: 0.1},
+		{ID: 2, Address: "a2", Amount: 1, Fee: 0.1},
+		{ID: 3, Address: "a1", Amount: 1, Fee: 0.1},
+	}
+
+	// network fee 0.3 per address, 0.9 + 0.3 + 0.9 + 0.3 fits but a1 again costs 0.9 more
+	p := memory.New(2.5, "")
+	p.SetFeePerOutput(0.3)
+	s := &mockStorage{withdrawals: withdrawals}
+	s.processor(p).Process()
+
+	if !reflect.DeepEqual(s.processedIDs, []int64{1, 2}) {
+		t.Errorf("withdrawals 1, 2 should be processed but get %v", s.processedIDs)
+	}
+	if batches := p.Batches(); len(batches) != 1 || !reflect.DeepEqual(batches[0], map[string]float64{"a1": 0.9, "a2": 0.9}) {
+		t.Errorf("user fee should be deducted from amounts but get %v", batches)
+	}
+}
+
+func TestProcessFeeCeiling(t *testing.T) {
+	p := memory.New(10, "")
+	p.SetFeePerOutput(0.3)
+	s := &mockStorage{maxFee: 0.2, withdrawals: []models.Withdrawal{{ID: 1, Address: "a", Amount: 1}}}
+	s.processor(p).Process()
+
+	if len(p.Batches()) != 0 || s.processingIDs != nil {
+		t.Error("withdrawals should be postponed when fee exceeds ceiling")
+	}
+
+	s.maxFee = 0.3
+	s.processor(p).Process()
+	if len(p.Batches()) != 1 {
+		t.Error("withdrawals should be sent when fee is within ceiling")
+	}
+}
+
+// pays more fee than estimated, like wallet using a fee rate higher than estimatesmartfee
+type overpayingPayout struct {
+	*memory.Payout
+}
+
+func (p overpayingPayout) GetTransactionStatus(transactionID string) (payout.TransactionStatus, error) {
+	status, err := p.Payout.GetTransactionStatus(transactionID)
+	status.Fee *= 2
+	return status, err
+}
+
+func TestProcessFeePaidExceedsCeiling(t *testing.T) {
+	p := memory.New(10, "")
+	p.SetFeePerOutput(0.2)
+	s := &mockStorage{maxFee: 0.3, maxOutputs: 1, withdrawals: []models.Withdrawal{{ID: 1, Address: "a1", Amount: 1}, {ID: 2, Address: "a2", Amount: 1}}}
+	s.processor(overpayingPayout{p}).Process()
+
+	if !reflect.DeepEqual(s.processedIDs, []int64{1}) {
+		t.Errorf("only withdrawal 1 should be processed but get %v", s.processedIDs)
+	}
+	if !reflect.DeepEqual(s.processingIDs, []int64{1}) {
+		t.Errorf("withdrawal 2 should be left pending but get processing %v", s.processingIDs)
+	}
+}
+
+func TestProcessSendError(t *testing.T) {
+	p := failingPayout{memory.New(10, "")}
+	s := &mockStorage{maxOutputs: 1, withdrawals: []models.Withdrawal{{ID: 1, Address: "a1", Amount: 1}, {ID: 2, Address: "a2", Amount: 1}}}
+	s.processor(p).Process()
+
+	if s.processedCalled || s.pendingIDs != nil || s.quarantinedIDs != nil {
+		t.Error("withdrawals should be left processing when outcome of sending coins is unknown")
+	}
+	if !reflect.DeepEqual(s.processingIDs, []int64{1}) {
+		t.Errorf("nothing should be sent after batch of unknown outcome but get processing %v", s.processingIDs)
+	}
+	for batchID, status := range s.batchStatus {
+		if status != models.PayoutBatchStatusSending {
+			t.Errorf("payout batch %v should be left sending but get %v", batchID, status)
+		}
+	}
+
+	// batch is not reconciled yet
+	p2 := memory.New(10, "")
+	s.processor(p2).Process()
+	if len(p2.Batches()) != 0 || !reflect.DeepEqual(s.processingIDs, []int64{1}) {
+		t.Errorf("nothing should be sent while payout batch is unfinished but get %v", p2.Batches())
+	}
+}
+
+// fails sending with error of unknown outcome, like wallet timing out
+type failingPayout struct {
+	*memory.Payout
+}
+
+func (p failingPayout) SendBatch(amounts map[string]float64, comment string, sent payout.SentFunc) error {
+	return errors.New("timeout")
+}
+
+// sends to first address only and rejects second, like ethereum payout failing halfway
+type partialPayout struct {
+	*memory.Payout
+}
+
+func (p partialPayout) SendBatch(amounts map[string]float64, comment string, sent payout.SentFunc) error {
+	if err := sent("tx1", []string{"a1"}); err != nil {
+		return err
+	}
+	return &payout.UnsentError{Addresses: []string{"a2"}, Err: errors.New("")}
+}
+
+func TestProcessSendPartially(t *testing.T) {
+	s := &mockStorage{withdrawals: []models.Withdrawal{{ID: 1, Address: "a1", Amount: 1}, {ID: 2, Address: "a2", Amount: 1}}}
+	s.processor(partialPayout{memory.New(10, "")}).Process()
+
+	if !reflect.DeepEqual(s.processedIDs, []int64{1}) || s.processedTxID != "tx1" {
+		t.Errorf("only withdrawal 1 should be processed with tx1 but get %v %v", s.processedIDs, s.processedTxID)
+	}
+	if !reflect.DeepEqual(s.pendingIDs, []int64{2}) {
+		t.Errorf("withdrawal 2 not sent should be put back to pending but get %v", s.pendingIDs)
+	}
+	if s.quarantinedIDs != nil {
+		t.Errorf("nothing should be quarantined but get %v", s.quarantinedIDs)
+	}
+}
+
+// fails any batch paying to bad address
+type badAddressPayout struct {
+	*memory.Payout
+	bad string
+}
+
+func (p badAddressPayout) SendBatch(amounts map[string]float64, comment string, sent payout.SentFunc) error {
+	if _, ok := amounts[p.bad]; ok {
+		return &payout.UnsentError{InvalidAddress: true, Err: errors.New("invalid address")}
+	}
+	return p.Payout.SendBatch(amounts, comment, sent)
+}
+
+func TestProcessSplitBatch(t *testing.T) {
+	withdrawals := []models.Withdrawal{
+		{ID: 1, Address: "a1", Amount: 1},
+		{ID: 2, Address: "a2", Amount: 1},
+		{ID: 3, Address: "a1", Amount: 1},
+		{ID: 4, Address: "a3", Amount: 1},
+		{ID: 5, Address: "a4", Amount: 1},
+	}
+
+	p := memory.New(10, "")
+	s := &mockStorage{maxOutputs: 2, withdrawals: withdrawals}
+	s.processor(p).Process()
+
+	expected := []map[string]float64{{"a1": 2, "a2": 1}, {"a3": 1, "a4": 1}}
+	if !reflect.DeepEqual(p.Batches(), expected) {
+		t.Errorf("batches should be %v but get %v", expected, p.Batches())
+	}
+	if !reflect.DeepEqual(s.processedIDs, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("all withdrawals should be processed but get %v", s.processedIDs)
+	}
+}
+
+func TestProcessQuarantine(t *testing.T) {
+	withdrawals := []models.Withdrawal{
+		{ID: 1, Address: "a1", Amount: 1},
+		{ID: 2, Address: "a2", Amount: 1},
+		{ID: 3, Address: "bad", Amount: 1},
+		{ID: 4, Address: "a3", Amount: 1},
+		{ID: 5, Address: "bad", Amount: 1},
+	}
+
+	p := badAddressPayout{memory.New(10, ""), "bad"}
+	s := &mockStorage{withdrawals: withdrawals}
+	s.processor(p).Process()
+
+	expected := []map[string]float64{{"a1": 1, "a2": 1}, {"a3": 1}}
+	if !reflect.DeepEqual(p.Batches(), expected) {
+		t.Errorf("batches without bad address should be sent %v but get %v", expected, p.Batches())
+	}
+	if !reflect.DeepEqual(s.quarantinedIDs, []int64{3, 5}) || s.quarantineReason != "payout failed: invalid address" {
+		t.Errorf("withdrawals 3, 5 should be quarantined but get %v %v", s.quarantinedIDs, s.quarantineReason)
+	}
+
+	// first batch and halves with bad address fail
+	failed := 0
+	for _, status := range s.batchStatus {
+		if status == models.PayoutBatchStatusFailed {
+			failed++
+		}
+	}
+	if len(s.batchStatus) != 5 || failed != 3 {
+		t.Errorf("3 of 5 payout batches should fail but get %v", s.batchStatus)
+	}
+}
+
+func TestProcessUpdateProcessedError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("should panic when coins are sent but withdrawals are not updated")
+		}
+	}()
+
+	s := &mockStorage{withdrawals: []models.Withdrawal{{ID: 1, Address: "a", Amount: 1}}, processedErr: errors.New("")}
+	s.processor(memory.New(10, "")).Process()
+}
@@ -0,0 +1,136 @@
+package payout
+
+import (
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// dependencies of reconciler on storage
+type (
+	dependencyGetUnfinishedPayoutBatches            func() ([]models.PayoutBatch, error)
+	dependencyGetProcessingWithdrawalsByPayoutBatch func(batchID string) ([]models.Withdrawal, error)
+)
+
+// Reconciler finds outcome of payout batches left sending by a crashed processor or an error of unknown outcome
+type Reconciler struct {
+	payout                                Payout
+	getUnfinishedPayoutBatches            dependencyGetUnfinishedPayoutBatches
+	getProcessingWithdrawalsByPayoutBatch dependencyGetProcessingWithdrawalsByPayoutBatch
+	updateWithdrawalStatusToProcessed     dependencyUpdateWithdrawalStatusToProcessed
+	updatePayoutBatchStatus               dependencyUpdatePayoutBatchStatus
+}
+
+// NewReconciler creates a reconciler looking up unfinished batches in wallet of payout
+func NewReconciler(
+	payout Payout,
+	getUnfinishedPayoutBatches dependencyGetUnfinishedPayoutBatches,
+	getProcessingWithdrawalsByPayoutBatch dependencyGetProcessingWithdrawalsByPayoutBatch,
+	updateWithdrawalStatusToProcessed dependencyUpdateWithdrawalStatusToProcessed,
+	updatePayoutBatchStatus dependencyUpdatePayoutBatchStatus,
+) Reconciler {
+	return Reconciler{
+		payout:                                payout,
+		getUnfinishedPayoutBatches:            getUnfinishedPayoutBatches,
+		getProcessingWithdrawalsByPayoutBatch: getProcessingWithdrawalsByPayoutBatch,
+		updateWithdrawalStatusToProcessed:     updateWithdrawalStatusToProcessed,
+		updatePayoutBatchStatus:               updatePayoutBatchStatus,
+	}
+}
+
+// Reconcile marks withdrawals of unfinished batches found in wallet processed,
+// batches not found or found in part are put to review with the rest of withdrawals processing,
+// since a transaction missing from wallet does not prove coins are not sent,
+// batches that can not be looked up for now are left sending for next time
+func (r Reconciler) Reconcile() {
+	batches, err := r.getUnfinishedPayoutBatches()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event": models.EventReconcilePayoutBatches,
+			"error": err.Error(),
+		}).Error("failed to get unfinished payout batches")
+		return
+	}
+
+	for _, batch := range batches {
+		r.reconcile(batch)
+	}
+}
+
+func (r Reconciler) reconcile(batch models.PayoutBatch) {
+	batchID := batch.BatchID
+	withdrawals, err := r.getProcessingWithdrawalsByPayoutBatch(batchID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":    models.EventReconcilePayoutBatches,
+			"batch_id": batchID,
+			"error":    err.Error(),
+		}).Error("failed to get withdrawals of payout batch")
+		return
+	}
+
+	transactionIDs, err := r.payout.FindBatch(batchID, batch.CreatedAt)
+	if err != nil && err != errors.ErrFindBatchNotSupported {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventReconcilePayoutBatches,
+			"batch_id":       batchID,
+			"withdrawal_ids": idsOf(withdrawals),
+			"error":          err.Error(),
+		}).Error("failed to find payout batch in wallet")
+		return
+	}
+
+	notFoundIDs := []int64{}
+	transactionWithdrawalIDs := map[string][]int64{}
+	for _, v := range withdrawals {
+		if transactionID, ok := transactionIDs[strings.TrimSpace(v.Address)]; ok {
+			transactionWithdrawalIDs[transactionID] = append(transactionWithdrawalIDs[transactionID], v.ID)
+		} else {
+			notFoundIDs = append(notFoundIDs, v.ID)
+		}
+	}
+
+	for transactionID, ids := range transactionWithdrawalIDs {
+		if err := r.updateWithdrawalStatusToProcessed(ids, transactionID); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"event":          models.EventReconcilePayoutBatches,
+				"batch_id":       batchID,
+				"withdrawal_ids": ids,
+				"transaction_id": transactionID,
+				"error":          err.Error(),
+			}).Error("failed to update withdrawal status to processed")
+			return
+		}
+	}
+
+	status := int64(models.PayoutBatchStatusSent)
+	if len(notFoundIDs) > 0 {
+		status = models.PayoutBatchStatusReview
+	}
+	if err := r.updatePayoutBatchStatus(batchID, status); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":    models.EventReconcilePayoutBatches,
+			"batch_id": batchID,
+			"error":    err.Error(),
+		}).Error("failed to update payout batch status")
+		return
+	}
+
+	if status == models.PayoutBatchStatusReview {
+		logrus.WithFields(logrus.Fields{
+			"event":           models.EventReconcilePayoutBatches,
+			"batch_id":        batchID,
+			"transaction_ids": transactionIDs,
+			"not_found_ids":   notFoundIDs,
+		}).Error("payout batch is not found in wallet, check withdrawals not found manually")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event":           models.EventReconcilePayoutBatches,
+		"batch_id":        batchID,
+		"transaction_ids": transactionIDs,
+	}).Warn("reconciled unfinished payout batch")
+}
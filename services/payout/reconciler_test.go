@@ -0,0 +1,102 @@
+package payout_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/services/payout"
+	"github.com/solefaucet/sole-server/services/payout/memory"
+)
+
+type mockReconcilerStorage struct {
+	batches      []models.PayoutBatch
+	withdrawals  map[string][]models.Withdrawal
+	processedIDs map[string][]int64
+	batchStatus  map[string]int64
+}
+
+func (m *mockReconcilerStorage) reconciler(p payout.Payout) payout.Reconciler {
+	m.processedIDs = map[string][]int64{}
+	m.batchStatus = map[string]int64{}
+	return payout.NewReconciler(
+		p,
+		func() ([]models.PayoutBatch, error) { return m.batches, nil },
+		func(batchID string) ([]models.Withdrawal, error) { return m.withdrawals[batchID], nil },
+		func(ids []int64, transactionID string) error {
+			m.processedIDs[transactionID] = append(m.processedIDs[transactionID], ids...)
+			return nil
+		},
+		func(batchID string, status int64) error {
+			m.batchStatus[batchID] = status
+			return nil
+		},
+	)
+}
+
+func TestReconcile(t *testing.T) {
+	// batch b1 is sent before crash, b2 is not found, b3 is found in part
+	p := memory.New(10, "")
+	p.SendBatch(map[string]float64{"a1": 1, "a2": 1}, "payout #b1", ignoreSent)
+	p.SendBatch(map[string]float64{"a4": 1}, "payout #b3", ignoreSent)
+
+	s := &mockReconcilerStorage{
+		batches: []models.PayoutBatch{{BatchID: "b1"}, {BatchID: "b2"}, {BatchID: "b3"}},
+		withdrawals: map[string][]models.Withdrawal{
+			"b1": {{ID: 1, Address: "a1"}, {ID: 2, Address: "a2"}, {ID: 3, Address: " a1 "}},
+			"b2": {{ID: 4, Address: "a3"}},
+			"b3": {{ID: 5, Address: "a4"}, {ID: 6, Address: "a5"}},
+		},
+	}
+	s.reconciler(p).Reconcile()
+
+	if !reflect.DeepEqual(s.processedIDs, map[string][]int64{"tx1": {1, 2, 3}, "tx2": {5}}) {
+		t.Errorf("withdrawals found should be processed but get %v", s.processedIDs)
+	}
+	expected := map[string]int64{
+		"b1": models.PayoutBatchStatusSent,
+		"b2": models.PayoutBatchStatusReview,
+		"b3": models.PayoutBatchStatusReview,
+	}
+	if !reflect.DeepEqual(s.batchStatus, expected) {
+		t.Errorf("batch status should be %v but get %v", expected, s.batchStatus)
+	}
+}
+
+func TestReconcileWalletUnavailable(t *testing.T) {
+	p := memory.New(10, "")
+	p.SetError(errors.ErrUnknown)
+
+	s := &mockReconcilerStorage{
+		batches:     []models.PayoutBatch{{BatchID: "b1"}},
+		withdrawals: map[string][]models.Withdrawal{"b1": {{ID: 1, Address: "a1"}}},
+	}
+	s.reconciler(p).Reconcile()
+
+	if len(s.processedIDs) != 0 || len(s.batchStatus) != 0 {
+		t.Errorf("batch should be left unfinished but get %v %v", s.processedIDs, s.batchStatus)
+	}
+}
+
+// wallet not looking up transactions by batch, like ethereum payout
+type unfindablePayout struct {
+	*memory.Payout
+}
+
+func (p unfindablePayout) FindBatch(batchID string, since time.Time) (map[string]string, error) {
+	return nil, errors.ErrFindBatchNotSupported
+}
+
+func TestReconcileFindBatchNotSupported(t *testing.T) {
+	s := &mockReconcilerStorage{
+		batches:     []models.PayoutBatch{{BatchID: "b1"}},
+		withdrawals: map[string][]models.Withdrawal{"b1": {{ID: 1, Address: "a1"}}},
+	}
+	s.reconciler(unfindablePayout{memory.New(10, "")}).Reconcile()
+
+	if len(s.processedIDs) != 0 || s.batchStatus["b1"] != models.PayoutBatchStatusReview {
+		t.Errorf("batch should be put to review but get %v %v", s.processedIDs, s.batchStatus)
+	}
+}
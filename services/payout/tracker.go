@@ -0,0 +1,122 @@
+package payout
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// dependencies of tracker on storage
+type (
+	dependencyGetUnconfirmedWithdrawals     func(confirmations int64) ([]models.Withdrawal, error)
+	dependencyUpdateWithdrawalConfirmations func(transactionID string, confirmations int64, blockTime *time.Time, stuck bool) error
+)
+
+// Tracker polls transactions of processed withdrawals until they are confirmed
+type Tracker struct {
+	payout                        Payout
+	confirmations                 int64
+	stuckAfter                    time.Duration
+	getUnconfirmedWithdrawals     dependencyGetUnconfirmedWithdrawals
+	updateWithdrawalConfirmations dependencyUpdateWithdrawalConfirmations
+}
+
+// NewTracker creates a tracker polling transactions until they have confirmations given,
+// transactions not mined within stuckAfter since sent are flagged as stuck
+func NewTracker(
+	payout Payout,
+	confirmations int64,
+	stuckAfter time.Duration,
+	getUnconfirmedWithdrawals dependencyGetUnconfirmedWithdrawals,
+	updateWithdrawalConfirmations dependencyUpdateWithdrawalConfirmations,
+) Tracker {
+	return Tracker{
+		payout:                        payout,
+		confirmations:                 confirmations,
+		stuckAfter:                    stuckAfter,
+		getUnconfirmedWithdrawals:     getUnconfirmedWithdrawals,
+		updateWithdrawalConfirmations: updateWithdrawalConfirmations,
+	}
+}
+
+// Track records confirmations and block time of transactions of unconfirmed withdrawals,
+// and flags transactions that conflict or are dropped or not mined in time
+func (t Tracker) Track() {
+	start := time.Now()
+
+	withdrawals, err := t.getUnconfirmedWithdrawals(t.confirmations)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event": models.EventTrackWithdrawals,
+			"error": err.Error(),
+		}).Error("failed to get unconfirmed withdrawals")
+		return
+	}
+
+	// withdrawals paid in one transaction share its status, track each transaction once
+	tracked := map[string]bool{}
+	for _, w := range withdrawals {
+		if tracked[w.TransactionID] {
+			continue
+		}
+		tracked[w.TransactionID] = true
+		t.track(w, start)
+	}
+
+	if len(tracked) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"event":                  models.EventTrackWithdrawals,
+			"duration":               float64(time.Since(start).Nanoseconds()) / 1e6,
+			"number_of_transactions": len(tracked),
+		}).Info("succeed to track transactions")
+	}
+}
+
+func (t Tracker) track(w models.Withdrawal, now time.Time) {
+	// keep what is known if transaction is not found, wallet may have dropped it
+	confirmations, blockTime := w.Confirmations, w.BlockTime
+	status, err := t.payout.GetTransactionStatus(w.TransactionID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventTrackWithdrawals,
+			"transaction_id": w.TransactionID,
+			"error":          err.Error(),
+		}).Warn("failed to get transaction status")
+	} else {
+		confirmations, blockTime = status.Confirmations, status.BlockTime
+	}
+
+	stuck := confirmations < 0 ||
+		confirmations == 0 && w.ProcessedAt != nil && now.Sub(*w.ProcessedAt) > t.stuckAfter
+
+	if confirmations == w.Confirmations && stuck == w.Stuck && equalTime(blockTime, w.BlockTime) {
+		return
+	}
+
+	if err := t.updateWithdrawalConfirmations(w.TransactionID, confirmations, blockTime, stuck); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventTrackWithdrawals,
+			"transaction_id": w.TransactionID,
+			"error":          err.Error(),
+		}).Error("failed to update withdrawal confirmations")
+		return
+	}
+
+	// alert once when transaction becomes stuck
+	if stuck && !w.Stuck {
+		logrus.WithFields(logrus.Fields{
+			"event":          models.EventTrackWithdrawals,
+			"transaction_id": w.TransactionID,
+			"confirmations":  confirmations,
+			"processed_at":   w.ProcessedAt,
+		}).Error("transaction is not confirmed in time")
+	}
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,101 @@
+package payout_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/solefaucet/sole-server/models"
+	"github.com/solefaucet/sole-server/services/payout"
+	"github.com/solefaucet/sole-server/services/payout/memory"
+)
+
+type confirmationsUpdate struct {
+	confirmations int64
+	blockTime     *time.Time
+	stuck         bool
+}
+
+type mockTrackerStorage struct {
+	withdrawals []models.Withdrawal
+	err         error
+	updates     map[string]confirmationsUpdate
+}
+
+func (m *mockTrackerStorage) tracker(p payout.Payout) payout.Tracker {
+	m.updates = map[string]confirmationsUpdate{}
+	return payout.NewTracker(
+		p,
+		6,
+		time.Hour,
+		func(confirmations int64) ([]models.Withdrawal, error) { return m.withdrawals, m.err },
+		func(transactionID string, confirmations int64, blockTime *time.Time, stuck bool) error {
+			m.updates[transactionID] = confirmationsUpdate{confirmations, blockTime, stuck}
+			return nil
+		},
+	)
+}
+
+func TestTrack(t *testing.T) {
+	p := memory.New(10, "wallet")
+	for i := 0; i < 4; i++ {
+		p.SendBatch(map[string]float64{"a": 1}, "", ignoreSent)
+	}
+	blockTime := time.Now().Add(-time.Minute)
+	p.SetConfirmations("tx1", 2, &blockTime)
+	p.SetConfirmations("tx3", -1, nil)
+
+	recent := time.Now().Add(-time.Minute)
+	old := time.Now().Add(-2 * time.Hour)
+	s := &mockTrackerStorage{withdrawals: []models.Withdrawal{
+		{ID: 1, TransactionID: "tx1", ProcessedAt: &old},
+		{ID: 2, TransactionID: "tx1", ProcessedAt: &old},
+		{ID: 3, TransactionID: "tx2", ProcessedAt: &recent},
+		{ID: 4, TransactionID: "tx3", ProcessedAt: &recent},
+		{ID: 5, TransactionID: "tx4", ProcessedAt: &old},
+		{ID: 6, TransactionID: "dropped", ProcessedAt: &old},
+	}}
+	s.tracker(p).Track()
+
+	if u := s.updates["tx1"]; u.confirmations != 2 || u.blockTime != &blockTime || u.stuck {
+		t.Errorf("tx1 should have 2 confirmations but get %v", u)
+	}
+	if _, ok := s.updates["tx2"]; ok {
+		t.Error("tx2 should not be updated as nothing changes")
+	}
+	if u := s.updates["tx3"]; !u.stuck {
+		t.Errorf("conflicting tx3 should be stuck but get %v", u)
+	}
+	if u := s.updates["tx4"]; !u.stuck || u.confirmations != 0 {
+		t.Errorf("tx4 not mined in time should be stuck but get %v", u)
+	}
+	if u := s.updates["dropped"]; !u.stuck {
+		t.Errorf("transaction not found should be stuck but get %v", u)
+	}
+}
+
+func TestTrackUnstuck(t *testing.T) {
+	p := memory.New(10, "wallet")
+	p.SendBatch(map[string]float64{"a": 1}, "", ignoreSent)
+	p.SetConfirmations("tx1", 1, nil)
+
+	old := time.Now().Add(-2 * time.Hour)
+	s := &mockTrackerStorage{withdrawals: []models.Withdrawal{
+		{ID: 1, TransactionID: "tx1", ProcessedAt: &old, Stuck: true},
+	}}
+	s.tracker(p).Track()
+
+	if u := s.updates["tx1"]; u.stuck || u.confirmations != 1 {
+		t.Errorf("tx1 mined eventually should not be stuck but get %v", u)
+	}
+}
+
+func TestTrackError(t *testing.T) {
+	p := memory.New(10, "wallet")
+	s := &mockTrackerStorage{err: errors.New("db down")}
+	s.tracker(p).Track()
+
+	if len(s.updates) != 0 {
+		t.Errorf("nothing should be updated but get %v", s.updates)
+	}
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetAchievements gets all achievements
+func (s Storage) GetAchievements() (models.Achievements, error) {
+	achievements := models.Achievements{}
+	err := s.db.Select(&achievements, "SELECT * FROM achievements ORDER BY `id` ASC")
+
+	if err != nil {
+		return nil, fmt.Errorf("query achievements error: %v", err)
+	}
+
+	return achievements, nil
+}
+
+// GetUserAchievements gets achievements unlocked by user
+func (s Storage) GetUserAchievements(userID int64) ([]models.UserAchievement, error) {
+	rawSQL := "SELECT * FROM user_achievements WHERE `user_id` = ? ORDER BY `id` ASC"
+	achievements := []models.UserAchievement{}
+	err := s.selects(&achievements, rawSQL, userID)
+	return achievements, err
+}
+
+// GetAchievementStats gets user's current value of every achievement metric
+func (s Storage) GetAchievementStats(userID int64) (models.AchievementStats, error) {
+	rawSQL := "SELECT " +
+		"(SELECT COUNT(*) FROM incomes WHERE `user_id` = ? AND `type` = ?), " +
+		"(SELECT COUNT(*) FROM incomes WHERE `user_id` = ? AND `type` NOT IN (?, ?, ?) AND `status` != ?), " +
+		"(SELECT COUNT(*) FROM users WHERE `referer_id` = ? AND `status` = ?), " +
+		"(SELECT COUNT(*) FROM withdrawals WHERE `user_id` = ? AND `status` NOT IN (?, ?))"
+	args := []interface{}{
+		userID, models.IncomeTypeReward,
+		userID, models.IncomeTypeReward, models.IncomeTypeAchievement, models.IncomeTypeAdmin, models.IncomeStatusChargeback,
+		userID, models.UserStatusVerified,
+		userID, models.WithdrawalStatusRejected, models.WithdrawalStatusFailed,
+	}
+
+	var rewards, offerwalls, verifiedReferees, withdrawals int64
+	if err := s.db.QueryRowx(rawSQL, args...).Scan(&rewards, &offerwalls, &verifiedReferees, &withdrawals); err != nil {
+		return nil, fmt.Errorf("query achievement stats error: %v", err)
+	}
+
+	return models.AchievementStats{
+		models.AchievementMetricRewards:          rewards,
+		models.AchievementMetricOfferwalls:       offerwalls,
+		models.AchievementMetricVerifiedReferees: verifiedReferees,
+		models.AchievementMetricWithdrawals:      withdrawals,
+	}, nil
+}
+
+// UnlockAchievement unlocks achievement for user and credits its bonus in currency given,
+// unlocked is false if the achievement has already been unlocked
+func (s Storage) UnlockAchievement(userID int64, achievement models.Achievement, currency string) (unlocked bool, err error) {
+	tx := s.db.MustBegin()
+
+	if unlocked, err = unlockAchievementWithTx(tx, userID, achievement, currency); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("unlock achievement commit transaction error: %v", err)
+	}
+
+	return unlocked, nil
+}
+
+func unlockAchievementWithTx(tx *sqlx.Tx, userID int64, achievement models.Achievement, currency string) (bool, error) {
+	// unique index on user_id and achievement_id makes unlocking idempotent
+	result, err := tx.Exec("INSERT IGNORE INTO user_achievements (`user_id`, `achievement_id`) VALUES (?, ?)", userID, achievement.ID)
+	if err != nil {
+		return false, fmt.Errorf("insert user achievement error: %v", err)
+	}
+	if rowAffected, _ := result.RowsAffected(); rowAffected != 1 {
+		return false, nil
+	}
+
+	if achievement.Bonus <= 0 {
+		return true, nil
+	}
+
+	// credit bonus as achievement income, referer earns nothing from it
+	income := models.Income{
+		UserID:   userID,
+		Type:     models.IncomeTypeAchievement,
+		Currency: currency,
+		Income:   achievement.Bonus,
+	}
+	if _, err := addIncome(tx, income); err != nil {
+		return false, err
+	}
+	if err := incrementUserBalance(tx, userID, currency, income.Income, 0); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
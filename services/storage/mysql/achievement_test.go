@@ -0,0 +1,78 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestGetAchievements(t *testing.T) {
+	Convey("Given mysql storage with default achievements", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When get achievements", func() {
+			achievements, _ := s.GetAchievements()
+
+			Convey("Achievements should be sorted by id", func() {
+				So(len(achievements), ShouldEqual, 6)
+				So(achievements[0].Name, ShouldEqual, "first_reward")
+			})
+		})
+	})
+
+	withClosedConn(t, "When get achievements", func(s Storage) error {
+		_, err := s.GetAchievements()
+		return err
+	})
+}
+
+func TestGetAchievementStats(t *testing.T) {
+	Convey("Given mysql storage with user activities", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e1", Address: "a1"}, "btc")
+		s.CreateUser(models.User{Email: "e2", Address: "a2", RefererID: 1}, "btc")
+		s.UpdateUserStatus(2, models.UserStatusVerified)
+		s.CreateRewardIncome(models.Income{UserID: 1, Type: models.IncomeTypeReward, Income: 10}, time.Now())
+		s.CreatePtcwallIncome(models.Income{UserID: 1, Type: models.IncomeTypePtcwall, Income: 10})
+
+		Convey("When get achievement stats", func() {
+			stats, _ := s.GetAchievementStats(1)
+
+			Convey("Stats should be correct", func() {
+				So(stats[models.AchievementMetricRewards], ShouldEqual, 1)
+				So(stats[models.AchievementMetricOfferwalls], ShouldEqual, 1)
+				So(stats[models.AchievementMetricVerifiedReferees], ShouldEqual, 1)
+				So(stats[models.AchievementMetricWithdrawals], ShouldEqual, 0)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get achievement stats", func(s Storage) error {
+		_, err := s.GetAchievementStats(1)
+		return err
+	})
+}
+
+func TestUnlockAchievement(t *testing.T) {
+	Convey("Given mysql storage with user", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e1", Address: "a1"}, "btc")
+		achievement := models.Achievement{ID: 2, Bonus: 1}
+
+		Convey("When unlock achievement twice", func() {
+			unlocked1, _ := s.UnlockAchievement(1, achievement, "btc")
+			unlocked2, _ := s.UnlockAchievement(1, achievement, "btc")
+			balance, _ := s.GetUserBalance(1, "btc")
+			achievements, _ := s.GetUserAchievements(1)
+
+			Convey("Achievement should be unlocked and credited only once", func() {
+				So(unlocked1, ShouldBeTrue)
+				So(unlocked2, ShouldBeFalse)
+				So(balance.Balance, ShouldEqual, 1)
+				So(len(achievements), ShouldEqual, 1)
+			})
+		})
+	})
+}
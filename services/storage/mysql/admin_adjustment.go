@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// CreateAdminAdjustment credits or deducts user balance of currency as admin type income,
+// deduction fails with ErrInsufficientBalance if user balance is not enough
+func (s Storage) CreateAdminAdjustment(adjustment models.AdminAdjustment) error {
+	tx := s.db.MustBegin()
+
+	if err := createAdminAdjustmentWithTx(tx, adjustment); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("create admin adjustment commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func createAdminAdjustmentWithTx(tx *sqlx.Tx, adjustment models.AdminAdjustment) error {
+	// user must exist before income is recorded
+	if _, err := getUserStatusForUpdate(tx, adjustment.UserID); err != nil {
+		return err
+	}
+
+	// referer earns nothing from adjustment
+	income := models.Income{
+		UserID:   adjustment.UserID,
+		Type:     models.IncomeTypeAdmin,
+		Currency: adjustment.Currency,
+		Income:   adjustment.Amount,
+	}
+	incomeID, err := addIncome(tx, income)
+	if err != nil {
+		return err
+	}
+
+	if adjustment.Amount < 0 {
+		err = deductUserBalanceBy(tx, adjustment.UserID, adjustment.Currency, -adjustment.Amount)
+	} else {
+		err = incrementUserBalance(tx, adjustment.UserID, adjustment.Currency, adjustment.Amount, 0)
+	}
+	if err != nil {
+		return err
+	}
+
+	rawSQL := "INSERT INTO admin_adjustments (`user_id`, `income_id`, `currency`, `amount`, `reason`) VALUES (?, ?, ?, ?, ?)"
+	if _, err := tx.Exec(rawSQL, adjustment.UserID, incomeID, adjustment.Currency, adjustment.Amount, adjustment.Reason); err != nil {
+		return fmt.Errorf("insert admin adjustment error: %v", err)
+	}
+
+	return nil
+}
+
+// GetAdminAdjustments gets user's admin adjustments, latest first
+func (s Storage) GetAdminAdjustments(userID int64) ([]models.AdminAdjustment, error) {
+	rawSQL := "SELECT * FROM admin_adjustments WHERE `user_id` = ? ORDER BY `id` DESC"
+	dest := []models.AdminAdjustment{}
+	err := s.selects(&dest, rawSQL, userID)
+	return dest, err
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestCreateAdminAdjustment(t *testing.T) {
+	Convey("Given mysql storage with user", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b", RefererID: 2}, "btc")
+
+		Convey("When adjust balance of non-existing user", func() {
+			err := s.CreateAdminAdjustment(models.AdminAdjustment{UserID: 3, Amount: 1, Currency: "btc", Reason: "r"})
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+
+		Convey("When credit user", func() {
+			err := s.CreateAdminAdjustment(models.AdminAdjustment{UserID: 1, Amount: 10, Currency: "btc", Reason: "compensation"})
+			user, _ := s.GetUserByID(1)
+			balance, _ := s.GetUserBalance(1, "btc")
+			incomes, _ := s.GetIncomes(1, 10, 0)
+			adjustments, _ := s.GetAdminAdjustments(1)
+
+			Convey("Balance should be credited as admin income", func() {
+				So(err, ShouldBeNil)
+				So(balance.Balance, ShouldEqual, 10)
+				So(user.XP, ShouldEqual, 0)
+				So(len(incomes), ShouldEqual, 1)
+				So(incomes[0].Type, ShouldEqual, models.IncomeTypeAdmin)
+				So(len(adjustments), ShouldEqual, 1)
+				So(adjustments[0].IncomeID, ShouldEqual, incomes[0].ID)
+				So(adjustments[0].Reason, ShouldEqual, "compensation")
+			})
+
+			Convey("When deduct more than balance", func() {
+				err := s.CreateAdminAdjustment(models.AdminAdjustment{UserID: 1, Amount: -11, Currency: "btc", Reason: "fraud"})
+				count, _ := s.GetNumberOfIncomes(1)
+
+				Convey("Error should be ErrInsufficientBalance and nothing recorded", func() {
+					So(err, ShouldEqual, errors.ErrInsufficientBalance)
+					So(count, ShouldEqual, 1)
+				})
+			})
+
+			Convey("When deduct user", func() {
+				s.CreateAdminAdjustment(models.AdminAdjustment{UserID: 1, Amount: -4, Currency: "btc", Reason: "fraud"})
+				balance, _ := s.GetUserBalance(1, "btc")
+
+				Convey("Balance should be deducted", func() {
+					So(balance.Balance, ShouldEqual, 6)
+				})
+			})
+		})
+	})
+
+	withClosedConn(t, "When get admin adjustments", func(s Storage) error {
+		_, err := s.GetAdminAdjustments(1)
+		return err
+	})
+}
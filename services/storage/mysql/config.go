@@ -18,3 +18,34 @@ func (s Storage) GetLatestConfig() (models.Config, error) {
 
 	return result, nil
 }
+
+// GetConfigs gets config versions, latest first
+func (s Storage) GetConfigs(limit, offset int64) ([]models.Config, error) {
+	configs := []models.Config{}
+	err := s.selects(&configs, "SELECT * FROM configs ORDER BY `id` DESC LIMIT ? OFFSET ?", limit, offset)
+	return configs, err
+}
+
+// GetNumberOfConfigs gets number of config versions
+func (s Storage) GetNumberOfConfigs() (int64, error) {
+	var count int64
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM configs").Scan(&count)
+	return count, err
+}
+
+// CreateConfig creates a new config version, configs table is append-only, returns id of config created
+func (s Storage) CreateConfig(config models.Config) (int64, error) {
+	rawSQL := "INSERT INTO configs (`total_reward_threshold`, `referer_reward_rate`, `min_withdrawal_amount`, `withdrawal_interval`, `withdrawal_fee`, `review_amount`, `review_account_days`, `review_address_days`) " +
+		"VALUES (:total_reward_threshold, :referer_reward_rate, :min_withdrawal_amount, :withdrawal_interval, :withdrawal_fee, :review_amount, :review_account_days, :review_address_days)"
+	result, err := s.db.NamedExec(rawSQL, config)
+	if err != nil {
+		return 0, fmt.Errorf("create config error: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get created config id error: %v", err)
+	}
+
+	return id, nil
+}
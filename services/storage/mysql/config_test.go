@@ -37,3 +37,34 @@ func TestGetLatestConfig(t *testing.T) {
 		return err
 	})
 }
+
+func TestCreateConfig(t *testing.T) {
+	Convey("Given mysql storage with default config", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When create config", func() {
+			id, err := s.CreateConfig(models.Config{TotalRewardThreshold: 20, RefererRewardRate: 0.2, MinWithdrawalAmount: 1, WithdrawalInterval: 3600, WithdrawalFee: 0.1, ReviewAmount: 5, ReviewAccountDays: 7})
+			latest, _ := s.GetLatestConfig()
+			configs, _ := s.GetConfigs(10, 0)
+			count, _ := s.GetNumberOfConfigs()
+
+			Convey("New config should be the latest version", func() {
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, latest.ID)
+				So(latest.TotalRewardThreshold, ShouldEqual, 20)
+				So(latest.WithdrawalInterval, ShouldEqual, 3600)
+				So(latest.WithdrawalFee, ShouldEqual, 0.1)
+				So(latest.ReviewAmount, ShouldEqual, 5)
+				So(latest.ReviewAccountDays, ShouldEqual, 7)
+				So(len(configs), ShouldEqual, 2)
+				So(configs[0].ID, ShouldEqual, latest.ID)
+				So(count, ShouldEqual, 2)
+			})
+		})
+	})
+
+	withClosedConn(t, "When create config", func(s Storage) error {
+		_, err := s.CreateConfig(models.Config{})
+		return err
+	})
+}
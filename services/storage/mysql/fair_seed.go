@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetActiveFairSeed gets user's active fair seed
+func (s Storage) GetActiveFairSeed(userID int64) (models.FairSeed, error) {
+	seed := models.FairSeed{}
+	err := s.db.Get(&seed, "SELECT * FROM fair_seeds WHERE `user_id` = ? AND `status` = ?", userID, models.FairSeedStatusActive)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return seed, errors.ErrNotFound
+		}
+
+		return seed, fmt.Errorf("query active fair seed error: %v", err)
+	}
+
+	return seed, nil
+}
+
+// RotateFairSeed reveals user's active fair seed and activates the new one,
+// revealed seed is empty if user has no active seed
+func (s Storage) RotateFairSeed(seed models.FairSeed) (revealed models.FairSeed, err error) {
+	tx := s.db.MustBegin()
+
+	if revealed, err = rotateFairSeedWithTx(tx, seed); err != nil {
+		tx.Rollback()
+		return models.FairSeed{}, err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return models.FairSeed{}, fmt.Errorf("rotate fair seed commit transaction error: %v", err)
+	}
+
+	return revealed, nil
+}
+
+func rotateFairSeedWithTx(tx *sqlx.Tx, seed models.FairSeed) (models.FairSeed, error) {
+	revealed, err := getActiveFairSeedForUpdate(tx, seed.UserID)
+	switch err {
+	case nil:
+		if _, err := tx.Exec("UPDATE fair_seeds SET `status` = ?, `revealed_at` = NOW() WHERE `id` = ?", models.FairSeedStatusRevealed, revealed.ID); err != nil {
+			return revealed, fmt.Errorf("reveal fair seed error: %v", err)
+		}
+		revealed.Status = models.FairSeedStatusRevealed
+	case errors.ErrNotFound:
+	default:
+		return revealed, err
+	}
+
+	rawSQL := "INSERT INTO fair_seeds (`user_id`, `server_seed`, `server_seed_hash`, `client_seed`) VALUES (:user_id, :server_seed, :server_seed_hash, :client_seed)"
+	if _, err := tx.NamedExec(rawSQL, seed); err != nil {
+		return revealed, fmt.Errorf("insert fair seed error: %v", err)
+	}
+
+	return revealed, nil
+}
+
+// use nonce of fair roll and record the roll, the roll is rejected if its seed is no longer active
+// or its nonce has been used by another roll since the seed was read
+func createFairRollWithTx(tx *sqlx.Tx, userID int64, roll models.FairRoll) error {
+	seed, err := getActiveFairSeedForUpdate(tx, userID)
+	if err == errors.ErrNotFound {
+		return errors.ErrFairSeedChanged
+	}
+	if err != nil {
+		return err
+	}
+	if seed.ID != roll.FairSeedID || seed.Nonce != roll.Nonce {
+		return errors.ErrFairSeedChanged
+	}
+
+	if _, err := tx.Exec("UPDATE fair_seeds SET `nonce` = `nonce` + 1 WHERE `id` = ?", seed.ID); err != nil {
+		return fmt.Errorf("increment fair seed nonce error: %v", err)
+	}
+
+	rawSQL := "INSERT INTO fair_rolls (`fair_seed_id`, `nonce`, `currency`, `reward_rates`, `reward`) VALUES (:fair_seed_id, :nonce, :currency, :reward_rates, :reward)"
+	if _, err := tx.NamedExec(rawSQL, roll); err != nil {
+		return fmt.Errorf("create fair roll error: %v", err)
+	}
+
+	return nil
+}
+
+// lock user's active fair seed
+func getActiveFairSeedForUpdate(tx *sqlx.Tx, userID int64) (models.FairSeed, error) {
+	seed := models.FairSeed{}
+	err := tx.Get(&seed, "SELECT * FROM fair_seeds WHERE `user_id` = ? AND `status` = ? FOR UPDATE", userID, models.FairSeedStatusActive)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return seed, errors.ErrNotFound
+		}
+
+		return seed, fmt.Errorf("query active fair seed error: %v", err)
+	}
+
+	return seed, nil
+}
+
+// GetFairRoll gets fair roll with nonce of fair seed committed by server seed hash
+func (s Storage) GetFairRoll(serverSeedHash string, nonce int64) (models.FairRoll, error) {
+	roll := models.FairRoll{}
+	rawSQL := "SELECT r.* FROM fair_rolls r JOIN fair_seeds s ON s.`id` = r.`fair_seed_id` WHERE s.`server_seed_hash` = ? AND r.`nonce` = ?"
+	err := s.db.Get(&roll, rawSQL, serverSeedHash, nonce)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return roll, errors.ErrNotFound
+		}
+
+		return roll, fmt.Errorf("query fair roll error: %v", err)
+	}
+
+	return roll, nil
+}
@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestGetActiveFairSeed(t *testing.T) {
+	Convey("Given empty mysql storage", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When get active fair seed", func() {
+			_, err := s.GetActiveFairSeed(1)
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get active fair seed", func(s Storage) error {
+		_, err := s.GetActiveFairSeed(1)
+		return err
+	})
+}
+
+func TestRotateFairSeed(t *testing.T) {
+	Convey("Given mysql storage with active fair seed", t, func() {
+		s := prepareDatabaseForTesting()
+		s.RotateFairSeed(models.FairSeed{UserID: 1, ServerSeed: "s1", ServerSeedHash: "h1", ClientSeed: "c1"})
+
+		Convey("When rotate fair seed", func() {
+			revealed, _ := s.RotateFairSeed(models.FairSeed{UserID: 1, ServerSeed: "s2", ServerSeedHash: "h2", ClientSeed: "c2"})
+			active, _ := s.GetActiveFairSeed(1)
+
+			Convey("Previous seed should be revealed and new seed should be active", func() {
+				So(revealed.ServerSeed, ShouldEqual, "s1")
+				So(revealed.Status, ShouldEqual, models.FairSeedStatusRevealed)
+				So(active.ServerSeed, ShouldEqual, "s2")
+				So(active.Nonce, ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestCreateFairRewardIncome(t *testing.T) {
+	Convey("Given mysql storage with active fair seed", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e1", Address: "b1"}, "btc")
+		s.RotateFairSeed(models.FairSeed{UserID: 1, ServerSeed: "s1", ServerSeedHash: "h1", ClientSeed: "c1"})
+		seed, _ := s.GetActiveFairSeed(1)
+		roll := models.FairRoll{FairSeedID: seed.ID, Nonce: seed.Nonce, Currency: "btc", RewardRates: "[]", Reward: 1.5}
+
+		Convey("When create fair reward income", func() {
+			err := s.CreateFairRewardIncome(income(1, 0, 1.5, 0), time.Now(), roll)
+			active, _ := s.GetActiveFairSeed(1)
+			balance, _ := s.GetUserBalance(1, "btc")
+
+			Convey("Nonce should be used and income should be created", func() {
+				So(err, ShouldBeNil)
+				So(active.Nonce, ShouldEqual, 1)
+				So(balance.Balance, ShouldEqual, 1.5)
+			})
+		})
+
+		Convey("When create fair reward income with used nonce", func() {
+			s.CreateFairRewardIncome(income(1, 0, 1.5, 0), time.Now(), roll)
+			err := s.CreateFairRewardIncome(income(1, 0, 1.5, 0), time.Now(), roll)
+			active, _ := s.GetActiveFairSeed(1)
+			balance, _ := s.GetUserBalance(1, "btc")
+
+			Convey("Error should be ErrFairSeedChanged and nothing should be recorded", func() {
+				So(err, ShouldEqual, errors.ErrFairSeedChanged)
+				So(active.Nonce, ShouldEqual, 1)
+				So(balance.Balance, ShouldEqual, 1.5)
+			})
+		})
+
+		Convey("When create fair reward income of user not found", func() {
+			s.RotateFairSeed(models.FairSeed{UserID: 2, ServerSeed: "s2", ServerSeedHash: "h2", ClientSeed: "c2"})
+			seed, _ := s.GetActiveFairSeed(2)
+			err := s.CreateFairRewardIncome(income(2, 0, 1.5, 0), time.Now(), models.FairRoll{FairSeedID: seed.ID, Currency: "btc", RewardRates: "[]"})
+			active, _ := s.GetActiveFairSeed(2)
+			_, rollErr := s.GetFairRoll("h2", 0)
+
+			Convey("Nonce should not be used and roll should not be recorded", func() {
+				So(err, ShouldNotBeNil)
+				So(active.Nonce, ShouldEqual, 0)
+				So(rollErr, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+	})
+}
+
+func TestGetFairRoll(t *testing.T) {
+	Convey("Given mysql storage with fair roll", t, func() {
+		s := prepareDatabaseForTesting()
+		s.RotateFairSeed(models.FairSeed{UserID: 1, ServerSeed: "s1", ServerSeedHash: "h1", ClientSeed: "c1"})
+		s.CreateUser(models.User{Email: "e1", Address: "b1"}, "btc")
+		seed, _ := s.GetActiveFairSeed(1)
+		s.CreateFairRewardIncome(income(1, 0, 1.5, 0), time.Now(), models.FairRoll{FairSeedID: seed.ID, Nonce: seed.Nonce, Currency: "btc", RewardRates: "[]", Reward: 1.5})
+
+		Convey("When get fair roll", func() {
+			roll, err := s.GetFairRoll("h1", 0)
+
+			Convey("Fair roll should be recorded", func() {
+				So(err, ShouldBeNil)
+				So(roll.Reward, ShouldEqual, 1.5)
+				So(roll.RewardRates, ShouldEqual, "[]")
+			})
+		})
+
+		Convey("When get fair roll of unused nonce", func() {
+			_, err := s.GetFairRoll("h1", 1)
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+	})
+}
@@ -35,8 +35,8 @@ func (s Storage) GetNumberOfRewardIncomes(userID int64) (int64, error) {
 
 // GetOfferwallIncomes get user's offerwall incomes
 func (s Storage) GetOfferwallIncomes(userID int64, limit, offset int64) ([]models.Income, error) {
-	rawSQL := "SELECT * FROM incomes WHERE `user_id` = ? AND `type` != ? ORDER BY `id` DESC LIMIT ? OFFSET ?"
-	args := []interface{}{userID, models.IncomeTypeReward, limit, offset}
+	rawSQL := "SELECT * FROM incomes WHERE `user_id` = ? AND `type` NOT IN (?, ?, ?) ORDER BY `id` DESC LIMIT ? OFFSET ?"
+	args := []interface{}{userID, models.IncomeTypeReward, models.IncomeTypeAchievement, models.IncomeTypeAdmin, limit, offset}
 	incomes := []models.Income{}
 	err := s.selects(&incomes, rawSQL, args...)
 	return incomes, err
@@ -45,7 +45,23 @@ func (s Storage) GetOfferwallIncomes(userID int64, limit, offset int64) ([]model
 // GetNumberOfOfferwallIncomes gets number of user's offerwall incomes
 func (s Storage) GetNumberOfOfferwallIncomes(userID int64) (int64, error) {
 	var count int64
-	err := s.db.QueryRowx("SELECT COUNT(*) FROM `incomes` WHERE `user_id` = ? AND `type` != ?", userID, models.IncomeTypeReward).Scan(&count)
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM `incomes` WHERE `user_id` = ? AND `type` NOT IN (?, ?, ?)", userID, models.IncomeTypeReward, models.IncomeTypeAchievement, models.IncomeTypeAdmin).Scan(&count)
+	return count, err
+}
+
+// GetIncomes gets user's incomes of all types
+func (s Storage) GetIncomes(userID int64, limit, offset int64) ([]models.Income, error) {
+	rawSQL := "SELECT * FROM incomes WHERE `user_id` = ? ORDER BY `id` DESC LIMIT ? OFFSET ?"
+	args := []interface{}{userID, limit, offset}
+	incomes := []models.Income{}
+	err := s.selects(&incomes, rawSQL, args...)
+	return incomes, err
+}
+
+// GetNumberOfIncomes gets number of user's incomes of all types
+func (s Storage) GetNumberOfIncomes(userID int64) (int64, error) {
+	var count int64
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM `incomes` WHERE `user_id` = ?", userID).Scan(&count)
 	return count, err
 }
 
@@ -66,24 +82,61 @@ func (s Storage) CreateRewardIncome(income models.Income, now time.Time) error {
 	return nil
 }
 
+// CreateFairRewardIncome creates a new reward type income rolled with user's fair seed,
+// nonce of the seed is used and the roll is recorded only if the income is created
+func (s Storage) CreateFairRewardIncome(income models.Income, now time.Time, roll models.FairRoll) error {
+	tx := s.db.MustBegin()
+
+	if err := createFairRollWithTx(tx, income.UserID, roll); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := createRewardIncomeWithTx(tx, income, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("create fair reward income commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
 func createRewardIncomeWithTx(tx *sqlx.Tx, income models.Income, now time.Time) error {
 	totalReward := income.Income
 
-	_, rowAffected, err := commonBatchOperation(tx, income)
+	_, refererCredited, err := commonBatchOperation(tx, income)
 	if err != nil {
 		return err
 	}
-	if rowAffected == 1 {
+	if refererCredited {
 		totalReward += income.RefererIncome
 	}
 
-	// update user rewarded_at
-	if _, err := tx.Exec("UPDATE users SET `rewarded_at` = ? WHERE `id` = ?", now, income.UserID); err != nil {
-		return err
+	// update user streak_days and rewarded_at, streak counts claims in any currency,
+	// days are counted by models.User.NextStreak in UTC instead of session timezone of mysql
+	user := models.User{}
+	rawSQL := "SELECT `streak_days`, `rewarded_at` FROM users WHERE `id` = ? FOR UPDATE"
+	if err := tx.Get(&user, rawSQL, income.UserID); err != nil {
+		return fmt.Errorf("query user streak error: %v", err)
+	}
+
+	rawSQL = "UPDATE users SET `streak_days` = ?, `rewarded_at` = ? WHERE `id` = ?"
+	if _, err := tx.Exec(rawSQL, user.NextStreak(now), now, income.UserID); err != nil {
+		return fmt.Errorf("update user streak error: %v", err)
+	}
+
+	// update rewarded_at of currency claimed, balance row is created by commonBatchOperation
+	rawSQL = "UPDATE user_balances SET `rewarded_at` = ? WHERE `user_id` = ? AND `currency` = ?"
+	if _, err := tx.Exec(rawSQL, now, income.UserID, income.Currency); err != nil {
+		return fmt.Errorf("update user balance rewarded_at error: %v", err)
 	}
 
 	// update total reward
-	if err := incrementTotalReward(tx, totalReward, now); err != nil {
+	if err := incrementTotalReward(tx, income.Currency, totalReward, now); err != nil {
 		return err
 	}
 
@@ -422,7 +475,7 @@ func createPtcwallIncomeWithTx(tx *sqlx.Tx, income models.Income) error {
 }
 
 // add income, update user, update referer
-func commonBatchOperation(tx *sqlx.Tx, income models.Income) (incomeID, updateRefererBalanceRowsAffected int64, err error) {
+func commonBatchOperation(tx *sqlx.Tx, income models.Income) (incomeID int64, refererCredited bool, err error) {
 	// insert income into incomes table
 	incomeID, err = addIncome(tx, income)
 	if err != nil {
@@ -430,13 +483,12 @@ func commonBatchOperation(tx *sqlx.Tx, income models.Income) (incomeID, updateRe
 	}
 
 	// update user balance, total_income, referer_total_income
-	if err = incrementUserBalance(tx, income.UserID, income.Income, income.RefererIncome); err != nil {
+	if err = incrementUserBalance(tx, income.UserID, income.Currency, income.Income, income.RefererIncome); err != nil {
 		return
 	}
 
 	// update referer balance
-	updateRefererBalanceRowsAffected, err = incrementRefererBalance(tx, income.RefererID, income.RefererIncome)
-	if _, err = incrementRefererBalance(tx, income.RefererID, income.RefererIncome); err != nil {
+	if refererCredited, err = incrementRefererBalance(tx, income.RefererID, income.Currency, income.RefererIncome); err != nil {
 		return
 	}
 
@@ -445,12 +497,12 @@ func commonBatchOperation(tx *sqlx.Tx, income models.Income) (incomeID, updateRe
 
 // insert reward income into incomes table
 func addIncome(tx *sqlx.Tx, income models.Income) (int64, error) {
-	sql := "INSERT INTO incomes (`user_id`, `referer_id`, `type`, `income`, `referer_income`) VALUES (:user_id, :referer_id, :type, :income, :referer_income)"
+	sql := "INSERT INTO incomes (`user_id`, `referer_id`, `type`, `currency`, `income`, `referer_income`) VALUES (:user_id, :referer_id, :type, :currency, :income, :referer_income)"
 
 	// pending offerwall income
-	if income.Type != models.IncomeTypeReward {
+	if income.IsOfferwall() {
 		income.Status = models.IncomeStatusPending
-		sql = "INSERT INTO incomes (`user_id`, `referer_id`, `type`, `income`, `referer_income`, `status`) VALUES (:user_id, :referer_id, :type, :income, :referer_income, :status)"
+		sql = "INSERT INTO incomes (`user_id`, `referer_id`, `type`, `currency`, `income`, `referer_income`, `status`) VALUES (:user_id, :referer_id, :type, :currency, :income, :referer_income, :status)"
 	}
 
 	result, err := tx.NamedExec(sql, income)
@@ -462,40 +514,62 @@ func addIncome(tx *sqlx.Tx, income models.Income) (int64, error) {
 		return 0, err
 	}
 
+	// user earns xp from reward and offerwall incomes
+	if err := incrementUserXP(tx, income.UserID, income.XP()); err != nil {
+		return 0, err
+	}
+
 	return lastInsertID, nil
 }
 
-// increment user balance, total_income, referer_total_income
-func incrementUserBalance(tx *sqlx.Tx, userID int64, delta, refererDelta float64) error {
-	rawSQL := "UPDATE users SET `balance` = `balance` + ?, `total_income` = `total_income` + ?, `referer_total_income` = `referer_total_income` + ? WHERE id = ?"
-	args := []interface{}{delta, delta, refererDelta, userID}
+// increment user xp
+func incrementUserXP(tx *sqlx.Tx, userID, delta int64) error {
+	if _, err := tx.Exec("UPDATE users SET `xp` = `xp` + ? WHERE id = ?", delta, userID); err != nil {
+		return fmt.Errorf("increment user xp error: %v", err)
+	}
+
+	return nil
+}
+
+// increment user balance, total_income, referer_total_income of currency,
+// balance row is created on first income in the currency
+func incrementUserBalance(tx *sqlx.Tx, userID int64, currency string, delta, refererDelta float64) error {
+	rawSQL := "INSERT INTO user_balances (`user_id`, `currency`, `balance`, `total_income`, `referer_total_income`) " +
+		"SELECT `id`, ?, ?, ?, ? FROM users WHERE `id` = ? ON DUPLICATE KEY UPDATE " +
+		"`user_balances`.`balance` = `user_balances`.`balance` + VALUES(`balance`), " +
+		"`user_balances`.`total_income` = `user_balances`.`total_income` + VALUES(`total_income`), " +
+		"`user_balances`.`referer_total_income` = `user_balances`.`referer_total_income` + VALUES(`referer_total_income`)"
+	args := []interface{}{currency, delta, delta, refererDelta, userID}
 	if result, err := tx.Exec(rawSQL, args...); err != nil {
 		return fmt.Errorf("increment user balance error: %v", err)
-	} else if rowAffected, _ := result.RowsAffected(); rowAffected != 1 {
+	} else if rowAffected, _ := result.RowsAffected(); rowAffected == 0 {
 		return fmt.Errorf("increment user balance affected %v rows", rowAffected)
 	}
 
 	return nil
 }
 
-// increment referer balance
-func incrementRefererBalance(tx *sqlx.Tx, refererID int64, delta float64) (int64, error) {
-	result, err := tx.NamedExec("UPDATE users SET `balance` = `balance` + :delta, `total_income_from_referees` = `total_income_from_referees` + :delta WHERE id = :id", map[string]interface{}{
-		"id":    refererID,
-		"delta": delta,
-	})
+// increment referer balance of currency, false is returned if user has no referer
+func incrementRefererBalance(tx *sqlx.Tx, refererID int64, currency string, delta float64) (bool, error) {
+	rawSQL := "INSERT INTO user_balances (`user_id`, `currency`, `balance`, `total_income_from_referees`) " +
+		"SELECT `id`, ?, ?, ? FROM users WHERE `id` = ? ON DUPLICATE KEY UPDATE " +
+		"`user_balances`.`balance` = `user_balances`.`balance` + VALUES(`balance`), " +
+		"`user_balances`.`total_income_from_referees` = `user_balances`.`total_income_from_referees` + VALUES(`total_income_from_referees`)"
+	result, err := tx.Exec(rawSQL, currency, delta, delta, refererID)
 	if err != nil {
-		return 0, fmt.Errorf("increment referer balance error: %v", err)
+		return false, fmt.Errorf("increment referer balance error: %v", err)
 	}
 
+	// 1 row affected by insert, 2 by update
 	rowAffected, _ := result.RowsAffected()
-	return rowAffected, nil
+	return rowAffected > 0, nil
 }
 
-// increment total reward
-func incrementTotalReward(tx *sqlx.Tx, totalReward float64, now time.Time) error {
-	sql := "INSERT INTO total_rewards (`total`, `created_at`) VALUES (:delta, :created_at) ON DUPLICATE KEY UPDATE `total` = `total` + :delta"
+// increment total reward of currency
+func incrementTotalReward(tx *sqlx.Tx, currency string, totalReward float64, now time.Time) error {
+	sql := "INSERT INTO total_rewards (`currency`, `total`, `created_at`) VALUES (:currency, :delta, :created_at) ON DUPLICATE KEY UPDATE `total` = `total` + :delta"
 	args := map[string]interface{}{
+		"currency":   currency,
 		"delta":      totalReward,
 		"created_at": now,
 	}
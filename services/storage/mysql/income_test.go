@@ -48,7 +48,7 @@ func TestIncrementUserBalance(t *testing.T) {
 		Convey("When increment user balance with commited transaction", func() {
 			tx := s.db.MustBegin()
 			tx.Commit()
-			err := incrementUserBalance(tx, 0, 0, 0)
+			err := incrementUserBalance(tx, 0, "btc", 0, 0)
 			Convey("Error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -58,7 +58,7 @@ func TestIncrementUserBalance(t *testing.T) {
 
 		Convey("When increment user balance affecting 0 row", func() {
 			tx := s.db.MustBegin()
-			err := incrementUserBalance(tx, 0, 0, 0)
+			err := incrementUserBalance(tx, 0, "btc", 0, 0)
 			Convey("Error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -75,7 +75,7 @@ func TestIncrementRefererBalance(t *testing.T) {
 		Convey("When increment referer balance with commited transaction", func() {
 			tx := s.db.MustBegin()
 			tx.Commit()
-			_, err := incrementRefererBalance(tx, 0, 0)
+			_, err := incrementRefererBalance(tx, 0, "btc", 0)
 			Convey("Error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -109,7 +109,7 @@ func TestIncrementTotalReward(t *testing.T) {
 		Convey("When increment total reward with commited transaction", func() {
 			tx := s.db.MustBegin()
 			tx.Commit()
-			err := incrementTotalReward(tx, 10, time.Now())
+			err := incrementTotalReward(tx, "btc", 10, time.Now())
 			Convey("Error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -122,14 +122,34 @@ func TestIncrementTotalReward(t *testing.T) {
 func TestCreateRewardIncome(t *testing.T) {
 	Convey("Given mysql storage with two users", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e1", Address: "b1"})
-		s.CreateUser(models.User{Email: "e2", Address: "b2", RefererID: 1})
+		s.CreateUser(models.User{Email: "e1", Address: "b1"}, "btc")
+		s.CreateUser(models.User{Email: "e2", Address: "b2", RefererID: 1}, "btc")
 
 		Convey("When create reward income", func() {
 			err := s.CreateRewardIncome(income(1, 2, 100, 4), time.Now())
+			balance, _ := s.GetUserBalance(1, "btc")
+			refererBalance, _ := s.GetUserBalance(2, "btc")
 
-			Convey("Error should be nil", func() {
+			Convey("Balance in currency should be credited", func() {
 				So(err, ShouldBeNil)
+				So(balance.Balance, ShouldEqual, 100)
+			})
+
+			Convey("Referer balance in currency should be credited once", func() {
+				So(refererBalance.Balance, ShouldEqual, 4)
+				So(refererBalance.TotalIncomeFromReferees, ShouldEqual, 4)
+			})
+		})
+
+		Convey("When create reward incomes on consecutive days in UTC", func() {
+			now := time.Date(2016, 11, 2, 0, 30, 0, 0, time.UTC)
+			s.CreateRewardIncome(income(1, 2, 100, 4), now.Add(-time.Hour))
+			err := s.CreateRewardIncome(income(1, 2, 100, 4), now)
+			user, _ := s.GetUserByID(1)
+
+			Convey("Streak days should be 2", func() {
+				So(err, ShouldBeNil)
+				So(user.StreakDays, ShouldEqual, 2)
 			})
 		})
 	})
@@ -138,7 +158,7 @@ func TestCreateRewardIncome(t *testing.T) {
 func TestGetRewardIncomes(t *testing.T) {
 	Convey("Given mysql storage", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e1", Address: "b1"})
+		s.CreateUser(models.User{Email: "e1", Address: "b1"}, "btc")
 		rewardedAt := time.Now()
 		s.CreateRewardIncome(income(1, 2, 91, 1), rewardedAt)
 		s.CreateRewardIncome(income(1, 2, 92, 1), rewardedAt)
@@ -167,6 +187,7 @@ func income(userID int64, refererID int64, income float64, refererIncome float64
 		UserID:        userID,
 		RefererID:     refererID,
 		Type:          models.IncomeTypeReward,
+		Currency:      "btc",
 		Income:        income,
 		RefererIncome: refererIncome,
 	}
@@ -202,8 +223,8 @@ func benchmarkCreateRewardIncomeLevel(n int64, b *testing.B) {
 
 func prepareDatabaseForBenchmarkingCreateRewardIncome(n int64) (Storage, error) {
 	s := prepareDatabaseForTesting()
-	s.CreateUser(models.User{Email: "e1", Address: "b1"})
-	s.CreateUser(models.User{Email: "e2", Address: "b2", RefererID: 1})
+	s.CreateUser(models.User{Email: "e1", Address: "b1"}, "btc")
+	s.CreateUser(models.User{Email: "e2", Address: "b2", RefererID: 1}, "btc")
 
 	for i := n / 1000; i > 0; i-- {
 		insertIncomes(s)
@@ -0,0 +1,19 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetLevels gets all levels sorted by min_xp
+func (s Storage) GetLevels() (models.Levels, error) {
+	levels := models.Levels{}
+	err := s.db.Select(&levels, "SELECT * FROM levels ORDER BY `min_xp` ASC")
+
+	if err != nil {
+		return nil, fmt.Errorf("query levels error: %v", err)
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetLevels(t *testing.T) {
+	Convey("Given mysql storage with default levels", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When get levels", func() {
+			levels, _ := s.GetLevels()
+
+			Convey("Levels should be sorted by min xp", func() {
+				So(len(levels), ShouldEqual, 5)
+				So(levels[0].MinXP, ShouldEqual, 0)
+				So(levels[0].RewardMultiplier, ShouldEqual, 1)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get levels", func(s Storage) error {
+		_, err := s.GetLevels()
+		return err
+	})
+}
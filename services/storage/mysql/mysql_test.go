@@ -62,12 +62,17 @@ func TestSelects(t *testing.T) {
 	})
 }
 
-func (s Storage) IncrementTotalReward(now time.Time, delta float64) {
-	sql := "INSERT INTO total_rewards (`total`, `created_at`) VALUES (:delta, :created_at) ON DUPLICATE KEY UPDATE `total` = `total` + :delta"
+func (s Storage) IncrementTotalReward(currency string, now time.Time, delta float64) {
+	sql := "INSERT INTO total_rewards (`currency`, `total`, `created_at`) VALUES (:currency, :delta, :created_at) ON DUPLICATE KEY UPDATE `total` = `total` + :delta"
 	args := map[string]interface{}{
+		"currency":   currency,
 		"delta":      delta,
 		"created_at": now,
 	}
 
 	s.db.NamedExec(sql, args)
 }
+
+func (s Storage) InsertUserBalance(userID int64, currency string, balance float64) {
+	s.db.MustExec("INSERT INTO user_balances (`user_id`, `currency`, `balance`) VALUES (?, ?, ?)", userID, currency, balance)
+}
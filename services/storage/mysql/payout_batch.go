@@ -0,0 +1,199 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// CreatePayoutBatch journals batch to be sent and marks its withdrawals processing,
+// failed batch of same withdrawals is sent again, other existing batch is ErrPayoutBatchExists,
+// withdrawals must be pending or processing in a failed batch
+func (s Storage) CreatePayoutBatch(batchID string, withdrawalIDs []int64) error {
+	tx := s.db.MustBegin()
+
+	if err := createPayoutBatchWithTx(tx, batchID, withdrawalIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("create payout batch commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func createPayoutBatchWithTx(tx *sqlx.Tx, batchID string, withdrawalIDs []int64) error {
+	batch := models.PayoutBatch{}
+	err := tx.Get(&batch, "SELECT * FROM `payout_batches` WHERE `batch_id` = ? FOR UPDATE", batchID)
+	switch {
+	case err == sql.ErrNoRows:
+		if _, err := tx.Exec("INSERT INTO `payout_batches` (`batch_id`, `status`) VALUES (?, ?)", batchID, models.PayoutBatchStatusSending); err != nil {
+			return fmt.Errorf("insert payout batch error: %v", err)
+		}
+	case err != nil:
+		return fmt.Errorf("query payout batch error: %v", err)
+	// sending, sent or in review batch may have paid withdrawals, only batch known to pay nothing is sent again
+	case batch.Status != models.PayoutBatchStatusFailed:
+		return errors.ErrPayoutBatchExists
+	default:
+		if _, err := tx.Exec("UPDATE `payout_batches` SET `status` = ? WHERE `id` = ?", models.PayoutBatchStatusSending, batch.ID); err != nil {
+			return fmt.Errorf("update payout batch status error: %v", err)
+		}
+	}
+
+	// withdrawals being sent again or split after failed batch are processing already
+	rawSQL, args, err := sqlx.In(
+		"UPDATE `withdrawals` SET `status` = ?, `payout_batch_id` = ? WHERE `id` IN (?) AND (`status` = ? OR "+
+			"(`status` = ? AND (`payout_batch_id` = ? OR `payout_batch_id` IN (SELECT `batch_id` FROM `payout_batches` WHERE `status` = ?))))",
+		models.WithdrawalStatusProcessing,
+		batchID,
+		withdrawalIDs,
+		models.WithdrawalStatusPending,
+		models.WithdrawalStatusProcessing,
+		batchID,
+		models.PayoutBatchStatusFailed,
+	)
+	if err != nil {
+		return fmt.Errorf("update withdrawal payout batch build sql with in: %v", err)
+	}
+
+	result, err := tx.Exec(rawSQL, args...)
+	if err != nil {
+		return fmt.Errorf("update withdrawal payout batch error: %v", err)
+	}
+
+	if rowAffected, _ := result.RowsAffected(); rowAffected != int64(len(withdrawalIDs)) {
+		return fmt.Errorf("expected %v but %v rows affected", len(withdrawalIDs), rowAffected)
+	}
+
+	return nil
+}
+
+// UpdatePayoutBatchStatus updates status of batch once its outcome is known
+func (s Storage) UpdatePayoutBatchStatus(batchID string, status int64) error {
+	if _, err := s.db.Exec("UPDATE `payout_batches` SET `status` = ? WHERE `batch_id` = ?", status, batchID); err != nil {
+		return fmt.Errorf("update payout batch status error: %v", err)
+	}
+
+	return nil
+}
+
+// GetUnfinishedPayoutBatches gets batches of currency whose outcome is unknown, oldest first
+func (s Storage) GetUnfinishedPayoutBatches(currency string) ([]models.PayoutBatch, error) {
+	rawSQL := "SELECT * FROM `payout_batches` WHERE `status` = ? AND `batch_id` IN " +
+		"(SELECT `payout_batch_id` FROM `withdrawals` WHERE `currency` = ?) ORDER BY `id` ASC"
+	dest := []models.PayoutBatch{}
+	err := s.selects(&dest, rawSQL, models.PayoutBatchStatusSending, currency)
+	return dest, err
+}
+
+// GetProcessingWithdrawalsByPayoutBatch gets withdrawals of batch still processing
+func (s Storage) GetProcessingWithdrawalsByPayoutBatch(batchID string) ([]models.Withdrawal, error) {
+	rawSQL := "SELECT * FROM `withdrawals` WHERE `payout_batch_id` = ? AND `status` = ? ORDER BY `id` ASC"
+	dest := []models.Withdrawal{}
+	err := s.selects(&dest, rawSQL, batchID, models.WithdrawalStatusProcessing)
+	return dest, err
+}
+
+// GetPayoutBatchesByStatus gets batches with status given, oldest first
+func (s Storage) GetPayoutBatchesByStatus(status int64, limit, offset int64) ([]models.PayoutBatch, error) {
+	rawSQL := "SELECT * FROM `payout_batches` WHERE `status` = ? ORDER BY `id` ASC LIMIT ? OFFSET ?"
+	dest := []models.PayoutBatch{}
+	err := s.selects(&dest, rawSQL, status, limit, offset)
+	return dest, err
+}
+
+// GetNumberOfPayoutBatchesByStatus gets number of batches with status given
+func (s Storage) GetNumberOfPayoutBatchesByStatus(status int64) (int64, error) {
+	var count int64
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM `payout_batches` WHERE `status` = ?", status).Scan(&count)
+	return count, err
+}
+
+// ResolvePayoutBatchWithdrawal resolves processing withdrawal of batch in review after it is checked manually,
+// withdrawal paid is processed with transaction id, unpaid one is put back to pending or failed and refunded with reason,
+// batch is sent once none of its withdrawals is processing, or failed if it has paid none of them
+func (s Storage) ResolvePayoutBatchWithdrawal(batchID string, id int64, status int64, transactionID, reason string) (models.Withdrawal, error) {
+	tx := s.db.MustBegin()
+
+	withdrawal, err := resolvePayoutBatchWithdrawalWithTx(tx, batchID, id, status, transactionID, reason)
+	if err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return withdrawal, fmt.Errorf("resolve payout batch withdrawal commit transaction error: %v", err)
+	}
+
+	return withdrawal, nil
+}
+
+func resolvePayoutBatchWithdrawalWithTx(tx *sqlx.Tx, batchID string, id int64, status int64, transactionID, reason string) (models.Withdrawal, error) {
+	batch := models.PayoutBatch{}
+	err := tx.Get(&batch, "SELECT * FROM `payout_batches` WHERE `batch_id` = ? FOR UPDATE", batchID)
+	switch {
+	case err == sql.ErrNoRows:
+		return models.Withdrawal{}, errors.ErrNotFound
+	case err != nil:
+		return models.Withdrawal{}, fmt.Errorf("query payout batch error: %v", err)
+	case batch.Status != models.PayoutBatchStatusReview:
+		return models.Withdrawal{}, errors.ErrPayoutBatchNotInReview
+	}
+
+	withdrawal, err := getWithdrawalForUpdate(tx, id, models.WithdrawalStatusProcessing, errors.ErrWithdrawalNotProcessing)
+	if err != nil {
+		return withdrawal, err
+	}
+	if withdrawal.PayoutBatchID != batchID {
+		return withdrawal, errors.ErrNotFound
+	}
+
+	switch status {
+	case models.WithdrawalStatusProcessed:
+		rawSQL := "UPDATE `withdrawals` SET `status` = ?, `transaction_id` = ?, `processed_at` = NOW() WHERE `id` = ?"
+		if _, err := tx.Exec(rawSQL, status, transactionID, id); err != nil {
+			return withdrawal, fmt.Errorf("update withdrawal status to processed error: %v", err)
+		}
+		withdrawal.Status = status
+		withdrawal.TransactionID = transactionID
+	case models.WithdrawalStatusPending:
+		if _, err := tx.Exec("UPDATE `withdrawals` SET `status` = ? WHERE `id` = ?", status, id); err != nil {
+			return withdrawal, fmt.Errorf("update withdrawal status to pending error: %v", err)
+		}
+		withdrawal.Status = status
+	case models.WithdrawalStatusFailed:
+		if withdrawal, err = refundWithdrawalWithTx(tx, withdrawal, status, reason); err != nil {
+			return withdrawal, err
+		}
+	default:
+		return withdrawal, fmt.Errorf("withdrawal can not be resolved as status %v", status)
+	}
+
+	// batch is finished once all of its withdrawals are resolved
+	var processing, processed int64
+	rawSQL := "SELECT COALESCE(SUM(`status` = ?), 0), COALESCE(SUM(`status` = ?), 0) FROM `withdrawals` WHERE `payout_batch_id` = ?"
+	if err := tx.QueryRowx(rawSQL, models.WithdrawalStatusProcessing, models.WithdrawalStatusProcessed, batchID).Scan(&processing, &processed); err != nil {
+		return withdrawal, fmt.Errorf("query withdrawals of payout batch error: %v", err)
+	}
+	if processing > 0 {
+		return withdrawal, nil
+	}
+
+	batchStatus := models.PayoutBatchStatusFailed
+	if processed > 0 {
+		batchStatus = models.PayoutBatchStatusSent
+	}
+	if _, err := tx.Exec("UPDATE `payout_batches` SET `status` = ? WHERE `id` = ?", batchStatus, batch.ID); err != nil {
+		return withdrawal, fmt.Errorf("update payout batch status error: %v", err)
+	}
+
+	return withdrawal, nil
+}
@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestPayoutBatch(t *testing.T) {
+	Convey("Given mysql storage with pending withdrawals", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3}, 0)
+
+		Convey("When create payout batch", func() {
+			err := s.CreatePayoutBatch("b1", []int64{1, 2})
+			batches, _ := s.GetUnfinishedPayoutBatches("btc")
+			withdrawals, _ := s.GetProcessingWithdrawalsByPayoutBatch("b1")
+
+			Convey("Batch should be sending with withdrawals processing", func() {
+				So(err, ShouldBeNil)
+				So(len(batches), ShouldEqual, 1)
+				So(batches[0].BatchID, ShouldEqual, "b1")
+				So(len(withdrawals), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When create unfinished payout batch again", func() {
+			s.CreatePayoutBatch("b1", []int64{1, 2})
+			err := s.CreatePayoutBatch("b1", []int64{1, 2})
+
+			Convey("Error should be ErrPayoutBatchExists", func() {
+				So(err, ShouldEqual, errors.ErrPayoutBatchExists)
+			})
+		})
+
+		Convey("When create failed payout batch again", func() {
+			s.CreatePayoutBatch("b1", []int64{1, 2})
+			s.UpdatePayoutBatchStatus("b1", models.PayoutBatchStatusFailed)
+			err := s.CreatePayoutBatch("b1", []int64{1, 2})
+			batches, _ := s.GetUnfinishedPayoutBatches("btc")
+
+			Convey("Batch should be sending again", func() {
+				So(err, ShouldBeNil)
+				So(len(batches), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When create payout batch in review again", func() {
+			s.CreatePayoutBatch("b1", []int64{1, 2})
+			s.UpdatePayoutBatchStatus("b1", models.PayoutBatchStatusReview)
+			err := s.CreatePayoutBatch("b1", []int64{1, 2})
+
+			Convey("Error should be ErrPayoutBatchExists", func() {
+				So(err, ShouldEqual, errors.ErrPayoutBatchExists)
+			})
+		})
+
+		Convey("When split processing withdrawals of unfinished batch into another batch", func() {
+			s.CreatePayoutBatch("b1", []int64{1, 2})
+			err := s.CreatePayoutBatch("b2", []int64{2})
+			withdrawals, _ := s.GetProcessingWithdrawalsByPayoutBatch("b1")
+
+			Convey("Withdrawal should stay in unfinished batch", func() {
+				So(err, ShouldNotBeNil)
+				So(len(withdrawals), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When split processing withdrawals of failed batch into another batch", func() {
+			s.CreatePayoutBatch("b1", []int64{1, 2})
+			s.UpdatePayoutBatchStatus("b1", models.PayoutBatchStatusFailed)
+			err := s.CreatePayoutBatch("b2", []int64{2})
+			withdrawals, _ := s.GetProcessingWithdrawalsByPayoutBatch("b2")
+
+			Convey("Withdrawal should belong to new batch", func() {
+				So(err, ShouldBeNil)
+				So(len(withdrawals), ShouldEqual, 1)
+				So(withdrawals[0].ID, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When create payout batch with processed withdrawal", func() {
+			s.UpdateWithdrawalStatusToProcessing([]int64{3})
+			s.UpdateWithdrawalStatusToProcessed([]int64{3}, "tx")
+			err := s.CreatePayoutBatch("b3", []int64{1, 3})
+			batches, _ := s.GetUnfinishedPayoutBatches("btc")
+
+			Convey("Batch should not be created", func() {
+				So(err, ShouldNotBeNil)
+				So(batches, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When update withdrawal status to pending", func() {
+			s.CreatePayoutBatch("b1", []int64{1, 2})
+			err := s.UpdateWithdrawalStatusToPending([]int64{1, 2})
+			withdrawals, _ := s.GetPendingWithdrawals("btc")
+
+			Convey("Withdrawals should be pending", func() {
+				So(err, ShouldBeNil)
+				So(len(withdrawals), ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestResolvePayoutBatchWithdrawal(t *testing.T) {
+	Convey("Given mysql storage with payout batch in review", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3}, 0)
+		s.CreatePayoutBatch("b1", []int64{1, 2})
+		s.UpdatePayoutBatchStatus("b1", models.PayoutBatchStatusReview)
+
+		Convey("When get payout batches in review", func() {
+			batches, _ := s.GetPayoutBatchesByStatus(models.PayoutBatchStatusReview, 10, 0)
+			count, _ := s.GetNumberOfPayoutBatchesByStatus(models.PayoutBatchStatusReview)
+
+			Convey("Batch should be in review", func() {
+				So(len(batches), ShouldEqual, 1)
+				So(batches[0].BatchID, ShouldEqual, "b1")
+				So(count, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When resolve one withdrawal as paid", func() {
+			withdrawal, err := s.ResolvePayoutBatchWithdrawal("b1", 1, models.WithdrawalStatusProcessed, "tx", "")
+			withdrawals, _ := s.GetProcessingWithdrawalsByPayoutBatch("b1")
+			count, _ := s.GetNumberOfPayoutBatchesByStatus(models.PayoutBatchStatusReview)
+
+			Convey("Withdrawal should be processed and batch should stay in review", func() {
+				So(err, ShouldBeNil)
+				So(withdrawal.TransactionID, ShouldEqual, "tx")
+				So(len(withdrawals), ShouldEqual, 1)
+				So(count, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When resolve withdrawals as paid and refunded", func() {
+			s.ResolvePayoutBatchWithdrawal("b1", 1, models.WithdrawalStatusProcessed, "tx", "")
+			_, err := s.ResolvePayoutBatchWithdrawal("b1", 2, models.WithdrawalStatusFailed, "", "not paid")
+			balance, _ := s.GetUserBalance(1, "btc")
+			count, _ := s.GetNumberOfPayoutBatchesByStatus(models.PayoutBatchStatusSent)
+
+			Convey("Unpaid withdrawal should be refunded and batch should be sent", func() {
+				So(err, ShouldBeNil)
+				So(balance.Balance, ShouldEqual, 6)
+				So(count, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When resolve all withdrawals as unpaid", func() {
+			s.ResolvePayoutBatchWithdrawal("b1", 1, models.WithdrawalStatusPending, "", "")
+			_, err := s.ResolvePayoutBatchWithdrawal("b1", 2, models.WithdrawalStatusPending, "", "")
+			withdrawals, _ := s.GetPendingWithdrawals("btc")
+			count, _ := s.GetNumberOfPayoutBatchesByStatus(models.PayoutBatchStatusFailed)
+
+			Convey("Withdrawals should be pending and batch should be failed", func() {
+				So(err, ShouldBeNil)
+				So(len(withdrawals), ShouldEqual, 3)
+				So(count, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When resolve withdrawal of another batch", func() {
+			_, err := s.ResolvePayoutBatchWithdrawal("b1", 3, models.WithdrawalStatusPending, "", "")
+
+			Convey("Error should be ErrWithdrawalNotProcessing", func() {
+				So(err, ShouldEqual, errors.ErrWithdrawalNotProcessing)
+			})
+		})
+
+		Convey("When resolve withdrawal of batch not in review", func() {
+			s.UpdatePayoutBatchStatus("b1", models.PayoutBatchStatusSending)
+			_, err := s.ResolvePayoutBatchWithdrawal("b1", 1, models.WithdrawalStatusPending, "", "")
+
+			Convey("Error should be ErrPayoutBatchNotInReview", func() {
+				So(err, ShouldEqual, errors.ErrPayoutBatchNotInReview)
+			})
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+var refereeIncomePeriodExprs = map[string]string{
+	models.RefereeIncomePeriodDay:  "DATE(i.`created_at`)",
+	models.RefereeIncomePeriodWeek: "DATE_SUB(DATE(i.`created_at`), INTERVAL WEEKDAY(i.`created_at`) DAY)", // week starts from monday
+}
+
+var refereeIncomeSortColumns = map[string]string{
+	models.RefereeIncomeSortByPeriod:    "`period`",
+	models.RefereeIncomeSortByAmount:    "`amount`",
+	models.RefereeIncomeSortByRefereeID: "`referee_id`",
+}
+
+// GetRefereeIncomes gets referer commission aggregated per referee per period per currency
+func (s Storage) GetRefereeIncomes(q models.RefereeIncomeQuery, limit, offset int64) ([]models.RefereeIncome, error) {
+	periodExpr, sortColumn, err := refereeIncomeExprs(q)
+	if err != nil {
+		return nil, err
+	}
+
+	direction := "ASC"
+	if q.Desc {
+		direction = "DESC"
+	}
+
+	rawSQL := fmt.Sprintf("SELECT i.`user_id` AS `referee_id`, u.`address`, %s AS `period`, i.`currency`, SUM(i.`referer_income`) AS `amount`, COUNT(*) AS `number_of_incomes` "+
+		"FROM incomes i JOIN users u ON u.`id` = i.`user_id` "+
+		"WHERE i.`referer_id` = ? AND i.`status` != ? AND i.`created_at` >= ? AND i.`created_at` < ? "+
+		"GROUP BY i.`user_id`, u.`address`, `period`, i.`currency` ORDER BY %s %s, `referee_id` ASC LIMIT ? OFFSET ?", periodExpr, sortColumn, direction)
+	args := []interface{}{q.RefererID, models.IncomeStatusChargeback, q.Since, q.Until, limit, offset}
+	dest := []models.RefereeIncome{}
+	err = s.selects(&dest, rawSQL, args...)
+	return dest, err
+}
+
+// GetNumberOfRefereeIncomes gets number of aggregated referee incomes
+func (s Storage) GetNumberOfRefereeIncomes(q models.RefereeIncomeQuery) (int64, error) {
+	periodExpr, _, err := refereeIncomeExprs(q)
+	if err != nil {
+		return 0, err
+	}
+
+	rawSQL := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT i.`user_id`, %s AS `period`, i.`currency` FROM incomes i "+
+		"WHERE i.`referer_id` = ? AND i.`status` != ? AND i.`created_at` >= ? AND i.`created_at` < ? "+
+		"GROUP BY i.`user_id`, `period`, i.`currency`) t", periodExpr)
+	args := []interface{}{q.RefererID, models.IncomeStatusChargeback, q.Since, q.Until}
+
+	var count int64
+	err = s.db.QueryRowx(rawSQL, args...).Scan(&count)
+	return count, err
+}
+
+// whitelist period and sort column, they are interpolated into sql
+func refereeIncomeExprs(q models.RefereeIncomeQuery) (periodExpr, sortColumn string, err error) {
+	periodExpr, ok := refereeIncomePeriodExprs[q.Period]
+	if !ok {
+		return "", "", fmt.Errorf("invalid referee income period %v", q.Period)
+	}
+
+	sortColumn, ok = refereeIncomeSortColumns[q.SortBy]
+	if !ok {
+		return "", "", fmt.Errorf("invalid referee income sort by %v", q.SortBy)
+	}
+
+	return periodExpr, sortColumn, nil
+}
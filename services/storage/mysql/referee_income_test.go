@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestGetRefereeIncomes(t *testing.T) {
+	Convey("Given mysql storage with referee incomes", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e1", Address: "a1"}, "btc")
+		s.CreateUser(models.User{Email: "e2", Address: "a2", RefererID: 1}, "btc")
+		s.CreateUser(models.User{Email: "e3", Address: "a3", RefererID: 1}, "btc")
+		now := time.Now()
+		s.CreateRewardIncome(models.Income{UserID: 2, RefererID: 1, Income: 10, RefererIncome: 1}, now)
+		s.CreateRewardIncome(models.Income{UserID: 2, RefererID: 1, Income: 10, RefererIncome: 1}, now)
+		s.CreateRewardIncome(models.Income{UserID: 3, RefererID: 1, Income: 30, RefererIncome: 3}, now)
+
+		q := models.RefereeIncomeQuery{
+			RefererID: 1,
+			Since:     now.Add(-24 * time.Hour),
+			Until:     now.Add(24 * time.Hour),
+			Period:    models.RefereeIncomePeriodDay,
+			SortBy:    models.RefereeIncomeSortByAmount,
+			Desc:      true,
+		}
+
+		Convey("When get referee incomes sorted by amount", func() {
+			incomes, _ := s.GetRefereeIncomes(q, 10, 0)
+
+			Convey("Referee incomes should be aggregated per referee", func() {
+				So(len(incomes), ShouldEqual, 2)
+				So(incomes[0].RefereeID, ShouldEqual, 3)
+				So(incomes[0].Amount, ShouldEqual, 3)
+				So(incomes[1].RefereeID, ShouldEqual, 2)
+				So(incomes[1].Amount, ShouldEqual, 2)
+				So(incomes[1].NumberOfIncomes, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When get number of referee incomes", func() {
+			count, _ := s.GetNumberOfRefereeIncomes(q)
+
+			Convey("Count should be 2", func() {
+				So(count, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When get referee incomes with invalid sort by", func() {
+			q.SortBy = "amount; DROP TABLE users"
+			_, err := s.GetRefereeIncomes(q, 10, 0)
+
+			Convey("Error should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get referee incomes", func(s Storage) error {
+		_, err := s.GetRefereeIncomes(models.RefereeIncomeQuery{
+			Period: models.RefereeIncomePeriodWeek,
+			SortBy: models.RefereeIncomeSortByPeriod,
+		}, 10, 0)
+		return err
+	})
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// CreateReferralCampaign creates a new referral campaign, returns id of campaign created
+func (s Storage) CreateReferralCampaign(campaign models.ReferralCampaign) (int64, error) {
+	result, err := s.db.NamedExec("INSERT INTO referral_campaigns (`user_id`, `code`, `name`) VALUES (:user_id, :code, :name)", campaign)
+
+	if err != nil {
+		switch e := err.(type) {
+		case *mysql.MySQLError:
+			if e.Number == errcodeDuplicate {
+				return 0, errors.ErrDuplicatedReferralCode
+			}
+		}
+
+		return 0, fmt.Errorf("create referral campaign error: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get created referral campaign id error: %v", err)
+	}
+
+	return id, nil
+}
+
+// GetReferralCampaignByCode gets a referral campaign with code given
+func (s Storage) GetReferralCampaignByCode(code string) (models.ReferralCampaign, error) {
+	campaign := models.ReferralCampaign{}
+	err := s.db.Get(&campaign, "SELECT * FROM referral_campaigns WHERE `code` = ?", code)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return campaign, errors.ErrNotFound
+		}
+
+		return campaign, fmt.Errorf("query referral campaign by code error: %v", err)
+	}
+
+	return campaign, nil
+}
+
+// GetReferralCampaigns gets user's referral campaigns along with signups and earnings of currency of each campaign
+func (s Storage) GetReferralCampaigns(userID int64, currency string, limit, offset int64) ([]models.ReferralCampaign, error) {
+	rawSQL := "SELECT c.*, COUNT(u.`id`) AS `signups`, COALESCE(SUM(u.`status` = ?), 0) AS `verified_signups`, COALESCE(SUM(b.`referer_total_income`), 0) AS `earnings` " +
+		"FROM referral_campaigns c LEFT JOIN users u ON u.`referral_campaign_id` = c.`id` " +
+		"LEFT JOIN user_balances b ON b.`user_id` = u.`id` AND b.`currency` = ? " +
+		"WHERE c.`user_id` = ? GROUP BY c.`id` ORDER BY c.`id` DESC LIMIT ? OFFSET ?"
+	args := []interface{}{models.UserStatusVerified, currency, userID, limit, offset}
+	dest := []models.ReferralCampaign{}
+	err := s.selects(&dest, rawSQL, args...)
+	return dest, err
+}
+
+// GetNumberOfReferralCampaigns gets number of user's referral campaigns
+func (s Storage) GetNumberOfReferralCampaigns(userID int64) (int64, error) {
+	var count int64
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM referral_campaigns WHERE `user_id` = ?", userID).Scan(&count)
+	return count, err
+}
+
+// IncrementReferralCampaignClicks increments clicks of the referral campaign by 1
+func (s Storage) IncrementReferralCampaignClicks(code string) error {
+	result, err := s.db.Exec("UPDATE referral_campaigns SET `clicks` = `clicks` + 1 WHERE `code` = ?", code)
+	if err != nil {
+		return fmt.Errorf("increment referral campaign clicks error: %v", err)
+	}
+
+	if rowAffected, _ := result.RowsAffected(); rowAffected != 1 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestCreateReferralCampaign(t *testing.T) {
+	Convey("Given mysql storage with referral campaign", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateReferralCampaign(models.ReferralCampaign{UserID: 1, Code: "code", Name: "name"})
+
+		Convey("When create referral campaign", func() {
+			id, err := s.CreateReferralCampaign(models.ReferralCampaign{UserID: 2, Code: "other"})
+
+			Convey("Id of created campaign should be returned", func() {
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When create referral campaign with duplicated code", func() {
+			_, err := s.CreateReferralCampaign(models.ReferralCampaign{UserID: 2, Code: "code"})
+
+			Convey("Error should be duplicated referral code", func() {
+				So(err, ShouldEqual, errors.ErrDuplicatedReferralCode)
+			})
+		})
+	})
+
+	withClosedConn(t, "When create referral campaign", func(s Storage) error {
+		_, err := s.CreateReferralCampaign(models.ReferralCampaign{})
+		return err
+	})
+}
+
+func TestGetReferralCampaignByCode(t *testing.T) {
+	Convey("Given empty mysql storage", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When get referral campaign by code", func() {
+			_, err := s.GetReferralCampaignByCode("code")
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+	})
+
+	Convey("Given mysql storage with referral campaign", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateReferralCampaign(models.ReferralCampaign{UserID: 1, Code: "code", Name: "name"})
+
+		Convey("When get referral campaign by code", func() {
+			campaign, _ := s.GetReferralCampaignByCode("code")
+
+			Convey("Referral campaign should be owned by user 1", func() {
+				So(campaign.UserID, ShouldEqual, 1)
+				So(campaign.Name, ShouldEqual, "name")
+			})
+		})
+	})
+
+	withClosedConn(t, "When get referral campaign by code", func(s Storage) error {
+		_, err := s.GetReferralCampaignByCode("code")
+		return err
+	})
+}
+
+func TestGetReferralCampaigns(t *testing.T) {
+	Convey("Given mysql storage with referral campaign and referees", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e1", Address: "a1"}, "btc")
+		s.CreateReferralCampaign(models.ReferralCampaign{UserID: 1, Code: "code"})
+		s.CreateUser(models.User{Email: "e2", Address: "a2", RefererID: 1, ReferralCampaignID: 1}, "btc")
+		s.CreateUser(models.User{Email: "e3", Address: "a3", RefererID: 1, ReferralCampaignID: 1}, "btc")
+		s.UpdateUserStatus(3, models.UserStatusVerified)
+		s.IncrementReferralCampaignClicks("code")
+		s.IncrementReferralCampaignClicks("code")
+
+		Convey("When get referral campaigns", func() {
+			campaigns, _ := s.GetReferralCampaigns(1, "btc", 10, 0)
+
+			Convey("Stats of referral campaign should be correct", func() {
+				So(len(campaigns), ShouldEqual, 1)
+				So(campaigns[0].Clicks, ShouldEqual, 2)
+				So(campaigns[0].Signups, ShouldEqual, 2)
+				So(campaigns[0].VerifiedSignups, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When get number of referral campaigns", func() {
+			count, _ := s.GetNumberOfReferralCampaigns(1)
+
+			Convey("Count should be 1", func() {
+				So(count, ShouldEqual, 1)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get referral campaigns", func(s Storage) error {
+		_, err := s.GetReferralCampaigns(1, "btc", 10, 0)
+		return err
+	})
+}
+
+func TestIncrementReferralCampaignClicks(t *testing.T) {
+	Convey("Given empty mysql storage", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When increment clicks of non-existing referral campaign", func() {
+			err := s.IncrementReferralCampaignClicks("code")
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+	})
+
+	withClosedConn(t, "When increment referral campaign clicks", func(s Storage) error {
+		return s.IncrementReferralCampaignClicks("code")
+	})
+}
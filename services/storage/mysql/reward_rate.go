@@ -1,15 +1,18 @@
 package mysql
 
 import (
+	"database/sql"
 	"fmt"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/models"
 )
 
-// GetRewardRatesByType get all reward rates by type
+// GetRewardRatesByType get all global reward rates of every currency by type
 func (s Storage) GetRewardRatesByType(rewardRateType string) ([]models.RewardRate, error) {
 	rrs := []models.RewardRate{}
-	err := s.db.Select(&rrs, "SELECT * FROM reward_rates WHERE `type` = ?", rewardRateType)
+	err := s.db.Select(&rrs, "SELECT * FROM reward_rates WHERE `type` = ? AND `scope` = ''", rewardRateType)
 
 	if err != nil {
 		return nil, fmt.Errorf("query reward rates error: %v", err)
@@ -17,3 +20,130 @@ func (s Storage) GetRewardRatesByType(rewardRateType string) ([]models.RewardRat
 
 	return rrs, nil
 }
+
+// GetScopedRewardRates get all reward rates scoped by country or country group
+func (s Storage) GetScopedRewardRates() ([]models.RewardRate, error) {
+	rrs := []models.RewardRate{}
+	err := s.db.Select(&rrs, "SELECT * FROM reward_rates WHERE `scope` != ''")
+
+	if err != nil {
+		return nil, fmt.Errorf("query scoped reward rates error: %v", err)
+	}
+
+	return rrs, nil
+}
+
+// GetCountryGroups get all country groups
+func (s Storage) GetCountryGroups() ([]models.CountryGroup, error) {
+	groups := []models.CountryGroup{}
+	err := s.db.Select(&groups, "SELECT * FROM country_groups")
+
+	if err != nil {
+		return nil, fmt.Errorf("query country groups error: %v", err)
+	}
+
+	return groups, nil
+}
+
+// GetAllRewardRates get all reward rates of every currency, scope and type
+func (s Storage) GetAllRewardRates() ([]models.RewardRate, error) {
+	rrs := []models.RewardRate{}
+	err := s.selects(&rrs, "SELECT * FROM reward_rates ORDER BY `currency` ASC, `scope` ASC, `type` ASC, `id` ASC")
+	return rrs, err
+}
+
+// CreateRewardRate creates a new reward rate bucket
+func (s Storage) CreateRewardRate(rate models.RewardRate) error {
+	return s.withRewardRateTx(func(tx *sqlx.Tx) error {
+		rawSQL := "INSERT INTO reward_rates (`min`, `max`, `weight`, `currency`, `type`, `scope`) VALUES (:min, :max, :weight, :currency, :type, :scope)"
+		if _, err := tx.NamedExec(rawSQL, rate); err != nil {
+			return fmt.Errorf("create reward rate error: %v", err)
+		}
+
+		return validateRewardRatesWithTx(tx, rate.Currency, rate.Type, rate.Scope)
+	})
+}
+
+// UpdateRewardRate updates reward rate bucket by id
+func (s Storage) UpdateRewardRate(rate models.RewardRate) error {
+	return s.withRewardRateTx(func(tx *sqlx.Tx) error {
+		old, err := getRewardRateForUpdate(tx, rate.ID)
+		if err != nil {
+			return err
+		}
+
+		rawSQL := "UPDATE reward_rates SET `min` = :min, `max` = :max, `weight` = :weight, `currency` = :currency, `type` = :type, `scope` = :scope WHERE `id` = :id"
+		if _, err := tx.NamedExec(rawSQL, rate); err != nil {
+			return fmt.Errorf("update reward rate error: %v", err)
+		}
+
+		// bucket may be moved to another currency, type or scope
+		if err := validateRewardRatesWithTx(tx, old.Currency, old.Type, old.Scope); err != nil {
+			return err
+		}
+		return validateRewardRatesWithTx(tx, rate.Currency, rate.Type, rate.Scope)
+	})
+}
+
+// DeleteRewardRate deletes reward rate bucket by id
+func (s Storage) DeleteRewardRate(id int64) error {
+	return s.withRewardRateTx(func(tx *sqlx.Tx) error {
+		old, err := getRewardRateForUpdate(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("DELETE FROM reward_rates WHERE `id` = ?", id); err != nil {
+			return fmt.Errorf("delete reward rate error: %v", err)
+		}
+
+		return validateRewardRatesWithTx(tx, old.Currency, old.Type, old.Scope)
+	})
+}
+
+func (s Storage) withRewardRateTx(f func(tx *sqlx.Tx) error) error {
+	tx := s.db.MustBegin()
+
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("reward rate commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func getRewardRateForUpdate(tx *sqlx.Tx, id int64) (models.RewardRate, error) {
+	rate := models.RewardRate{}
+	err := tx.Get(&rate, "SELECT * FROM reward_rates WHERE `id` = ? FOR UPDATE", id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return rate, errors.ErrNotFound
+		}
+
+		return rate, fmt.Errorf("query reward rate error: %v", err)
+	}
+
+	return rate, nil
+}
+
+// weights of reward rates of the same currency, type and scope must sum to more than 0,
+// scoped ones may be empty which means falling back to global ones
+func validateRewardRatesWithTx(tx *sqlx.Tx, currency, rewardRateType, scope string) error {
+	var count, sum int64
+	rawSQL := "SELECT COUNT(*), COALESCE(SUM(`weight`), 0) FROM reward_rates WHERE `currency` = ? AND `type` = ? AND `scope` = ?"
+	if err := tx.QueryRowx(rawSQL, currency, rewardRateType, scope).Scan(&count, &sum); err != nil {
+		return fmt.Errorf("query sum of reward rates weight error: %v", err)
+	}
+
+	if (count > 0 || scope == "") && sum < 1 {
+		return errors.ErrInvalidRewardRates
+	}
+
+	return nil
+}
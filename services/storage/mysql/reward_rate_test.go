@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/models"
 )
 
@@ -25,3 +26,118 @@ func TestGetRewardRatesByType(t *testing.T) {
 		return err
 	})
 }
+
+func TestGetScopedRewardRates(t *testing.T) {
+	Convey("Given mysql storage with scoped reward rates", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `reward_rates` (`min`, `max`, `weight`, `type`, `scope`) VALUES (1, 2, 1, ?, 'US')", models.RewardRateTypeLess)
+
+		Convey("When get scoped reward rates", func() {
+			rrs, _ := s.GetScopedRewardRates()
+
+			Convey("Result set should contains 1 record", func() {
+				So(len(rrs), ShouldEqual, 1)
+				So(rrs[0].Scope, ShouldEqual, "US")
+			})
+		})
+
+		Convey("When get global reward rates", func() {
+			rrs, _ := s.GetRewardRatesByType(models.RewardRateTypeLess)
+
+			Convey("Scoped reward rates should be excluded", func() {
+				So(len(rrs), ShouldEqual, 3)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get scoped reward rates", func(s Storage) error {
+		_, err := s.GetScopedRewardRates()
+		return err
+	})
+}
+
+func TestGetCountryGroups(t *testing.T) {
+	Convey("Given mysql storage with country groups", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `country_groups` (`name`, `country`) VALUES ('tier1', 'US'), ('tier1', 'GB')")
+
+		Convey("When get country groups", func() {
+			groups, _ := s.GetCountryGroups()
+
+			Convey("Result set should contains 2 records", func() {
+				So(len(groups), ShouldEqual, 2)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get country groups", func(s Storage) error {
+		_, err := s.GetCountryGroups()
+		return err
+	})
+}
+
+func TestCreateRewardRate(t *testing.T) {
+	Convey("Given mysql storage with default reward rates", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When create scoped reward rate with zero weight", func() {
+			err := s.CreateRewardRate(models.RewardRate{Min: 1, Max: 2, Type: models.RewardRateTypeLess, Scope: "US"})
+
+			Convey("Error should be ErrInvalidRewardRates", func() {
+				So(err, ShouldEqual, errors.ErrInvalidRewardRates)
+			})
+		})
+
+		Convey("When create scoped reward rate", func() {
+			err := s.CreateRewardRate(models.RewardRate{Min: 1, Max: 2, Weight: 1, Type: models.RewardRateTypeLess, Scope: "US"})
+			rrs, _ := s.GetAllRewardRates()
+
+			Convey("Reward rate should be created", func() {
+				So(err, ShouldBeNil)
+				So(len(rrs), ShouldEqual, 7)
+			})
+		})
+	})
+}
+
+func TestUpdateRewardRate(t *testing.T) {
+	Convey("Given mysql storage with default reward rates", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When update non-existing reward rate", func() {
+			err := s.UpdateRewardRate(models.RewardRate{ID: 100})
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+
+		Convey("When update reward rate", func() {
+			err := s.UpdateRewardRate(models.RewardRate{ID: 1, Min: 1, Max: 2, Weight: 10, Type: models.RewardRateTypeLess})
+			rrs, _ := s.GetRewardRatesByType(models.RewardRateTypeLess)
+
+			Convey("Reward rate should be updated", func() {
+				So(err, ShouldBeNil)
+				So(rrs[0].Weight, ShouldEqual, 10)
+			})
+		})
+	})
+}
+
+func TestDeleteRewardRate(t *testing.T) {
+	Convey("Given mysql storage with default reward rates", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When delete all global reward rates of type", func() {
+			s.DeleteRewardRate(1)
+			s.DeleteRewardRate(2)
+			err := s.DeleteRewardRate(3)
+			rrs, _ := s.GetRewardRatesByType(models.RewardRateTypeLess)
+
+			Convey("The last one should not be deleted", func() {
+				So(err, ShouldEqual, errors.ErrInvalidRewardRates)
+				So(len(rrs), ShouldEqual, 1)
+			})
+		})
+	})
+}
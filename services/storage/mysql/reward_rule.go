@@ -0,0 +1,19 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetRewardRules gets all reward rules not expired yet sorted by priority
+func (s Storage) GetRewardRules() (models.RewardRules, error) {
+	rules := models.RewardRules{}
+	err := s.db.Select(&rules, "SELECT * FROM reward_rules WHERE `ends_at` IS NULL OR `ends_at` > UTC_TIMESTAMP() ORDER BY `priority` ASC, `id` ASC")
+
+	if err != nil {
+		return nil, fmt.Errorf("query reward rules error: %v", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,30 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetRewardRules(t *testing.T) {
+	Convey("Given mysql storage with reward rules", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `reward_rules` (`name`, `priority`, `multiplier`) VALUES ('b', 2, 1.5), ('a', 1, 2)")
+		s.db.MustExec("INSERT INTO `reward_rules` (`name`, `priority`, `multiplier`, `ends_at`) VALUES ('expired', 0, 3, '2016-01-01')")
+
+		Convey("When get reward rules", func() {
+			rules, _ := s.GetRewardRules()
+
+			Convey("Reward rules not expired should be sorted by priority", func() {
+				So(len(rules), ShouldEqual, 2)
+				So(rules[0].Name, ShouldEqual, "a")
+				So(rules[1].Multiplier, ShouldEqual, 1.5)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get reward rules", func(s Storage) error {
+		_, err := s.GetRewardRules()
+		return err
+	})
+}
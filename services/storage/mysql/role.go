@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetRoles gets all roles
+func (s Storage) GetRoles() ([]models.Role, error) {
+	dest := []models.Role{}
+	err := s.selects(&dest, "SELECT * FROM roles ORDER BY `id` ASC")
+	return dest, err
+}
+
+// GetRolePermissions gets permissions granted to every role
+func (s Storage) GetRolePermissions() (models.RolePermissions, error) {
+	rawSQL := "SELECT r.`name` AS `role`, rp.`permission` FROM role_permissions rp JOIN roles r ON r.`id` = rp.`role_id`"
+	dest := []models.RolePermission{}
+	if err := s.selects(&dest, rawSQL); err != nil {
+		return nil, err
+	}
+
+	return models.NewRolePermissions(dest), nil
+}
+
+// UpdateUserRole grants role to user, empty role revokes admin permissions
+func (s Storage) UpdateUserRole(userID int64, role string) error {
+	tx := s.db.MustBegin()
+
+	if err := updateUserRoleWithTx(tx, userID, role); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("update user role commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func updateUserRoleWithTx(tx *sqlx.Tx, userID int64, role string) error {
+	if _, err := getUserStatusForUpdate(tx, userID); err != nil {
+		return err
+	}
+
+	if role != "" {
+		var count int64
+		if err := tx.QueryRowx("SELECT COUNT(*) FROM roles WHERE `name` = ?", role).Scan(&count); err != nil {
+			return fmt.Errorf("query role error: %v", err)
+		}
+		if count == 0 {
+			return errors.ErrInvalidRole
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE users SET `role` = ? WHERE `id` = ?", role, userID); err != nil {
+		return fmt.Errorf("update user role error: %v", err)
+	}
+
+	return nil
+}
+
+// CreateAdminAuditLog records an admin request
+func (s Storage) CreateAdminAuditLog(log models.AdminAuditLog) error {
+	rawSQL := "INSERT INTO admin_audit_logs (`user_id`, `action`, `target`, `body`, `status_code`, `ip`) VALUES (:user_id, :action, :target, :body, :status_code, :ip)"
+	if _, err := s.db.NamedExec(rawSQL, log); err != nil {
+		return fmt.Errorf("create admin audit log error: %v", err)
+	}
+
+	return nil
+}
+
+// GetAdminAuditLogs gets admin audit logs, latest first
+func (s Storage) GetAdminAuditLogs(limit, offset int64) ([]models.AdminAuditLog, error) {
+	dest := []models.AdminAuditLog{}
+	err := s.selects(&dest, "SELECT * FROM admin_audit_logs ORDER BY `id` DESC LIMIT ? OFFSET ?", limit, offset)
+	return dest, err
+}
+
+// GetNumberOfAdminAuditLogs gets number of admin audit logs
+func (s Storage) GetNumberOfAdminAuditLogs() (int64, error) {
+	var count int64
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM admin_audit_logs").Scan(&count)
+	return count, err
+}
@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestGetRolePermissions(t *testing.T) {
+	Convey("Given mysql storage with default roles", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When get role permissions", func() {
+			roles, _ := s.GetRoles()
+			permissions, _ := s.GetRolePermissions()
+
+			Convey("Permissions should be grouped by role", func() {
+				So(len(roles), ShouldEqual, 3)
+				So(permissions.Has("superadmin", models.PermissionConfigsWrite), ShouldBeTrue)
+				So(permissions.Has("support", models.PermissionUsersWrite), ShouldBeTrue)
+				So(permissions.Has("support", models.PermissionBalancesWrite), ShouldBeFalse)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get role permissions", func(s Storage) error {
+		_, err := s.GetRolePermissions()
+		return err
+	})
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	Convey("Given mysql storage with user", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+
+		Convey("When grant non-existing role", func() {
+			err := s.UpdateUserRole(1, "god")
+
+			Convey("Error should be ErrInvalidRole", func() {
+				So(err, ShouldEqual, errors.ErrInvalidRole)
+			})
+		})
+
+		Convey("When grant role to non-existing user", func() {
+			err := s.UpdateUserRole(2, "support")
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+
+		Convey("When grant role", func() {
+			err := s.UpdateUserRole(1, "support")
+			user, _ := s.GetUserByID(1)
+
+			Convey("User should have role", func() {
+				So(err, ShouldBeNil)
+				So(user.Role, ShouldEqual, "support")
+			})
+
+			Convey("When revoke role", func() {
+				s.UpdateUserRole(1, "")
+				user, _ := s.GetUserByID(1)
+
+				Convey("User should have no role", func() {
+					So(user.Role, ShouldEqual, "")
+				})
+			})
+		})
+	})
+}
+
+func TestAdminAuditLog(t *testing.T) {
+	Convey("Given mysql storage", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateAdminAuditLog(models.AdminAuditLog{UserID: 1, Action: "POST /v1/admin/users/:id/adjustments", Target: "id=2", Body: `{"amount":1}`, StatusCode: 200})
+
+		Convey("When get admin audit logs", func() {
+			logs, _ := s.GetAdminAuditLogs(10, 0)
+			count, _ := s.GetNumberOfAdminAuditLogs()
+
+			Convey("Audit log should be recorded", func() {
+				So(count, ShouldEqual, 1)
+				So(logs[0].Target, ShouldEqual, "id=2")
+				So(logs[0].Body, ShouldEqual, `{"amount":1}`)
+			})
+		})
+	})
+
+	withClosedConn(t, "When create admin audit log", func(s Storage) error {
+		return s.CreateAdminAuditLog(models.AdminAuditLog{})
+	})
+}
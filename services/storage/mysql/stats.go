@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetUserStats gets number of users by status and signups since time given
+func (s Storage) GetUserStats(since time.Time) (models.UserStats, error) {
+	rawSQL := "SELECT COUNT(*) AS `users`, " +
+		"COALESCE(SUM(`status` = ?), 0) AS `verified`, " +
+		"COALESCE(SUM(`status` = ?), 0) AS `banned`, " +
+		"COALESCE(SUM(`created_at` >= ?), 0) AS `signups` FROM users"
+	args := []interface{}{models.UserStatusVerified, models.UserStatusBanned, since}
+
+	stats := models.UserStats{}
+	if err := s.db.Get(&stats, rawSQL, args...); err != nil {
+		return stats, fmt.Errorf("query user stats error: %v", err)
+	}
+
+	return stats, nil
+}
+
+// GetLiabilities gets sum of users balance of currency owed
+func (s Storage) GetLiabilities(currency string) (float64, error) {
+	var amount float64
+	rawSQL := "SELECT COALESCE(SUM(`balance`), 0) FROM user_balances WHERE `currency` = ?"
+	if err := s.db.QueryRowx(rawSQL, currency).Scan(&amount); err != nil {
+		return 0, fmt.Errorf("query liabilities error: %v", err)
+	}
+
+	return amount, nil
+}
+
+// GetWithdrawalStats gets number and amount of withdrawals of currency per status
+func (s Storage) GetWithdrawalStats(currency string) ([]models.WithdrawalStats, error) {
+	rawSQL := "SELECT `status`, COUNT(*) AS `number_of_withdrawals`, SUM(`amount`) AS `amount` FROM withdrawals WHERE `currency` = ? GROUP BY `status` ORDER BY `status` ASC"
+	dest := []models.WithdrawalStats{}
+	err := s.selects(&dest, rawSQL, currency)
+	return dest, err
+}
+
+// GetPaidOutAmount gets total amount of withdrawals of currency sent since time given
+func (s Storage) GetPaidOutAmount(currency string, since time.Time) (float64, error) {
+	var amount float64
+	rawSQL := "SELECT COALESCE(SUM(`amount`), 0) FROM withdrawals WHERE `currency` = ? AND `status` = ? AND `processed_at` >= ?"
+	if err := s.db.QueryRowx(rawSQL, currency, models.WithdrawalStatusProcessed, since).Scan(&amount); err != nil {
+		return 0, fmt.Errorf("query paid out amount error: %v", err)
+	}
+
+	return amount, nil
+}
+
+// GetOfferwallRevenues gets offerwall incomes within [since, until) aggregated per provider and currency
+func (s Storage) GetOfferwallRevenues(since, until time.Time) ([]models.OfferwallRevenue, error) {
+	rawSQL := "SELECT `type`, `currency`, COUNT(*) AS `number_of_incomes`, " +
+		"SUM(IF(`status` != ?, `income`, 0)) AS `amount`, " +
+		"SUM(IF(`status` != ?, `referer_income`, 0)) AS `referer_amount`, " +
+		"SUM(IF(`status` = ?, `income` + `referer_income`, 0)) AS `chargeback_amount` " +
+		"FROM incomes WHERE `type` NOT IN (?, ?, ?) AND `created_at` >= ? AND `created_at` < ? " +
+		"GROUP BY `type`, `currency` ORDER BY `amount` DESC"
+	args := []interface{}{
+		models.IncomeStatusChargeback, models.IncomeStatusChargeback, models.IncomeStatusChargeback,
+		models.IncomeTypeReward, models.IncomeTypeAchievement, models.IncomeTypeAdmin,
+		since, until,
+	}
+
+	dest := []models.OfferwallRevenue{}
+	if err := s.selects(&dest, rawSQL, args...); err != nil {
+		return nil, err
+	}
+
+	for i := range dest {
+		dest[i].Provider = models.IncomeTypeName(dest[i].Type)
+	}
+
+	return dest, nil
+}
@@ -0,0 +1,142 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestGetUserStats(t *testing.T) {
+	Convey("Given mysql storage with users", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address, status, created_at) VALUES(?, ?, ?, ?);", "e1", "b1", models.UserStatusVerified, time.Now().AddDate(0, 0, -2))
+		s.db.MustExec("INSERT INTO `users` (email, address, status) VALUES(?, ?, ?);", "e2", "b2", models.UserStatusBanned)
+		s.CreateUser(models.User{Email: "e3", Address: "b3"}, "btc")
+
+		Convey("When get user stats since yesterday", func() {
+			stats, err := s.GetUserStats(time.Now().AddDate(0, 0, -1))
+
+			Convey("Stats should be aggregated", func() {
+				So(err, ShouldBeNil)
+				So(stats, ShouldResemble, models.UserStats{Users: 3, Verified: 1, Banned: 1, Signups: 2})
+			})
+		})
+	})
+
+	withClosedConn(t, "When get user stats", func(s Storage) error {
+		_, err := s.GetUserStats(time.Now())
+		return err
+	})
+}
+
+func TestGetLiabilities(t *testing.T) {
+	Convey("Given mysql storage with balances in currencies", t, func() {
+		s := prepareDatabaseForTesting()
+		s.InsertUserBalance(1, "btc", 1)
+		s.InsertUserBalance(2, "btc", 2)
+		s.InsertUserBalance(1, "doge", 100)
+
+		Convey("When get liabilities of currency", func() {
+			amount, err := s.GetLiabilities("btc")
+
+			Convey("Balances of currency should be summed", func() {
+				So(err, ShouldBeNil)
+				So(amount, ShouldEqual, 3)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get liabilities", func(s Storage) error {
+		_, err := s.GetLiabilities("btc")
+		return err
+	})
+}
+
+func TestGetWithdrawalStats(t *testing.T) {
+	Convey("Given mysql storage with withdrawals", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3, Status: models.WithdrawalStatusReview}, 0)
+
+		Convey("When get withdrawal stats", func() {
+			stats, _ := s.GetWithdrawalStats("btc")
+
+			Convey("Withdrawals should be aggregated per status", func() {
+				So(stats, ShouldResemble, []models.WithdrawalStats{
+					{Status: models.WithdrawalStatusPending, NumberOfWithdrawals: 2, Amount: 3},
+					{Status: models.WithdrawalStatusReview, NumberOfWithdrawals: 1, Amount: 3},
+				})
+			})
+		})
+	})
+
+	withClosedConn(t, "When get withdrawal stats", func(s Storage) error {
+		_, err := s.GetWithdrawalStats("btc")
+		return err
+	})
+}
+
+func TestGetPaidOutAmount(t *testing.T) {
+	Convey("Given mysql storage with processed withdrawals", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3}, 0)
+		s.UpdateWithdrawalStatusToProcessing([]int64{1, 2})
+		s.UpdateWithdrawalStatusToProcessed([]int64{1, 2}, "tx")
+
+		Convey("When get paid out amount", func() {
+			amount, err := s.GetPaidOutAmount("btc", time.Now().Add(-time.Hour))
+
+			Convey("Amount of processed withdrawals should be summed", func() {
+				So(err, ShouldBeNil)
+				So(amount, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When get paid out amount in future", func() {
+			amount, err := s.GetPaidOutAmount("btc", time.Now().Add(time.Hour))
+
+			Convey("Amount should be 0", func() {
+				So(err, ShouldBeNil)
+				So(amount, ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestGetOfferwallRevenues(t *testing.T) {
+	Convey("Given mysql storage with offerwall incomes", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+		now := time.Now()
+		s.CreateRewardIncome(models.Income{UserID: 1, Currency: "btc", Type: models.IncomeTypeReward, Income: 100}, now)
+		s.CreateSuperrewardsIncome(models.Income{UserID: 1, Currency: "btc", Type: models.IncomeTypeSuperrewards, Income: 1, RefererIncome: 0.1}, "t1", "o1")
+		s.CreateSuperrewardsIncome(models.Income{UserID: 1, Currency: "btc", Type: models.IncomeTypeSuperrewards, Income: 2, RefererIncome: 0.2}, "t2", "o2")
+		s.CreatePtcwallIncome(models.Income{UserID: 1, Currency: "btc", Type: models.IncomeTypePtcwall, Income: 5})
+
+		Convey("When get offerwall revenues", func() {
+			revenues, _ := s.GetOfferwallRevenues(now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+
+			Convey("Incomes should be aggregated per provider", func() {
+				So(len(revenues), ShouldEqual, 2)
+				So(revenues[0].Provider, ShouldEqual, "ptcwall")
+				So(revenues[1].Provider, ShouldEqual, "superrewards")
+				So(revenues[1].NumberOfIncomes, ShouldEqual, 2)
+				So(revenues[1].Amount, ShouldEqual, 3)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get offerwall revenues", func(s Storage) error {
+		_, err := s.GetOfferwallRevenues(time.Now(), time.Now())
+		return err
+	})
+}
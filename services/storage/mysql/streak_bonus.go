@@ -0,0 +1,19 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetStreakBonuses gets all streak bonuses sorted by days
+func (s Storage) GetStreakBonuses() (models.StreakBonuses, error) {
+	bonuses := models.StreakBonuses{}
+	err := s.db.Select(&bonuses, "SELECT * FROM streak_bonuses ORDER BY `days` ASC")
+
+	if err != nil {
+		return nil, fmt.Errorf("query streak bonuses error: %v", err)
+	}
+
+	return bonuses, nil
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetStreakBonuses(t *testing.T) {
+	Convey("Given mysql storage with default streak bonuses", t, func() {
+		s := prepareDatabaseForTesting()
+
+		Convey("When get streak bonuses", func() {
+			bonuses, _ := s.GetStreakBonuses()
+
+			Convey("Streak bonuses should be sorted by days", func() {
+				So(len(bonuses), ShouldEqual, 5)
+				So(bonuses[0].Days, ShouldEqual, 2)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get streak bonuses", func(s Storage) error {
+		_, err := s.GetStreakBonuses()
+		return err
+	})
+}
@@ -3,14 +3,15 @@ package mysql
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/solefaucet/sole-server/models"
 )
 
-// GetLatestTotalReward get all total rewards order by time desc
-func (s Storage) GetLatestTotalReward() (models.TotalReward, error) {
+// GetLatestTotalReward get latest total reward of currency
+func (s Storage) GetLatestTotalReward(currency string) (models.TotalReward, error) {
 	result := models.TotalReward{}
-	err := s.db.Get(&result, "SELECT * FROM total_rewards FORCE INDEX (`created_at`) ORDER BY `created_at` DESC LIMIT 1")
+	err := s.db.Get(&result, "SELECT * FROM total_rewards WHERE `currency` = ? ORDER BY `created_at` DESC LIMIT 1", currency)
 
 	if err != nil && err != sql.ErrNoRows {
 		return result, fmt.Errorf("query sorted total rewards error: %v", err)
@@ -18,3 +19,11 @@ func (s Storage) GetLatestTotalReward() (models.TotalReward, error) {
 
 	return result, nil
 }
+
+// GetTotalRewards gets daily total rewards of currency within [since, until)
+func (s Storage) GetTotalRewards(currency string, since, until time.Time) ([]models.TotalReward, error) {
+	rawSQL := "SELECT * FROM total_rewards WHERE `currency` = ? AND `created_at` >= ? AND `created_at` < ? ORDER BY `created_at` ASC"
+	dest := []models.TotalReward{}
+	err := s.selects(&dest, rawSQL, currency, since, until)
+	return dest, err
+}
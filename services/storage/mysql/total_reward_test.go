@@ -14,7 +14,7 @@ func TestGetLatestTotalReward(t *testing.T) {
 		s := prepareDatabaseForTesting()
 
 		Convey("When get latest total reward", func() {
-			result, err := s.GetLatestTotalReward()
+			result, err := s.GetLatestTotalReward("btc")
 
 			Convey("Error should be nil", func() {
 				So(err, ShouldBeNil)
@@ -29,11 +29,11 @@ func TestGetLatestTotalReward(t *testing.T) {
 	Convey("Given mysql storage with one total reward data", t, func() {
 		s := prepareDatabaseForTesting()
 		now := time.Now()
-		s.IncrementTotalReward(now, 1)
-		s.IncrementTotalReward(now, 1)
+		s.IncrementTotalReward("btc", now, 1)
+		s.IncrementTotalReward("btc", now, 1)
 
 		Convey("When get latest total reward", func() {
-			r, _ := s.GetLatestTotalReward()
+			r, _ := s.GetLatestTotalReward("btc")
 
 			Convey("Result should be equal", func() {
 				So(r, func(actual interface{}, expected ...interface{}) string {
@@ -51,11 +51,11 @@ func TestGetLatestTotalReward(t *testing.T) {
 		s := prepareDatabaseForTesting()
 		now := time.Now()
 		tmr := now.AddDate(0, 0, 1)
-		s.IncrementTotalReward(now, 10)
-		s.IncrementTotalReward(tmr, 1)
+		s.IncrementTotalReward("btc", now, 10)
+		s.IncrementTotalReward("btc", tmr, 1)
 
 		Convey("When get latest total reward", func() {
-			r, _ := s.GetLatestTotalReward()
+			r, _ := s.GetLatestTotalReward("btc")
 
 			Convey("Result should be equal", func() {
 				So(r, func(actual interface{}, expected ...interface{}) string {
@@ -70,7 +70,14 @@ func TestGetLatestTotalReward(t *testing.T) {
 	})
 
 	withClosedConn(t, "When get latest total rewards", func(s Storage) error {
-		_, err := s.GetLatestTotalReward()
+		_, err := s.GetLatestTotalReward("btc")
+		return err
+	})
+}
+
+func TestGetTotalRewards(t *testing.T) {
+	withClosedConn(t, "When get total rewards", func(s Storage) error {
+		_, err := s.GetTotalRewards("btc", time.Now(), time.Now())
 		return err
 	})
 }
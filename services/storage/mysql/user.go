@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
 	"github.com/solefaucet/sole-server/errors"
 	"github.com/solefaucet/sole-server/models"
 )
@@ -42,9 +43,28 @@ func (s Storage) GetUserByEmail(email string) (models.User, error) {
 	return user, nil
 }
 
-// CreateUser creates a new user
-func (s Storage) CreateUser(u models.User) error {
-	_, err := s.db.NamedExec("INSERT INTO users (`email`, `address`, `referer_id`) VALUES (:email, :address, :referer_id)", u)
+// CreateUser creates a new user with signup address as verified default of currency in address book
+func (s Storage) CreateUser(u models.User, currency string) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("create user begin transaction error: %v", err)
+	}
+
+	if err := createUserWithTx(tx, u, currency); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("create user commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func createUserWithTx(tx *sqlx.Tx, u models.User, currency string) error {
+	result, err := tx.NamedExec("INSERT INTO users (`email`, `address`, `referer_id`, `referral_campaign_id`) VALUES (:email, :address, :referer_id, :referral_campaign_id)", u)
 
 	if err != nil {
 		switch e := err.(type) {
@@ -60,12 +80,23 @@ func (s Storage) CreateUser(u models.User) error {
 					}
 				}
 			}
-		default:
-			return fmt.Errorf("create user error: %v", err)
 		}
+		return fmt.Errorf("create user error: %v", err)
 	}
 
-	return nil
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get created user id error: %v", err)
+	}
+
+	_, err = createUserAddressWithTx(tx, models.UserAddress{
+		UserID:    id,
+		Currency:  currency,
+		Address:   u.Address,
+		Verified:  true,
+		IsDefault: true,
+	})
+	return err
 }
 
 // UpdateUserStatus updates a user's status
@@ -79,6 +110,55 @@ func (s Storage) UpdateUserStatus(id int64, status string) error {
 	return nil
 }
 
+// UpdateUserAutoWithdrawal updates whether user's balance is swept by auto withdrawal
+func (s Storage) UpdateUserAutoWithdrawal(id int64, autoWithdrawal bool) error {
+	_, err := s.db.Exec("UPDATE users SET `auto_withdrawal` = ? WHERE `id` = ?", autoWithdrawal, id)
+
+	if err != nil {
+		return fmt.Errorf("update user auto withdrawal error: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateUserCurrency updates currency that user's offerwall earnings go to
+func (s Storage) UpdateUserCurrency(id int64, currency string) error {
+	_, err := s.db.Exec("UPDATE users SET `currency` = ? WHERE `id` = ?", currency, id)
+
+	if err != nil {
+		return fmt.Errorf("update user currency error: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateUserTOTP updates user's TOTP secret and whether it is required on withdrawal
+func (s Storage) UpdateUserTOTP(id int64, secret string, enabled bool) error {
+	_, err := s.db.Exec("UPDATE users SET `totp_secret` = ?, `totp_enabled` = ? WHERE `id` = ?", secret, enabled, id)
+
+	if err != nil {
+		return fmt.Errorf("update user totp error: %v", err)
+	}
+
+	return nil
+}
+
+// UseTOTPCounter records counter of TOTP code used by user,
+// ErrInvalidTOTPCode is returned if code of the counter or a later one has been used
+func (s Storage) UseTOTPCounter(id int64, counter int64) error {
+	result, err := s.db.Exec("UPDATE users SET `totp_counter` = ? WHERE `id` = ? AND `totp_counter` < ?", counter, id, counter)
+	if err != nil {
+		return fmt.Errorf("update user totp counter error: %v", err)
+	}
+
+	// counter is compared and set atomically so that a code is not accepted twice
+	if rowAffected, _ := result.RowsAffected(); rowAffected != 1 {
+		return errors.ErrInvalidTOTPCode
+	}
+
+	return nil
+}
+
 // GetReferees gets user's referees
 func (s Storage) GetReferees(userID int64, limit, offset int64) ([]models.User, error) {
 	rawSQL := "SELECT * FROM users WHERE `referer_id` = ? ORDER BY `id` DESC LIMIT ? OFFSET ?"
@@ -95,11 +175,171 @@ func (s Storage) GetNumberOfReferees(userID int64) (int64, error) {
 	return count, err
 }
 
-// GetWithdrawableUsers gets users who are able to withdraw
-func (s Storage) GetWithdrawableUsers(minAmount float64) ([]models.User, error) {
-	rawSQL := "SELECT * FROM users WHERE `status` = ? AND `balance` > ?"
-	args := []interface{}{models.UserStatusVerified, minAmount}
+// GetWithdrawableBalances gets balances of currency of users who are able to withdraw and do not opt out of auto withdrawal
+func (s Storage) GetWithdrawableBalances(currency string, minAmount float64) ([]models.UserBalance, error) {
+	rawSQL := "SELECT b.* FROM user_balances b JOIN users u ON u.`id` = b.`user_id` " +
+		"WHERE b.`currency` = ? AND b.`balance` > ? AND u.`status` = ? AND u.`auto_withdrawal` = 1"
+	args := []interface{}{currency, minAmount, models.UserStatusVerified}
+	dest := []models.UserBalance{}
+	err := s.selects(&dest, rawSQL, args...)
+	return dest, err
+}
+
+// GetUsers gets users matching query, latest registered first
+func (s Storage) GetUsers(q models.UserQuery, limit, offset int64) ([]models.User, error) {
+	where, args := userQueryCondition(q)
+	rawSQL := "SELECT * FROM users WHERE " + where + " ORDER BY `id` DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
 	dest := []models.User{}
 	err := s.selects(&dest, rawSQL, args...)
 	return dest, err
 }
+
+// GetNumberOfUsers gets number of users matching query
+func (s Storage) GetNumberOfUsers(q models.UserQuery) (int64, error) {
+	where, args := userQueryCondition(q)
+	var count int64
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM users WHERE "+where, args...).Scan(&count)
+	return count, err
+}
+
+func userQueryCondition(q models.UserQuery) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if q.ID > 0 {
+		conditions = append(conditions, "`id` = ?")
+		args = append(args, q.ID)
+	}
+	if q.RefererID > 0 {
+		conditions = append(conditions, "`referer_id` = ?")
+		args = append(args, q.RefererID)
+	}
+	if q.Email != "" {
+		conditions = append(conditions, "`email` LIKE ?")
+		args = append(args, escapeLike(q.Email)+"%")
+	}
+	if q.Address != "" {
+		conditions = append(conditions, "`id` IN (SELECT `user_id` FROM user_addresses WHERE `address` LIKE ?)")
+		args = append(args, escapeLike(q.Address)+"%")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escape wildcards so that user input is matched literally
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// BanUser bans user with reason, user is logged out of every device
+func (s Storage) BanUser(id int64, reason string) error {
+	tx := s.db.MustBegin()
+
+	if err := banUserWithTx(tx, id, reason); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ban user commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func banUserWithTx(tx *sqlx.Tx, id int64, reason string) error {
+	status, err := getUserStatusForUpdate(tx, id)
+	if err != nil {
+		return err
+	}
+	if status == models.UserStatusBanned {
+		return errors.ErrUserBanned
+	}
+
+	if err := changeUserStatus(tx, id, status, models.UserStatusBanned, reason); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM auth_tokens WHERE `user_id` = ?", id); err != nil {
+		return fmt.Errorf("delete auth tokens of user error: %v", err)
+	}
+
+	return nil
+}
+
+// UnbanUser unbans user with reason, user status is restored to the one before banned
+func (s Storage) UnbanUser(id int64, reason string) error {
+	tx := s.db.MustBegin()
+
+	if err := unbanUserWithTx(tx, id, reason); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unban user commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func unbanUserWithTx(tx *sqlx.Tx, id int64, reason string) error {
+	status, err := getUserStatusForUpdate(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.UserStatusBanned {
+		return errors.ErrUserNotBanned
+	}
+
+	// users banned without log, e.g. banned by sql, have to verify email again
+	previousStatus := models.UserStatusUnverified
+	rawSQL := "SELECT `from_status` FROM user_status_logs WHERE `user_id` = ? AND `to_status` = ? ORDER BY `id` DESC LIMIT 1"
+	err = tx.QueryRowx(rawSQL, id, models.UserStatusBanned).Scan(&previousStatus)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("query user status before banned error: %v", err)
+	}
+
+	return changeUserStatus(tx, id, status, previousStatus, reason)
+}
+
+func getUserStatusForUpdate(tx *sqlx.Tx, id int64) (string, error) {
+	var status string
+	err := tx.QueryRowx("SELECT `status` FROM users WHERE `id` = ? FOR UPDATE", id).Scan(&status)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.ErrNotFound
+		}
+
+		return "", fmt.Errorf("query user status error: %v", err)
+	}
+
+	return status, nil
+}
+
+func changeUserStatus(tx *sqlx.Tx, id int64, from, to, reason string) error {
+	if _, err := tx.Exec("UPDATE users SET `status` = ? WHERE `id` = ?", to, id); err != nil {
+		return fmt.Errorf("update user status error: %v", err)
+	}
+
+	rawSQL := "INSERT INTO user_status_logs (`user_id`, `from_status`, `to_status`, `reason`) VALUES (?, ?, ?, ?)"
+	if _, err := tx.Exec(rawSQL, id, from, to, reason); err != nil {
+		return fmt.Errorf("insert user status log error: %v", err)
+	}
+
+	return nil
+}
+
+// GetUserStatusLogs gets user's status changes, latest first
+func (s Storage) GetUserStatusLogs(userID int64) ([]models.UserStatusLog, error) {
+	rawSQL := "SELECT * FROM user_status_logs WHERE `user_id` = ? ORDER BY `id` DESC"
+	dest := []models.UserStatusLog{}
+	err := s.selects(&dest, rawSQL, userID)
+	return dest, err
+}
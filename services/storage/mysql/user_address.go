@@ -0,0 +1,209 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetUserAddresses gets user's address book, default addresses first
+func (s Storage) GetUserAddresses(userID int64) ([]models.UserAddress, error) {
+	rawSQL := "SELECT * FROM user_addresses WHERE `user_id` = ? ORDER BY `is_default` DESC, `id` ASC"
+	dest := []models.UserAddress{}
+	err := s.selects(&dest, rawSQL, userID)
+	return dest, err
+}
+
+// GetDefaultUserAddress gets user's default address of currency
+func (s Storage) GetDefaultUserAddress(userID int64, currency string) (models.UserAddress, error) {
+	a := models.UserAddress{}
+	err := s.db.Get(&a, "SELECT * FROM user_addresses WHERE `user_id` = ? AND `currency` = ? AND `is_default` = 1", userID, currency)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return a, errors.ErrNotFound
+		}
+
+		return a, fmt.Errorf("query default user address error: %v", err)
+	}
+
+	return a, nil
+}
+
+// CreateUserAddress adds address to user's address book, returns id of the entry,
+// address must be in no address book and paid to no other user before,
+// address book holds at most models.MaxUserAddresses addresses
+func (s Storage) CreateUserAddress(a models.UserAddress) (int64, error) {
+	tx := s.db.MustBegin()
+
+	id, err := createUserAddressWithTx(tx, a)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("create user address commit transaction error: %v", err)
+	}
+
+	return id, nil
+}
+
+func createUserAddressWithTx(tx *sqlx.Tx, a models.UserAddress) (int64, error) {
+	// user row is locked so that concurrent requests can not exceed the limit together
+	if _, err := getUserStatusForUpdate(tx, a.UserID); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	rawSQL := "SELECT COUNT(*) FROM user_addresses WHERE `user_id` = ?"
+	if err := tx.QueryRowx(rawSQL, a.UserID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("query number of user addresses error: %v", err)
+	}
+	if count >= models.MaxUserAddresses {
+		return 0, errors.ErrTooManyAddresses
+	}
+
+	// addresses removed from address book are free to add again,
+	// unless another user has been paid to it
+	rawSQL = "SELECT COUNT(*) FROM withdrawals WHERE `address` = ? AND `user_id` <> ?"
+	if err := tx.QueryRowx(rawSQL, a.Address, a.UserID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("query withdrawals of address error: %v", err)
+	}
+	if count > 0 {
+		return 0, errors.ErrDuplicatedAddress
+	}
+
+	rawSQL = "INSERT INTO user_addresses (`user_id`, `currency`, `address`, `label`, `verify_token`, `verified`, `is_default`) VALUES (:user_id, :currency, :address, :label, :verify_token, :verified, :is_default)"
+	result, err := tx.NamedExec(rawSQL, a)
+	if err != nil {
+		if e, ok := err.(*mysql.MySQLError); ok && e.Number == errcodeDuplicate {
+			return 0, errors.ErrDuplicatedAddress
+		}
+		return 0, fmt.Errorf("insert user address error: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get created user address id error: %v", err)
+	}
+
+	return id, nil
+}
+
+// VerifyUserAddress marks user's address verified if token matches the one sent
+func (s Storage) VerifyUserAddress(userID, id int64, token string) error {
+	rawSQL := "UPDATE user_addresses SET `verified` = 1, `verify_token` = '' WHERE `id` = ? AND `user_id` = ? AND `verified` = 0 AND `verify_token` = ?"
+	result, err := s.db.Exec(rawSQL, id, userID, token)
+	if err != nil {
+		return fmt.Errorf("verify user address error: %v", err)
+	}
+
+	rowAffected, _ := result.RowsAffected()
+	if rowAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetDefaultUserAddress makes user's verified address default of its currency, the one of base currency
+// is mirrored to users.address, address_updated_at is bumped so that withdrawals are held by address review rule
+func (s Storage) SetDefaultUserAddress(userID, id int64, baseCurrency string) error {
+	tx := s.db.MustBegin()
+
+	if err := setDefaultUserAddressWithTx(tx, userID, id, baseCurrency); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("set default user address commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func setDefaultUserAddressWithTx(tx *sqlx.Tx, userID, id int64, baseCurrency string) error {
+	a, err := getUserAddressForUpdate(tx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !a.Verified {
+		return errors.ErrAddressNotVerified
+	}
+	if a.IsDefault {
+		return nil
+	}
+
+	rawSQL := "UPDATE user_addresses SET `is_default` = (`id` = ?) WHERE `user_id` = ? AND `currency` = ?"
+	if _, err := tx.Exec(rawSQL, id, userID, a.Currency); err != nil {
+		return fmt.Errorf("update default user address error: %v", err)
+	}
+
+	rawSQL = "UPDATE users SET `address_updated_at` = NOW() WHERE `id` = ?"
+	args := []interface{}{userID}
+	if a.Currency == baseCurrency {
+		rawSQL = "UPDATE users SET `address` = ?, `address_updated_at` = NOW() WHERE `id` = ?"
+		args = []interface{}{a.Address, userID}
+	}
+	if _, err := tx.Exec(rawSQL, args...); err != nil {
+		return fmt.Errorf("update user address error: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteUserAddress removes address from user's address book, default address can not be removed
+func (s Storage) DeleteUserAddress(userID, id int64) error {
+	tx := s.db.MustBegin()
+
+	if err := deleteUserAddressWithTx(tx, userID, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("delete user address commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+func deleteUserAddressWithTx(tx *sqlx.Tx, userID, id int64) error {
+	a, err := getUserAddressForUpdate(tx, userID, id)
+	if err != nil {
+		return err
+	}
+	if a.IsDefault {
+		return errors.ErrDefaultAddress
+	}
+
+	if _, err := tx.Exec("DELETE FROM user_addresses WHERE `id` = ?", id); err != nil {
+		return fmt.Errorf("delete user address error: %v", err)
+	}
+
+	return nil
+}
+
+func getUserAddressForUpdate(tx *sqlx.Tx, userID, id int64) (models.UserAddress, error) {
+	a := models.UserAddress{}
+	err := tx.Get(&a, "SELECT * FROM user_addresses WHERE `id` = ? AND `user_id` = ? FOR UPDATE", id, userID)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return a, errors.ErrNotFound
+		}
+
+		return a, fmt.Errorf("query user address error: %v", err)
+	}
+
+	return a, nil
+}
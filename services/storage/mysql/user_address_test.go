@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/errors"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestUserAddress(t *testing.T) {
+	Convey("Given mysql storage with users", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e1", Address: "b1"}, "btc")
+		s.CreateUser(models.User{Email: "e2", Address: "b2"}, "btc")
+
+		Convey("When get addresses of user", func() {
+			addresses, err := s.GetUserAddresses(1)
+
+			Convey("Signup address should be verified default", func() {
+				So(err, ShouldBeNil)
+				So(len(addresses), ShouldEqual, 1)
+				So(addresses[0].Address, ShouldEqual, "b1")
+				So(addresses[0].Verified, ShouldBeTrue)
+				So(addresses[0].IsDefault, ShouldBeTrue)
+			})
+		})
+
+		Convey("When create address in address book of another user", func() {
+			_, err := s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "b2"})
+
+			Convey("Error should be duplicate address", func() {
+				So(err, ShouldEqual, errors.ErrDuplicatedAddress)
+			})
+		})
+
+		Convey("When signup with address in address book of another user", func() {
+			s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "b3"})
+			err := s.CreateUser(models.User{Email: "e3", Address: "b3"}, "btc")
+
+			Convey("Error should be duplicate address", func() {
+				So(err, ShouldEqual, errors.ErrDuplicatedAddress)
+			})
+		})
+
+		Convey("When create addresses more than limit", func() {
+			for i := 1; i < models.MaxUserAddresses; i++ {
+				s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: fmt.Sprintf("c%d", i)})
+			}
+			_, err := s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "c"})
+			addresses, _ := s.GetUserAddresses(1)
+
+			Convey("Error should be ErrTooManyAddresses", func() {
+				So(err, ShouldEqual, errors.ErrTooManyAddresses)
+				So(len(addresses), ShouldEqual, models.MaxUserAddresses)
+			})
+		})
+
+		Convey("When create address paid to another user", func() {
+			s.db.MustExec("INSERT INTO withdrawals (user_id, address, amount) VALUES (?, ?, ?)", 2, "b3", 1)
+			_, err := s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "b3"})
+
+			Convey("Error should be duplicate address", func() {
+				So(err, ShouldEqual, errors.ErrDuplicatedAddress)
+			})
+		})
+
+		Convey("When set unverified address as default", func() {
+			id, _ := s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "b3", VerifyToken: "t"})
+			err := s.SetDefaultUserAddress(1, id, "btc")
+
+			Convey("Error should be ErrAddressNotVerified", func() {
+				So(err, ShouldEqual, errors.ErrAddressNotVerified)
+			})
+		})
+
+		Convey("When verify address with wrong token", func() {
+			id, _ := s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "b3", VerifyToken: "t"})
+			err := s.VerifyUserAddress(1, id, "x")
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+
+		Convey("When set verified address as default", func() {
+			id, _ := s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "b3", VerifyToken: "t"})
+			verifyErr := s.VerifyUserAddress(1, id, "t")
+			err := s.SetDefaultUserAddress(1, id, "btc")
+			addresses, _ := s.GetUserAddresses(1)
+			user, _ := s.GetUserByID(1)
+
+			Convey("Address should be default and mirrored to user", func() {
+				So(verifyErr, ShouldBeNil)
+				So(err, ShouldBeNil)
+				So(addresses[0].Address, ShouldEqual, "b3")
+				So(addresses[0].IsDefault, ShouldBeTrue)
+				So(addresses[1].IsDefault, ShouldBeFalse)
+				So(user.Address, ShouldEqual, "b3")
+				So(user.AddressUpdatedAt, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When set address of another user as default", func() {
+			err := s.SetDefaultUserAddress(1, 2, "btc")
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+
+		Convey("When delete default address", func() {
+			err := s.DeleteUserAddress(1, 1)
+
+			Convey("Error should be ErrDefaultAddress", func() {
+				So(err, ShouldEqual, errors.ErrDefaultAddress)
+			})
+		})
+
+		Convey("When delete non-default address", func() {
+			id, _ := s.CreateUserAddress(models.UserAddress{UserID: 1, Currency: "btc", Address: "b3"})
+			err := s.DeleteUserAddress(1, id)
+			addresses, _ := s.GetUserAddresses(1)
+
+			Convey("Address should be removed", func() {
+				So(err, ShouldBeNil)
+				So(len(addresses), ShouldEqual, 1)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get user addresses", func(s Storage) error {
+		_, err := s.GetUserAddresses(1)
+		return err
+	})
+
+	withClosedConn(t, "When verify user address", func(s Storage) error {
+		return s.VerifyUserAddress(1, 1, "t")
+	})
+}
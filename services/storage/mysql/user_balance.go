@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+// GetUserBalances gets user's balances of every currency earned in
+func (s Storage) GetUserBalances(userID int64) ([]models.UserBalance, error) {
+	rawSQL := "SELECT * FROM user_balances WHERE `user_id` = ? ORDER BY `currency` ASC"
+	dest := []models.UserBalance{}
+	err := s.selects(&dest, rawSQL, userID)
+	return dest, err
+}
+
+// GetUserBalance gets user's balance of currency, zero balance if user has never earned in it
+func (s Storage) GetUserBalance(userID int64, currency string) (models.UserBalance, error) {
+	balance := models.UserBalance{}
+	err := s.db.Get(&balance, "SELECT * FROM user_balances WHERE `user_id` = ? AND `currency` = ?", userID, currency)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.UserBalance{UserID: userID, Currency: currency}, nil
+		}
+
+		return balance, fmt.Errorf("query user balance error: %v", err)
+	}
+
+	return balance, nil
+}
+
+// tables whose rows are kept per currency
+var currencyTables = []string{
+	"user_balances",
+	"user_addresses",
+	"incomes",
+	"withdrawals",
+	"admin_adjustments",
+	"reward_rates",
+	"total_rewards",
+}
+
+// LabelLegacyCurrency labels rows created by single currency deployment, i.e. with empty currency,
+// as currency given, returns number of rows labeled per table
+func (s Storage) LabelLegacyCurrency(currency string) (map[string]int64, error) {
+	labeled := map[string]int64{}
+	for _, table := range currencyTables {
+		result, err := s.db.Exec("UPDATE `"+table+"` SET `currency` = ? WHERE `currency` = ''", currency)
+		if err != nil {
+			return labeled, fmt.Errorf("label currency of %v error: %v", table, err)
+		}
+
+		labeled[table], _ = result.RowsAffected()
+	}
+
+	return labeled, nil
+}
@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestUserBalance(t *testing.T) {
+	Convey("Given mysql storage with balances in currencies", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+		s.InsertUserBalance(1, "doge", 100)
+		s.InsertUserBalance(1, "btc", 1)
+
+		Convey("When get user balances", func() {
+			balances, err := s.GetUserBalances(1)
+
+			Convey("Balances should be ordered by currency", func() {
+				So(err, ShouldBeNil)
+				So(len(balances), ShouldEqual, 2)
+				So(balances[0].Currency, ShouldEqual, "btc")
+				So(balances[1].Balance, ShouldEqual, 100)
+			})
+		})
+
+		Convey("When get user balance of currency never earned in", func() {
+			balance, err := s.GetUserBalance(1, "ltc")
+
+			Convey("Balance should be zero", func() {
+				So(err, ShouldBeNil)
+				So(balance, ShouldResemble, models.UserBalance{UserID: 1, Currency: "ltc"})
+			})
+		})
+	})
+
+	Convey("Given mysql storage with rows of single currency deployment", t, func() {
+		s := prepareDatabaseForTesting()
+		s.InsertUserBalance(1, "", 1)
+		s.InsertUserBalance(2, "", 2)
+		s.InsertUserBalance(2, "doge", 100)
+
+		Convey("When label legacy currency", func() {
+			labeled, err := s.LabelLegacyCurrency("btc")
+			liabilities, _ := s.GetLiabilities("btc")
+
+			Convey("Rows with empty currency should be labeled", func() {
+				So(err, ShouldBeNil)
+				So(labeled["user_balances"], ShouldEqual, 2)
+				So(liabilities, ShouldEqual, 3)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get user balances", func(s Storage) error {
+		_, err := s.GetUserBalances(1)
+		return err
+	})
+
+	withClosedConn(t, "When label legacy currency", func(s Storage) error {
+		_, err := s.LabelLegacyCurrency("btc")
+		return err
+	})
+}
@@ -24,7 +24,7 @@ func TestGetUserByID(t *testing.T) {
 
 	Convey("Given mysql storage with user data", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e", Address: "b"})
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
 
 		Convey("When get user by id", func() {
 			user, _ := s.GetUserByID(1)
@@ -64,7 +64,7 @@ func TestGetUserByEmail(t *testing.T) {
 
 	Convey("Given mysql storage with user data", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e", Address: "b"})
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
 
 		Convey("When get user by email", func() {
 			user, _ := s.GetUserByEmail("e")
@@ -92,7 +92,7 @@ func TestCreateUser(t *testing.T) {
 		s := prepareDatabaseForTesting()
 
 		Convey("When create user", func() {
-			err := s.CreateUser(models.User{Email: "e", Address: "b"})
+			err := s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
 
 			Convey("Error should be nil", func() {
 				So(err, ShouldBeNil)
@@ -102,10 +102,10 @@ func TestCreateUser(t *testing.T) {
 
 	Convey("Given mysql storage with user data", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e", Address: "b"})
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
 
 		Convey("When create user with duplicate email", func() {
-			err := s.CreateUser(models.User{Email: "e", Address: ""})
+			err := s.CreateUser(models.User{Email: "e", Address: ""}, "btc")
 
 			Convey("Error should be duplicate email", func() {
 				So(err, ShouldEqual, errors.ErrDuplicatedEmail)
@@ -113,7 +113,7 @@ func TestCreateUser(t *testing.T) {
 		})
 
 		Convey("When create user with duplicate address", func() {
-			err := s.CreateUser(models.User{Email: "", Address: "b"})
+			err := s.CreateUser(models.User{Email: "", Address: "b"}, "btc")
 
 			Convey("Error should be duplicate address", func() {
 				So(err, ShouldEqual, errors.ErrDuplicatedAddress)
@@ -122,14 +122,14 @@ func TestCreateUser(t *testing.T) {
 	})
 
 	withClosedConn(t, "When create user", func(s Storage) error {
-		return s.CreateUser(models.User{Email: "e", Address: "b"})
+		return s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
 	})
 }
 
 func TestUpdateUserStatus(t *testing.T) {
 	Convey("Given mysql storage with user data", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e", Address: "b"})
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
 
 		Convey("When update user's status", func() {
 			err := s.UpdateUserStatus(1, models.UserStatusVerified)
@@ -150,12 +150,99 @@ func TestUpdateUserStatus(t *testing.T) {
 	})
 }
 
+func TestUpdateUserAutoWithdrawal(t *testing.T) {
+	Convey("Given mysql storage with user data", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+
+		Convey("When opt out of auto withdrawal", func() {
+			err := s.UpdateUserAutoWithdrawal(1, false)
+			user, _ := s.GetUserByID(1)
+
+			Convey("Error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("User should not be auto withdrawn", func() {
+				So(user.AutoWithdrawal, ShouldBeFalse)
+			})
+		})
+	})
+
+	withClosedConn(t, "When update user auto withdrawal", func(s Storage) error {
+		return s.UpdateUserAutoWithdrawal(0, false)
+	})
+}
+
+func TestUpdateUserTOTP(t *testing.T) {
+	Convey("Given mysql storage with user data", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+
+		Convey("When enable totp", func() {
+			err := s.UpdateUserTOTP(1, "SECRET", true)
+			user, _ := s.GetUserByID(1)
+
+			Convey("Error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("User should have totp enabled", func() {
+				So(user.TOTPSecret, ShouldEqual, "SECRET")
+				So(user.TOTPEnabled, ShouldBeTrue)
+			})
+		})
+	})
+
+	withClosedConn(t, "When update user totp", func(s Storage) error {
+		return s.UpdateUserTOTP(0, "", false)
+	})
+}
+
+func TestUseTOTPCounter(t *testing.T) {
+	Convey("Given mysql storage with user used totp counter", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+		s.UseTOTPCounter(1, 10)
+
+		Convey("When use same counter again", func() {
+			err := s.UseTOTPCounter(1, 10)
+
+			Convey("Error should be ErrInvalidTOTPCode", func() {
+				So(err, ShouldEqual, errors.ErrInvalidTOTPCode)
+			})
+		})
+
+		Convey("When use earlier counter", func() {
+			err := s.UseTOTPCounter(1, 9)
+
+			Convey("Error should be ErrInvalidTOTPCode", func() {
+				So(err, ShouldEqual, errors.ErrInvalidTOTPCode)
+			})
+		})
+
+		Convey("When use later counter", func() {
+			err := s.UseTOTPCounter(1, 11)
+			user, _ := s.GetUserByID(1)
+
+			Convey("Counter should be updated", func() {
+				So(err, ShouldBeNil)
+				So(user.TOTPCounter, ShouldEqual, 11)
+			})
+		})
+	})
+
+	withClosedConn(t, "When use totp counter", func(s Storage) error {
+		return s.UseTOTPCounter(0, 1)
+	})
+}
+
 func TestGetReferees(t *testing.T) {
 	Convey("Given mysql storage", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e1", Address: "b1"})
-		s.CreateUser(models.User{RefererID: 1, Email: "e2", Address: "b2"})
-		s.CreateUser(models.User{RefererID: 1, Email: "e3", Address: "b3"})
+		s.CreateUser(models.User{Email: "e1", Address: "b1"}, "btc")
+		s.CreateUser(models.User{RefererID: 1, Email: "e2", Address: "b2"}, "btc")
+		s.CreateUser(models.User{RefererID: 1, Email: "e3", Address: "b3"}, "btc")
 
 		Convey("When get referees until 1", func() {
 			result, _ := s.GetReferees(1, 1, 1)
@@ -174,24 +261,154 @@ func TestGetReferees(t *testing.T) {
 	})
 }
 
-func TestGetWithdrawableUser(t *testing.T) {
+func TestGetWithdrawableBalances(t *testing.T) {
 	Convey("Given mysql storage", t, func() {
 		s := prepareDatabaseForTesting()
-		s.db.Exec("INSERT INTO users(email, address, status, balance, min_withdrawal_amount) VALUES('e1', 'b1', 'verified', 10, 5)")
-		s.db.Exec("INSERT INTO users(email, address, status, balance, min_withdrawal_amount) VALUES('e2', 'b2', 'verified', 5, 10)")
-
-		Convey("When get withdrawable users", func() {
-			result, _ := s.GetWithdrawableUsers(6)
-
-			Convey("Users should equal", func() {
+		s.db.Exec("INSERT INTO users(email, address, status) VALUES('e1', 'b1', 'verified')")
+		s.db.Exec("INSERT INTO users(email, address, status) VALUES('e2', 'b2', 'verified')")
+		s.db.Exec("INSERT INTO users(email, address, status, auto_withdrawal) VALUES('e3', 'b3', 'verified', 0)")
+		s.InsertUserBalance(1, "btc", 10)
+		s.InsertUserBalance(1, "doge", 10)
+		s.InsertUserBalance(2, "btc", 5)
+		s.InsertUserBalance(3, "btc", 10)
+
+		Convey("When get withdrawable balances", func() {
+			result, _ := s.GetWithdrawableBalances("btc", 6)
+
+			Convey("Balances should equal", func() {
 				So(result, func(actual interface{}, expected ...interface{}) string {
-					users := actual.([]models.User)
-					if len(users) == 1 && users[0].Email == "e1" {
+					balances := actual.([]models.UserBalance)
+					if len(balances) == 1 && balances[0].UserID == 1 && balances[0].Currency == "btc" {
 						return ""
 					}
-					return fmt.Sprintf("Users %v is not expected", result)
+					return fmt.Sprintf("Balances %v is not expected", result)
 				})
 			})
 		})
 	})
 }
+
+func TestGetUsers(t *testing.T) {
+	Convey("Given mysql storage", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "alice@example.com", Address: "1abc"}, "btc")
+		s.CreateUser(models.User{Email: "bob@example.com", Address: "1abd", RefererID: 1}, "btc")
+		s.CreateUser(models.User{Email: "a_b@example.com", Address: "3xyz", RefererID: 1}, "btc")
+
+		Convey("When get users by address prefix", func() {
+			result, _ := s.GetUsers(models.UserQuery{Address: "1ab"}, 10, 0)
+			count, _ := s.GetNumberOfUsers(models.UserQuery{Address: "1ab"})
+
+			Convey("Users should be matched by prefix, latest first", func() {
+				So(count, ShouldEqual, 2)
+				So(len(result), ShouldEqual, 2)
+				So(result[0].Email, ShouldEqual, "bob@example.com")
+			})
+		})
+
+		Convey("When get users by email with wildcard", func() {
+			result, _ := s.GetUsers(models.UserQuery{Email: "a_"}, 10, 0)
+
+			Convey("Wildcard should be matched literally", func() {
+				So(len(result), ShouldEqual, 1)
+				So(result[0].ID, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When get users by id and referer", func() {
+			result, _ := s.GetUsers(models.UserQuery{ID: 2, RefererID: 1}, 10, 0)
+
+			Convey("Only user 2 should be matched", func() {
+				So(len(result), ShouldEqual, 1)
+				So(result[0].ID, ShouldEqual, 2)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get users", func(s Storage) error {
+		_, err := s.GetUsers(models.UserQuery{}, 10, 0)
+		return err
+	})
+}
+
+func TestBanUser(t *testing.T) {
+	Convey("Given mysql storage with verified user", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+		s.UpdateUserStatus(1, models.UserStatusVerified)
+		s.CreateAuthToken(models.AuthToken{UserID: 1, AuthToken: "token"})
+
+		Convey("When ban non-existing user", func() {
+			err := s.BanUser(2, "spam")
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+
+		Convey("When ban user", func() {
+			err := s.BanUser(1, "spam")
+			user, _ := s.GetUserByID(1)
+			_, tokenErr := s.GetAuthToken("token")
+			logs, _ := s.GetUserStatusLogs(1)
+
+			Convey("User should be banned and logged out with status logged", func() {
+				So(err, ShouldBeNil)
+				So(user.Status, ShouldEqual, models.UserStatusBanned)
+				So(tokenErr, ShouldEqual, errors.ErrNotFound)
+				So(len(logs), ShouldEqual, 1)
+				So(logs[0].FromStatus, ShouldEqual, models.UserStatusVerified)
+				So(logs[0].Reason, ShouldEqual, "spam")
+			})
+
+			Convey("When ban user again", func() {
+				err := s.BanUser(1, "spam")
+
+				Convey("Error should be ErrUserBanned", func() {
+					So(err, ShouldEqual, errors.ErrUserBanned)
+				})
+			})
+
+			Convey("When unban user", func() {
+				err := s.UnbanUser(1, "appealed")
+				user, _ := s.GetUserByID(1)
+				logs, _ := s.GetUserStatusLogs(1)
+
+				Convey("User status should be restored", func() {
+					So(err, ShouldBeNil)
+					So(user.Status, ShouldEqual, models.UserStatusVerified)
+					So(len(logs), ShouldEqual, 2)
+					So(logs[0].Reason, ShouldEqual, "appealed")
+				})
+			})
+		})
+
+		Convey("When unban user not banned", func() {
+			err := s.UnbanUser(1, "appealed")
+
+			Convey("Error should be ErrUserNotBanned", func() {
+				So(err, ShouldEqual, errors.ErrUserNotBanned)
+			})
+		})
+	})
+
+	Convey("Given mysql storage with user banned without log", t, func() {
+		s := prepareDatabaseForTesting()
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+		s.UpdateUserStatus(1, models.UserStatusBanned)
+
+		Convey("When unban user", func() {
+			s.UnbanUser(1, "appealed")
+			user, _ := s.GetUserByID(1)
+
+			Convey("User should be unverified", func() {
+				So(user.Status, ShouldEqual, models.UserStatusUnverified)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get user status logs", func(s Storage) error {
+		_, err := s.GetUserStatusLogs(1)
+		return err
+	})
+}
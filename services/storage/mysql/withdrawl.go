@@ -1,7 +1,9 @@
 package mysql
 
 import (
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/jmoiron/sqlx"
@@ -9,12 +11,13 @@ import (
 	"github.com/solefaucet/sole-server/models"
 )
 
-// CreateWithdrawal creates a new withdrawal
-func (s Storage) CreateWithdrawal(withdrawal models.Withdrawal) error {
+// CreateWithdrawal creates a new withdrawal,
+// user is not allowed to create another one within interval after the latest withdrawal, 0 for no limit
+func (s Storage) CreateWithdrawal(withdrawal models.Withdrawal, interval time.Duration) error {
 	tx := s.db.MustBegin()
 
 	// create withdrawal with transaction
-	if err := createWithdrawal(tx, withdrawal); err != nil {
+	if err := createWithdrawal(tx, withdrawal, interval); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -27,15 +30,42 @@ func (s Storage) CreateWithdrawal(withdrawal models.Withdrawal) error {
 	return nil
 }
 
-func createWithdrawal(tx *sqlx.Tx, withdrawal models.Withdrawal) error {
-	if err := deductUserBalanceBy(tx, withdrawal.UserID, withdrawal.Amount); err != nil {
+func createWithdrawal(tx *sqlx.Tx, withdrawal models.Withdrawal, interval time.Duration) error {
+	if interval > 0 {
+		if err := checkWithdrawalInterval(tx, withdrawal.UserID, interval); err != nil {
+			return err
+		}
+	}
+
+	if err := deductUserBalanceBy(tx, withdrawal.UserID, withdrawal.Currency, withdrawal.Amount); err != nil {
+		return err
+	}
+	return insertWithdrawal(tx, withdrawal)
+}
+
+// lock user so that concurrent requests are serialized, then look for withdrawals within interval,
+// created_at is compared in sql as it is set by mysql
+func checkWithdrawalInterval(tx *sqlx.Tx, userID int64, interval time.Duration) error {
+	if _, err := getUserStatusForUpdate(tx, userID); err != nil {
 		return err
 	}
-	return insertWithdrawal(tx, withdrawal.UserID, withdrawal.Address, withdrawal.Amount)
+
+	var count int64
+	rawSQL := "SELECT COUNT(*) FROM withdrawals WHERE `user_id` = ? AND `created_at` > NOW() - INTERVAL ? SECOND"
+	if err := tx.QueryRowx(rawSQL, userID, int64(interval/time.Second)).Scan(&count); err != nil {
+		return fmt.Errorf("query withdrawals within interval error: %v", err)
+	}
+
+	if count > 0 {
+		return errors.ErrTooManyWithdrawals
+	}
+
+	return nil
 }
 
-func deductUserBalanceBy(tx *sqlx.Tx, userID int64, delta float64) error {
-	result, err := tx.Exec("UPDATE users SET `balance` = `balance` - ? WHERE `id` = ? AND `balance` >= ?", delta, userID, delta)
+func deductUserBalanceBy(tx *sqlx.Tx, userID int64, currency string, delta float64) error {
+	rawSQL := "UPDATE user_balances SET `balance` = `balance` - ? WHERE `user_id` = ? AND `currency` = ? AND `balance` >= ?"
+	result, err := tx.Exec(rawSQL, delta, userID, currency, delta)
 	if err != nil {
 		return fmt.Errorf("deduct user balance error: %v", err)
 	}
@@ -48,9 +78,9 @@ func deductUserBalanceBy(tx *sqlx.Tx, userID int64, delta float64) error {
 	return nil
 }
 
-func insertWithdrawal(tx *sqlx.Tx, userID int64, address string, amount float64) error {
-	rawSQL := "INSERT INTO withdrawals (`user_id`, `address`, `amount`) VALUES (?, ?, ?)"
-	if _, err := tx.Exec(rawSQL, userID, address, amount); err != nil {
+func insertWithdrawal(tx *sqlx.Tx, withdrawal models.Withdrawal) error {
+	rawSQL := "INSERT INTO withdrawals (`user_id`, `currency`, `address`, `amount`, `fee`, `status`, `review_reason`) VALUES (:user_id, :currency, :address, :amount, :fee, :status, :review_reason)"
+	if _, err := tx.NamedExec(rawSQL, withdrawal); err != nil {
 		return fmt.Errorf("create withdrawal error: %v", err)
 	}
 
@@ -73,10 +103,10 @@ func (s Storage) GetNumberOfWithdrawals(userID int64) (int64, error) {
 	return count, err
 }
 
-// GetPendingWithdrawals get all unprocessed withdrawals
-func (s Storage) GetPendingWithdrawals() ([]models.Withdrawal, error) {
-	rawSQL := "SELECT * FROM withdrawals WHERE `status` = ? ORDER BY `id` asc"
-	args := []interface{}{models.WithdrawalStatusPending}
+// GetPendingWithdrawals get all unprocessed withdrawals of currency
+func (s Storage) GetPendingWithdrawals(currency string) ([]models.Withdrawal, error) {
+	rawSQL := "SELECT * FROM withdrawals WHERE `currency` = ? AND `status` = ? ORDER BY `id` asc"
+	args := []interface{}{currency, models.WithdrawalStatusPending}
 	dest := []models.Withdrawal{}
 	err := s.selects(&dest, rawSQL, args...)
 	return dest, err
@@ -111,10 +141,58 @@ func (s Storage) UpdateWithdrawalStatusToProcessing(ids []int64) error {
 	return nil
 }
 
+// UpdatePendingWithdrawalStatusToProcessing updates status of pending withdrawals of currency to processing and returns them,
+// rows are locked in between so that a withdrawal is returned only once
+func (s Storage) UpdatePendingWithdrawalStatusToProcessing(currency string) ([]models.Withdrawal, error) {
+	tx := s.db.MustBegin()
+
+	withdrawals, err := updatePendingWithdrawalStatusToProcessingWithTx(tx, currency)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("update pending withdrawal status to processing commit transaction error: %v", err)
+	}
+
+	return withdrawals, nil
+}
+
+func updatePendingWithdrawalStatusToProcessingWithTx(tx *sqlx.Tx, currency string) ([]models.Withdrawal, error) {
+	withdrawals := []models.Withdrawal{}
+	rawSQL := "SELECT * FROM `withdrawals` WHERE `currency` = ? AND `status` = ? ORDER BY `id` ASC FOR UPDATE"
+	if err := tx.Select(&withdrawals, rawSQL, currency, models.WithdrawalStatusPending); err != nil {
+		return nil, fmt.Errorf("query pending withdrawals error: %v", err)
+	}
+
+	if len(withdrawals) == 0 {
+		return withdrawals, nil
+	}
+
+	ids := make([]int64, len(withdrawals))
+	for i, v := range withdrawals {
+		ids[i] = v.ID
+		withdrawals[i].Status = models.WithdrawalStatusProcessing
+	}
+
+	rawSQL, args, err := sqlx.In("UPDATE `withdrawals` SET `status` = ? WHERE `id` IN (?)", models.WithdrawalStatusProcessing, ids)
+	if err != nil {
+		return nil, fmt.Errorf("update withdrawal status to processing build sql with in: %v", err)
+	}
+
+	if _, err := tx.Exec(rawSQL, args...); err != nil {
+		return nil, fmt.Errorf("update withdrawal status to processing error: %v", err)
+	}
+
+	return withdrawals, nil
+}
+
 // UpdateWithdrawalStatusToProcessed update withdrawal status to processed if status = processing
 func (s Storage) UpdateWithdrawalStatusToProcessed(ids []int64, transactionID string) error {
 	rawSQL, args, err := sqlx.In(
-		"UPDATE `withdrawals` SET `status` = ?, `transaction_id` = ? WHERE `id` IN (?) AND `status` = ?",
+		"UPDATE `withdrawals` SET `status` = ?, `transaction_id` = ?, `processed_at` = NOW() WHERE `id` IN (?) AND `status` = ?",
 		models.WithdrawalStatusProcessed,
 		transactionID,
 		ids,
@@ -140,3 +218,202 @@ func (s Storage) UpdateWithdrawalStatusToProcessed(ids []int64, transactionID st
 
 	return nil
 }
+
+// UpdateWithdrawalStatusToPending puts processing withdrawals known not to be sent back to pending
+func (s Storage) UpdateWithdrawalStatusToPending(ids []int64) error {
+	rawSQL, args, err := sqlx.In(
+		"UPDATE `withdrawals` SET `status` = ? WHERE `id` IN (?) AND `status` = ?",
+		models.WithdrawalStatusPending,
+		ids,
+		models.WithdrawalStatusProcessing,
+	)
+	if err != nil {
+		return fmt.Errorf("update withdrawal status to pending build sql with in: %v", err)
+	}
+
+	result, err := s.db.Exec(rawSQL, args...)
+	if err != nil {
+		return fmt.Errorf("update withdrawal status to pending error: %v", err)
+	}
+
+	if rowAffected, _ := result.RowsAffected(); rowAffected != int64(len(ids)) {
+		return fmt.Errorf("expected %v but %v rows affected", len(ids), rowAffected)
+	}
+
+	return nil
+}
+
+// QuarantineWithdrawals puts processing withdrawals failing payout back to review with reason
+func (s Storage) QuarantineWithdrawals(ids []int64, reason string) error {
+	rawSQL, args, err := sqlx.In(
+		"UPDATE `withdrawals` SET `status` = ?, `review_reason` = ? WHERE `id` IN (?) AND `status` = ?",
+		models.WithdrawalStatusReview,
+		reason,
+		ids,
+		models.WithdrawalStatusProcessing,
+	)
+	if err != nil {
+		return fmt.Errorf("quarantine withdrawals build sql with in: %v", err)
+	}
+
+	result, err := s.db.Exec(rawSQL, args...)
+	if err != nil {
+		return fmt.Errorf("quarantine withdrawals error: %v", err)
+	}
+
+	if rowAffected, _ := result.RowsAffected(); rowAffected != int64(len(ids)) {
+		return fmt.Errorf("expected %v but %v rows affected", len(ids), rowAffected)
+	}
+
+	return nil
+}
+
+// GetUnconfirmedWithdrawals gets processed withdrawals of currency whose transaction has less confirmations than given, oldest first
+func (s Storage) GetUnconfirmedWithdrawals(currency string, confirmations int64) ([]models.Withdrawal, error) {
+	rawSQL := "SELECT * FROM `withdrawals` WHERE `currency` = ? AND `status` = ? AND `transaction_id` != '' AND `confirmations` < ? ORDER BY `id` ASC"
+	args := []interface{}{currency, models.WithdrawalStatusProcessed, confirmations}
+	dest := []models.Withdrawal{}
+	err := s.selects(&dest, rawSQL, args...)
+	return dest, err
+}
+
+// UpdateWithdrawalConfirmations records confirmations, block time and stuck flag of all withdrawals paid in transaction
+func (s Storage) UpdateWithdrawalConfirmations(transactionID string, confirmations int64, blockTime *time.Time, stuck bool) error {
+	rawSQL := "UPDATE `withdrawals` SET `confirmations` = ?, `block_time` = ?, `stuck` = ? WHERE `transaction_id` = ? AND `status` = ?"
+	args := []interface{}{confirmations, blockTime, stuck, transactionID, models.WithdrawalStatusProcessed}
+	if _, err := s.db.Exec(rawSQL, args...); err != nil {
+		return fmt.Errorf("update withdrawal confirmations error: %v", err)
+	}
+
+	return nil
+}
+
+// GetWithdrawalsByStatus gets withdrawals of all users with status given, oldest first
+func (s Storage) GetWithdrawalsByStatus(status int64, limit, offset int64) ([]models.Withdrawal, error) {
+	rawSQL := "SELECT * FROM `withdrawals` WHERE `status` = ? ORDER BY `id` ASC LIMIT ? OFFSET ?"
+	args := []interface{}{status, limit, offset}
+	dest := []models.Withdrawal{}
+	err := s.selects(&dest, rawSQL, args...)
+	return dest, err
+}
+
+// GetNumberOfWithdrawalsByStatus gets number of withdrawals with status given
+func (s Storage) GetNumberOfWithdrawalsByStatus(status int64) (int64, error) {
+	var count int64
+	err := s.db.QueryRowx("SELECT COUNT(*) FROM `withdrawals` WHERE `status` = ?", status).Scan(&count)
+	return count, err
+}
+
+// ApproveWithdrawal releases withdrawal under review to payout job
+func (s Storage) ApproveWithdrawal(id int64) error {
+	tx := s.db.MustBegin()
+
+	if _, err := getWithdrawalForUpdate(tx, id, models.WithdrawalStatusReview, errors.ErrWithdrawalNotInReview); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE `withdrawals` SET `status` = ? WHERE `id` = ?", models.WithdrawalStatusPending, id); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("approve withdrawal error: %v", err)
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("approve withdrawal commit transaction error: %v", err)
+	}
+
+	return nil
+}
+
+// RejectWithdrawal rejects withdrawal under review and refunds its amount to user balance
+func (s Storage) RejectWithdrawal(id int64, reason string) (models.Withdrawal, error) {
+	tx := s.db.MustBegin()
+
+	withdrawal, err := rejectWithdrawalWithTx(tx, id, reason)
+	if err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return withdrawal, fmt.Errorf("reject withdrawal commit transaction error: %v", err)
+	}
+
+	return withdrawal, nil
+}
+
+func rejectWithdrawalWithTx(tx *sqlx.Tx, id int64, reason string) (models.Withdrawal, error) {
+	withdrawal, err := getWithdrawalForUpdate(tx, id, models.WithdrawalStatusReview, errors.ErrWithdrawalNotInReview)
+	if err != nil {
+		return withdrawal, err
+	}
+
+	return refundWithdrawalWithTx(tx, withdrawal, models.WithdrawalStatusRejected, reason)
+}
+
+// FailWithdrawal marks processing withdrawal as failed to pay out and refunds its amount to user balance
+func (s Storage) FailWithdrawal(id int64, reason string) (models.Withdrawal, error) {
+	tx := s.db.MustBegin()
+
+	withdrawal, err := failWithdrawalWithTx(tx, id, reason)
+	if err != nil {
+		tx.Rollback()
+		return withdrawal, err
+	}
+
+	// commit
+	if err := tx.Commit(); err != nil {
+		return withdrawal, fmt.Errorf("fail withdrawal commit transaction error: %v", err)
+	}
+
+	return withdrawal, nil
+}
+
+func failWithdrawalWithTx(tx *sqlx.Tx, id int64, reason string) (models.Withdrawal, error) {
+	withdrawal, err := getWithdrawalForUpdate(tx, id, models.WithdrawalStatusProcessing, errors.ErrWithdrawalNotProcessing)
+	if err != nil {
+		return withdrawal, err
+	}
+
+	return refundWithdrawalWithTx(tx, withdrawal, models.WithdrawalStatusFailed, reason)
+}
+
+// update withdrawal to status with reason and give its amount back to user
+func refundWithdrawalWithTx(tx *sqlx.Tx, withdrawal models.Withdrawal, status int64, reason string) (models.Withdrawal, error) {
+	rawSQL := "UPDATE `withdrawals` SET `status` = ?, `reject_reason` = ? WHERE `id` = ?"
+	if _, err := tx.Exec(rawSQL, status, reason, withdrawal.ID); err != nil {
+		return withdrawal, fmt.Errorf("update withdrawal status to %v error: %v", status, err)
+	}
+	withdrawal.Status = status
+	withdrawal.RejectReason = reason
+
+	// refund balance only, total income is untouched as it was never deducted
+	rawSQL = "UPDATE user_balances SET `balance` = `balance` + ? WHERE `user_id` = ? AND `currency` = ?"
+	if _, err := tx.Exec(rawSQL, withdrawal.Amount, withdrawal.UserID, withdrawal.Currency); err != nil {
+		return withdrawal, fmt.Errorf("refund user balance error: %v", err)
+	}
+
+	return withdrawal, nil
+}
+
+// lock withdrawal, errStatus is returned if withdrawal is not in status expected
+func getWithdrawalForUpdate(tx *sqlx.Tx, id int64, status int64, errStatus error) (models.Withdrawal, error) {
+	withdrawal := models.Withdrawal{}
+	err := tx.Get(&withdrawal, "SELECT * FROM `withdrawals` WHERE `id` = ? FOR UPDATE", id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return withdrawal, errors.ErrNotFound
+		}
+
+		return withdrawal, fmt.Errorf("query withdrawal error: %v", err)
+	}
+
+	if withdrawal.Status != status {
+		return withdrawal, errStatus
+	}
+
+	return withdrawal, nil
+}
@@ -17,7 +17,7 @@ func TestDeductUserBalanceBy(t *testing.T) {
 		Convey("When deduct user balance with commited transaction", func() {
 			tx := s.db.MustBegin()
 			tx.Commit()
-			err := deductUserBalanceBy(tx, 0, 0)
+			err := deductUserBalanceBy(tx, 0, "btc", 0)
 			Convey("Error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -27,7 +27,7 @@ func TestDeductUserBalanceBy(t *testing.T) {
 
 		Convey("When deduct user balance affecting 0 row", func() {
 			tx := s.db.MustBegin()
-			err := deductUserBalanceBy(tx, 0, 0)
+			err := deductUserBalanceBy(tx, 0, "btc", 0)
 			Convey("Error should be insufficient balance", func() {
 				So(err, ShouldEqual, errors.ErrInsufficientBalance)
 			})
@@ -44,7 +44,7 @@ func TestInsertWithdrawal(t *testing.T) {
 		Convey("When insert withdrawal with commited transaction", func() {
 			tx := s.db.MustBegin()
 			tx.Commit()
-			err := insertWithdrawal(tx, 0, "", 0)
+			err := insertWithdrawal(tx, models.Withdrawal{})
 			Convey("Error should not be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -57,19 +57,40 @@ func TestInsertWithdrawal(t *testing.T) {
 func TestCreateWithdrawal(t *testing.T) {
 	Convey("Given empty mysql storage", t, func() {
 		s := prepareDatabaseForTesting()
-		s.CreateUser(models.User{Email: "e", Address: "b"})
-		s.CreateRewardIncome(models.Income{UserID: 1, Income: 10}, time.Now())
+		s.CreateUser(models.User{Email: "e", Address: "b"}, "btc")
+		s.CreateRewardIncome(models.Income{UserID: 1, Currency: "btc", Income: 10}, time.Now())
 
 		Convey("When create withdrawal", func() {
 			err := s.CreateWithdrawal(models.Withdrawal{
-				UserID:  1,
-				Address: "b",
-				Amount:  5,
-			})
+				UserID:   1,
+				Currency: "btc",
+				Address:  "b",
+				Amount:   5,
+				Fee:      0.1,
+			}, 0)
+			withdrawals, _ := s.GetWithdrawals(1, 1, 0)
 
 			Convey("Error should be nil", func() {
 				So(err, ShouldBeNil)
 			})
+
+			Convey("Fee should be recorded", func() {
+				So(withdrawals[0].Fee, ShouldEqual, 0.1)
+			})
+		})
+
+		Convey("When create withdrawals within interval", func() {
+			s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, time.Hour)
+			err := s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, time.Hour)
+			balance, _ := s.GetUserBalance(1, "btc")
+
+			Convey("Error should be ErrTooManyWithdrawals", func() {
+				So(err, ShouldEqual, errors.ErrTooManyWithdrawals)
+			})
+
+			Convey("Balance should be deducted once", func() {
+				So(balance.Balance, ShouldEqual, 9)
+			})
 		})
 	})
 }
@@ -77,10 +98,11 @@ func TestCreateWithdrawal(t *testing.T) {
 func TestGetWithdrawals(t *testing.T) {
 	Convey("Given mysql storage", t, func() {
 		s := prepareDatabaseForTesting()
-		s.db.MustExec("INSERT INTO `users` (email, address, balance) VALUES(?, ?, ?);", "e", "b", 8388607)
-		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Address: "b", Amount: 1})
-		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Address: "b", Amount: 2})
-		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Address: "b", Amount: 3})
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 8388607)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3}, 0)
 
 		Convey("When get withdrawals until now", func() {
 			result, _ := s.GetWithdrawals(1, 2, 1)
@@ -103,13 +125,14 @@ func TestGetWithdrawals(t *testing.T) {
 func TestGetUnprocessedWithdrawals(t *testing.T) {
 	Convey("Given mysql storage", t, func() {
 		s := prepareDatabaseForTesting()
-		s.db.MustExec("INSERT INTO `users` (email, address, balance) VALUES(?, ?, ?);", "e", "b", 8388607)
-		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Address: "b", Amount: 1})
-		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Address: "b", Amount: 2})
-		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Address: "b", Amount: 3})
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 8388607)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3}, 0)
 
 		Convey("When get withdrawals until now", func() {
-			result, _ := s.GetPendingWithdrawals()
+			result, _ := s.GetPendingWithdrawals("btc")
 
 			Convey("Withdrawals should equal", func() {
 				So(result, func(actual interface{}, expected ...interface{}) string {
@@ -125,3 +148,207 @@ func TestGetUnprocessedWithdrawals(t *testing.T) {
 		})
 	})
 }
+
+func TestUpdatePendingWithdrawalStatusToProcessing(t *testing.T) {
+	Convey("Given mysql storage with pending withdrawals", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "alipay", 10)
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "alipay", Address: "a", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "alipay", Address: "a", Amount: 3}, 0)
+
+		Convey("When update pending withdrawal status to processing", func() {
+			withdrawals, err := s.UpdatePendingWithdrawalStatusToProcessing("alipay")
+			again, _ := s.UpdatePendingWithdrawalStatusToProcessing("alipay")
+			pending, _ := s.GetPendingWithdrawals("btc")
+
+			Convey("Pending withdrawals of currency should be returned once", func() {
+				So(err, ShouldBeNil)
+				So(len(withdrawals), ShouldEqual, 2)
+				So(withdrawals[0].ID, ShouldEqual, 1)
+				So(withdrawals[1].ID, ShouldEqual, 3)
+				So(withdrawals[1].Status, ShouldEqual, models.WithdrawalStatusProcessing)
+				So(again, ShouldBeEmpty)
+				So(len(pending), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestReviewWithdrawal(t *testing.T) {
+	Convey("Given mysql storage with withdrawals under review", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1, Status: models.WithdrawalStatusReview, ReviewReason: "amount above 0.5"}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2, Status: models.WithdrawalStatusReview}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3}, 0)
+
+		Convey("When get withdrawals under review", func() {
+			result, _ := s.GetWithdrawalsByStatus(models.WithdrawalStatusReview, 10, 0)
+			count, _ := s.GetNumberOfWithdrawalsByStatus(models.WithdrawalStatusReview)
+
+			Convey("Withdrawals under review should be returned, oldest first", func() {
+				So(count, ShouldEqual, 2)
+				So(len(result), ShouldEqual, 2)
+				So(result[0].ReviewReason, ShouldEqual, "amount above 0.5")
+			})
+		})
+
+		Convey("When approve withdrawal", func() {
+			err := s.ApproveWithdrawal(1)
+			pending, _ := s.GetPendingWithdrawals("btc")
+
+			Convey("Withdrawal should be released to payout", func() {
+				So(err, ShouldBeNil)
+				So(len(pending), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When approve withdrawal not under review", func() {
+			err := s.ApproveWithdrawal(3)
+
+			Convey("Error should be ErrWithdrawalNotInReview", func() {
+				So(err, ShouldEqual, errors.ErrWithdrawalNotInReview)
+			})
+		})
+
+		Convey("When reject non-existing withdrawal", func() {
+			_, err := s.RejectWithdrawal(4, "fraud")
+
+			Convey("Error should be ErrNotFound", func() {
+				So(err, ShouldEqual, errors.ErrNotFound)
+			})
+		})
+
+		Convey("When reject withdrawal", func() {
+			withdrawal, err := s.RejectWithdrawal(2, "fraud")
+			balance, _ := s.GetUserBalance(1, "btc")
+
+			Convey("Withdrawal should be rejected and balance refunded", func() {
+				So(err, ShouldBeNil)
+				So(withdrawal.Status, ShouldEqual, models.WithdrawalStatusRejected)
+				So(withdrawal.RejectReason, ShouldEqual, "fraud")
+				So(balance.Balance, ShouldEqual, 6)
+			})
+		})
+	})
+
+	withClosedConn(t, "When get withdrawals by status", func(s Storage) error {
+		_, err := s.GetWithdrawalsByStatus(models.WithdrawalStatusReview, 10, 0)
+		return err
+	})
+}
+
+func TestQuarantineWithdrawals(t *testing.T) {
+	Convey("Given mysql storage with processing withdrawals", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.UpdateWithdrawalStatusToProcessing([]int64{2})
+
+		Convey("When quarantine pending withdrawal", func() {
+			err := s.QuarantineWithdrawals([]int64{1}, "payout failed")
+
+			Convey("Error should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When quarantine processing withdrawal", func() {
+			err := s.QuarantineWithdrawals([]int64{2}, "payout failed")
+			withdrawals, _ := s.GetWithdrawalsByStatus(models.WithdrawalStatusReview, 10, 0)
+
+			Convey("Withdrawal should be under review with reason", func() {
+				So(err, ShouldBeNil)
+				So(len(withdrawals), ShouldEqual, 1)
+				So(withdrawals[0].ReviewReason, ShouldEqual, "payout failed")
+			})
+		})
+	})
+}
+
+func TestFailWithdrawal(t *testing.T) {
+	Convey("Given mysql storage with processing withdrawal", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.UpdateWithdrawalStatusToProcessing([]int64{2})
+
+		Convey("When fail pending withdrawal", func() {
+			_, err := s.FailWithdrawal(1, "account not found")
+
+			Convey("Error should be ErrWithdrawalNotProcessing", func() {
+				So(err, ShouldEqual, errors.ErrWithdrawalNotProcessing)
+			})
+		})
+
+		Convey("When fail processing withdrawal", func() {
+			withdrawal, err := s.FailWithdrawal(2, "account not found")
+			balance, _ := s.GetUserBalance(1, "btc")
+
+			Convey("Withdrawal should be failed and balance refunded", func() {
+				So(err, ShouldBeNil)
+				So(withdrawal.Status, ShouldEqual, models.WithdrawalStatusFailed)
+				So(withdrawal.RejectReason, ShouldEqual, "account not found")
+				So(balance.Balance, ShouldEqual, 9)
+			})
+		})
+	})
+}
+
+func TestUpdateWithdrawalConfirmations(t *testing.T) {
+	Convey("Given mysql storage with processed withdrawals", t, func() {
+		s := prepareDatabaseForTesting()
+		s.db.MustExec("INSERT INTO `users` (email, address) VALUES(?, ?);", "e", "b")
+		s.InsertUserBalance(1, "btc", 10)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 1}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 2}, 0)
+		s.CreateWithdrawal(models.Withdrawal{UserID: 1, Currency: "btc", Address: "b", Amount: 3}, 0)
+		s.UpdateWithdrawalStatusToProcessing([]int64{1, 2})
+		s.UpdateWithdrawalStatusToProcessed([]int64{1, 2}, "tx")
+
+		Convey("When get unconfirmed withdrawals", func() {
+			withdrawals, err := s.GetUnconfirmedWithdrawals("btc", 6)
+
+			Convey("Processed withdrawals should be returned with time sent", func() {
+				So(err, ShouldBeNil)
+				So(len(withdrawals), ShouldEqual, 2)
+				So(withdrawals[0].ProcessedAt, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When update withdrawal confirmations", func() {
+			blockTime := time.Now().Truncate(time.Second)
+			err := s.UpdateWithdrawalConfirmations("tx", 6, &blockTime, false)
+			withdrawals, _ := s.GetWithdrawals(1, 10, 0)
+			unconfirmed, _ := s.GetUnconfirmedWithdrawals("btc", 6)
+
+			Convey("Withdrawals of transaction should be confirmed", func() {
+				So(err, ShouldBeNil)
+				So(withdrawals[1].Confirmations, ShouldEqual, 6)
+				So(withdrawals[1].BlockTime.Unix(), ShouldEqual, blockTime.Unix())
+				So(withdrawals[2].Confirmations, ShouldEqual, 6)
+				So(withdrawals[0].Confirmations, ShouldEqual, 0)
+				So(unconfirmed, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When flag withdrawals stuck", func() {
+			err := s.UpdateWithdrawalConfirmations("tx", 0, nil, true)
+			withdrawals, _ := s.GetUnconfirmedWithdrawals("btc", 6)
+
+			Convey("Withdrawals should be stuck", func() {
+				So(err, ShouldBeNil)
+				So(withdrawals[0].Stuck, ShouldBeTrue)
+				So(withdrawals[0].BlockTime, ShouldBeNil)
+			})
+		})
+	})
+}
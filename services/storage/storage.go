@@ -11,11 +11,48 @@ type Storage interface {
 	// User
 	GetUserByID(int64) (models.User, error)
 	GetUserByEmail(string) (models.User, error)
-	CreateUser(models.User) error
+	CreateUser(u models.User, currency string) error
 	UpdateUserStatus(int64, string) error
+	UpdateUserAutoWithdrawal(id int64, autoWithdrawal bool) error
+	UpdateUserCurrency(id int64, currency string) error
+	UpdateUserTOTP(id int64, secret string, enabled bool) error
+	UseTOTPCounter(id int64, counter int64) error
 	GetReferees(userID int64, limit, offset int64) ([]models.User, error)
 	GetNumberOfReferees(userID int64) (int64, error)
-	GetWithdrawableUsers(minAmount float64) ([]models.User, error)
+	GetUsers(q models.UserQuery, limit, offset int64) ([]models.User, error)
+	GetNumberOfUsers(q models.UserQuery) (int64, error)
+	BanUser(id int64, reason string) error
+	UnbanUser(id int64, reason string) error
+	GetUserStatusLogs(userID int64) ([]models.UserStatusLog, error)
+
+	// UserBalance
+	GetUserBalances(userID int64) ([]models.UserBalance, error)
+	GetUserBalance(userID int64, currency string) (models.UserBalance, error)
+	GetWithdrawableBalances(currency string, minAmount float64) ([]models.UserBalance, error)
+	LabelLegacyCurrency(currency string) (map[string]int64, error)
+
+	// UserAddress
+	GetUserAddresses(userID int64) ([]models.UserAddress, error)
+	GetDefaultUserAddress(userID int64, currency string) (models.UserAddress, error)
+	CreateUserAddress(models.UserAddress) (int64, error)
+	VerifyUserAddress(userID, id int64, token string) error
+	SetDefaultUserAddress(userID, id int64, baseCurrency string) error
+	DeleteUserAddress(userID, id int64) error
+
+	// Role
+	GetRoles() ([]models.Role, error)
+	GetRolePermissions() (models.RolePermissions, error)
+	UpdateUserRole(userID int64, role string) error
+	CreateAdminAuditLog(models.AdminAuditLog) error
+	GetAdminAuditLogs(limit, offset int64) ([]models.AdminAuditLog, error)
+	GetNumberOfAdminAuditLogs() (int64, error)
+
+	// ReferralCampaign
+	CreateReferralCampaign(models.ReferralCampaign) (int64, error)
+	GetReferralCampaignByCode(code string) (models.ReferralCampaign, error)
+	GetReferralCampaigns(userID int64, currency string, limit, offset int64) ([]models.ReferralCampaign, error)
+	GetNumberOfReferralCampaigns(userID int64) (int64, error)
+	IncrementReferralCampaignClicks(code string) error
 
 	// AuthToken
 	GetAuthToken(string) (models.AuthToken, error)
@@ -27,29 +64,94 @@ type Storage interface {
 	UpsertSession(models.Session) error
 
 	// TotalReward
-	GetLatestTotalReward() (models.TotalReward, error)
+	GetLatestTotalReward(currency string) (models.TotalReward, error)
+	GetTotalRewards(currency string, since, until time.Time) ([]models.TotalReward, error)
+
+	// Stats
+	GetUserStats(since time.Time) (models.UserStats, error)
+	GetLiabilities(currency string) (float64, error)
+	GetWithdrawalStats(currency string) ([]models.WithdrawalStats, error)
+	GetPaidOutAmount(currency string, since time.Time) (float64, error)
+	GetOfferwallRevenues(since, until time.Time) ([]models.OfferwallRevenue, error)
 
 	// RewardRate
 	GetRewardRatesByType(string) ([]models.RewardRate, error)
+	GetScopedRewardRates() ([]models.RewardRate, error)
+	GetCountryGroups() ([]models.CountryGroup, error)
+	GetAllRewardRates() ([]models.RewardRate, error)
+	CreateRewardRate(models.RewardRate) error
+	UpdateRewardRate(models.RewardRate) error
+	DeleteRewardRate(id int64) error
+
+	// Level
+	GetLevels() (models.Levels, error)
+
+	// StreakBonus
+	GetStreakBonuses() (models.StreakBonuses, error)
+
+	// Achievement
+	GetAchievements() (models.Achievements, error)
+	GetUserAchievements(userID int64) ([]models.UserAchievement, error)
+	GetAchievementStats(userID int64) (models.AchievementStats, error)
+	UnlockAchievement(userID int64, achievement models.Achievement, currency string) (bool, error)
+
+	// FairSeed
+	GetActiveFairSeed(userID int64) (models.FairSeed, error)
+	RotateFairSeed(models.FairSeed) (models.FairSeed, error)
+	GetFairRoll(serverSeedHash string, nonce int64) (models.FairRoll, error)
+
+	// RewardRule
+	GetRewardRules() (models.RewardRules, error)
 
 	// Config
 	GetLatestConfig() (models.Config, error)
+	GetConfigs(limit, offset int64) ([]models.Config, error)
+	GetNumberOfConfigs() (int64, error)
+	CreateConfig(models.Config) (int64, error)
 
 	// Income
 	CreateRewardIncome(models.Income, time.Time) error
+	CreateFairRewardIncome(models.Income, time.Time, models.FairRoll) error
 	GetRewardIncomes(userID int64, limit, offset int64) ([]models.Income, error)
 	GetNumberOfRewardIncomes(userID int64) (int64, error)
 	GetOfferwallIncomes(userID int64, limit, offset int64) ([]models.Income, error)
 	GetNumberOfOfferwallIncomes(userID int64) (int64, error)
 	ChargebackIncome(incomeID int64) error
+	GetRefereeIncomes(q models.RefereeIncomeQuery, limit, offset int64) ([]models.RefereeIncome, error)
+	GetNumberOfRefereeIncomes(q models.RefereeIncomeQuery) (int64, error)
+	GetIncomes(userID int64, limit, offset int64) ([]models.Income, error)
+	GetNumberOfIncomes(userID int64) (int64, error)
+
+	// AdminAdjustment
+	CreateAdminAdjustment(models.AdminAdjustment) error
+	GetAdminAdjustments(userID int64) ([]models.AdminAdjustment, error)
 
 	// Withdrawal
-	CreateWithdrawal(models.Withdrawal) error
+	CreateWithdrawal(withdrawal models.Withdrawal, interval time.Duration) error
 	GetWithdrawals(userID int64, limit, offset int64) ([]models.Withdrawal, error)
 	GetNumberOfWithdrawals(userID int64) (int64, error)
-	GetPendingWithdrawals() ([]models.Withdrawal, error)
+	GetPendingWithdrawals(currency string) ([]models.Withdrawal, error)
 	UpdateWithdrawalStatusToProcessing(ids []int64) error
+	UpdatePendingWithdrawalStatusToProcessing(currency string) ([]models.Withdrawal, error)
 	UpdateWithdrawalStatusToProcessed(ids []int64, transactionID string) error
+	UpdateWithdrawalStatusToPending(ids []int64) error
+	QuarantineWithdrawals(ids []int64, reason string) error
+	GetUnconfirmedWithdrawals(currency string, confirmations int64) ([]models.Withdrawal, error)
+	UpdateWithdrawalConfirmations(transactionID string, confirmations int64, blockTime *time.Time, stuck bool) error
+	GetWithdrawalsByStatus(status int64, limit, offset int64) ([]models.Withdrawal, error)
+	GetNumberOfWithdrawalsByStatus(status int64) (int64, error)
+	ApproveWithdrawal(id int64) error
+	RejectWithdrawal(id int64, reason string) (models.Withdrawal, error)
+	FailWithdrawal(id int64, reason string) (models.Withdrawal, error)
+
+	// PayoutBatch
+	CreatePayoutBatch(batchID string, withdrawalIDs []int64) error
+	UpdatePayoutBatchStatus(batchID string, status int64) error
+	GetUnfinishedPayoutBatches(currency string) ([]models.PayoutBatch, error)
+	GetProcessingWithdrawalsByPayoutBatch(batchID string) ([]models.Withdrawal, error)
+	GetPayoutBatchesByStatus(status int64, limit, offset int64) ([]models.PayoutBatch, error)
+	GetNumberOfPayoutBatchesByStatus(status int64) (int64, error)
+	ResolvePayoutBatchWithdrawal(batchID string, id int64, status int64, transactionID, reason string) (models.Withdrawal, error)
 
 	// Superrewards
 	GetNumberOfSuperrewardsOffers(transactionID string, userID int64) (int64, error)
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+// FairReward generates a provably fair reward with rates given,
+// rate bucket and amount are picked by HMAC-SHA256(serverSeed, clientSeed:nonce)
+// so that anyone can reproduce the reward once server seed is revealed
+func FairReward(rates []models.RewardRate, serverSeed, clientSeed string, nonce int64) float64 {
+	mac := hmac.New(sha256.New, []byte(serverSeed))
+	fmt.Fprintf(mac, "%s:%d", clientSeed, nonce)
+	sum := mac.Sum(nil)
+
+	// first 8 bytes pick the bucket, next 8 bytes pick the amount
+	rolls := []uint64{binary.BigEndian.Uint64(sum[0:8]), binary.BigEndian.Uint64(sum[8:16])}
+	return pickReward(rates, func(min, max int64) int64 {
+		roll := rolls[0]
+		rolls = rolls[1:]
+		return min + int64(roll%uint64(max-min))
+	})
+}
+
+// NewServerSeed generates a random server seed in hex
+func NewServerSeed() string {
+	b := make([]byte, 32)
+	// panic if rand.Read returns error, fail fast here
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// HashServerSeed returns sha256 of server seed in hex
+func HashServerSeed(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
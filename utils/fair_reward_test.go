@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/solefaucet/sole-server/models"
+)
+
+func TestFairReward(t *testing.T) {
+	rates := []models.RewardRate{
+		{Min: 0, Max: 10, Weight: 1},
+		{Min: 10, Max: 20, Weight: 1},
+	}
+
+	reward := FairReward(rates, "server", "client", 1)
+	if reward >= 20 || reward < 0 {
+		t.Errorf("reward should be [0, 20) but get %v", reward)
+	}
+
+	if r := FairReward(rates, "server", "client", 1); r != reward {
+		t.Errorf("reward should be reproducible, expected %v but get %v", reward, r)
+	}
+
+	rewards := map[float64]bool{}
+	for nonce := int64(0); nonce < 10; nonce++ {
+		rewards[FairReward(rates, "server", "client", nonce)] = true
+	}
+	if len(rewards) < 2 {
+		t.Errorf("reward should vary with nonce but get %v", rewards)
+	}
+
+	fixed := []models.RewardRate{{Min: 5, Max: 5, Weight: 1}}
+	if r := FairReward(fixed, "server", "client", 1); r != 5 {
+		t.Errorf("reward of fixed rate should be 5 but get %v", r)
+	}
+}
+
+func TestServerSeed(t *testing.T) {
+	seed := NewServerSeed()
+	if len(seed) != 64 {
+		t.Errorf("length of server seed should be 64 but get %v", len(seed))
+	}
+
+	if seed == NewServerSeed() {
+		t.Error("server seeds should be random")
+	}
+
+	// echo -n "" | sha256sum
+	if hash := HashServerSeed(""); hash != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("unexpected hash %v", hash)
+	}
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	testdata := map[string]string{
+		"":    "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		"abc": "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45",
+		// longer than one block of 136 bytes
+		strings.Repeat("a", 200): "96ea54061def936c4be90b518992fdc6f12f535068a256229aca54267b4d084d",
+	}
+
+	for input, expected := range testdata {
+		if digest := hex.EncodeToString(Keccak256([]byte(input))); digest != expected {
+			t.Errorf("keccak256 of %q should be %v but get %v", input, expected, digest)
+		}
+	}
+}
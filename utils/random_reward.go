@@ -10,6 +10,12 @@ import (
 
 // RandomReward generates a random reward with rates given
 func RandomReward(rates []models.RewardRate) float64 {
+	return pickReward(rates, randInt64)
+}
+
+// pick a rate bucket by weight and an amount in [min, max) of the bucket,
+// randInt should return an integer in [min, max)
+func pickReward(rates []models.RewardRate, randInt func(min, max int64) int64) float64 {
 	var sum int64
 	for i := range rates {
 		sum += rates[i].Weight
@@ -19,7 +25,7 @@ func RandomReward(rates []models.RewardRate) float64 {
 	}
 
 	i := 0
-	for r := randInt64(0, sum); i < len(rates); i++ {
+	for r := randInt(0, sum); i < len(rates); i++ {
 		r -= rates[i].Weight
 		if r < 0 {
 			break
@@ -30,7 +36,11 @@ func RandomReward(rates []models.RewardRate) float64 {
 	pow8 := math.Pow(10, 8)
 	min := int64(rate.Min * pow8)
 	max := int64(rate.Max * pow8)
-	return float64(randInt64(min, max)) / pow8
+	if max <= min {
+		// fixed amount, randInt panics on empty range
+		return rate.Min
+	}
+	return float64(randInt(min, max)) / pow8
 }
 
 func randInt64(min, max int64) int64 {
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	totpModulo = 1000000 // 10^totpDigits
+	totpSkew   = 1       // number of periods before and after now accepted to tolerate clock drift
+)
+
+// NewTOTPSecret generates a random base32 encoded TOTP secret
+func NewTOTPSecret() string {
+	b := make([]byte, 20)
+	// panic if rand.Read returns error, fail fast here
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base32.StdEncoding.EncodeToString(b)
+}
+
+// TOTPURL returns otpauth url of secret that authenticator apps scan as qr code
+func TOTPURL(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + issuer + ":" + account, RawQuery: v.Encode()}
+	return u.String()
+}
+
+// TOTPCode returns code of secret at time t as described in RFC 6238
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(t.Unix()/totpPeriod)), nil
+}
+
+// ValidateTOTP checks code against secret at time t and returns counter of the period matched,
+// codes of adjacent periods are accepted as well
+func ValidateTOTP(secret, code string, t time.Time) (int64, bool) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	counter := t.Unix() / totpPeriod
+	for i := counter - totpSkew; i <= counter+totpSkew; i++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, uint64(i))), []byte(code)) == 1 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	key, err := base32.StdEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return nil, fmt.Errorf("decode totp secret error: %v", err)
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("empty totp secret")
+	}
+	return key, nil
+}
+
+// HOTP value of counter as described in RFC 4226
+func hotp(key []byte, counter uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulo)
+}
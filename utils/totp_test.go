@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	// test vectors of RFC 6238 appendix B, SHA1 with last 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, v := range vectors {
+		if counter, ok := ValidateTOTP(secret, v.code, time.Unix(v.unix, 0)); !ok || counter != v.unix/30 {
+			t.Errorf("code %v should be valid at %v with counter %v but get %v", v.code, v.unix, v.unix/30, counter)
+		}
+	}
+
+	now := time.Unix(1234567890, 0)
+	if counter, ok := ValidateTOTP(secret, "005924", now.Add(30*time.Second)); !ok || counter != 1234567890/30 {
+		t.Errorf("code of previous period should be valid with its counter but get %v", counter)
+	}
+	if _, ok := ValidateTOTP(secret, "005924", now.Add(90*time.Second)); ok {
+		t.Error("code of expired period should be invalid")
+	}
+	if _, ok := ValidateTOTP(secret, "5924", now); ok {
+		t.Error("code with wrong length should be invalid")
+	}
+	if _, ok := ValidateTOTP("not base32!", "005924", now); ok {
+		t.Error("code with invalid secret should be invalid")
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	if code, err := TOTPCode(secret, time.Unix(1111111109, 0)); err != nil || code != "081804" {
+		t.Errorf("code should be 081804 but get %v, %v", code, err)
+	}
+
+	if _, err := TOTPCode("", time.Now()); err == nil {
+		t.Error("error should not be nil with empty secret")
+	}
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret := NewTOTPSecret()
+	if len(secret) != 32 {
+		t.Errorf("length of secret should be 32 but get %v", len(secret))
+	}
+
+	if secret == NewTOTPSecret() {
+		t.Error("secrets should be random")
+	}
+}
+
+func TestTOTPURL(t *testing.T) {
+	expected := "otpauth://totp/sole:foo@bar.com?issuer=sole&secret=ABC"
+	if u := TOTPURL("sole", "foo@bar.com", "ABC"); u != expected {
+		t.Errorf("url should be %v but get %v", expected, u)
+	}
+}